@@ -0,0 +1,29 @@
+-- one row per year, holding the last invoice number issued in it. Numbers are taken
+-- inside the transaction that inserts the invoice, so a rolled back invoice frees its number.
+CREATE TABLE invoice_sequences (
+  year integer PRIMARY KEY,
+  last_number integer NOT NULL
+);
+
+CREATE TABLE invoices (
+  id bigserial PRIMARY KEY,
+  number varchar NOT NULL UNIQUE,
+  order_id integer NOT NULL UNIQUE,
+  first_name varchar NOT NULL,
+  last_name varchar NOT NULL,
+  email varchar NOT NULL,
+  product varchar NOT NULL,
+  quantity integer NOT NULL,
+  subtotal integer NOT NULL,
+  tax integer NOT NULL DEFAULT 0,
+  total integer NOT NULL,
+  status varchar NOT NULL DEFAULT 'issued' CHECK (status IN ('issued', 'sent', 'void')),
+  pdf_location varchar NOT NULL DEFAULT '',
+  issued_at timestamptz NOT NULL,
+  due_at timestamptz NOT NULL,
+  sent_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT (now()),
+  updated_at timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX invoices_email_idx ON invoices (email);
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS invoices_email_idx;
+CREATE INDEX invoices_email_idx ON invoices (email);
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS void_reason;
+ALTER TABLE invoices DROP COLUMN IF EXISTS voided_at;
\ No newline at end of file
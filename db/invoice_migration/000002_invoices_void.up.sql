@@ -0,0 +1,7 @@
+ALTER TABLE invoices
+    ADD COLUMN voided_at timestamptz,
+    ADD COLUMN void_reason VARCHAR NOT NULL DEFAULT '';
+
+-- invoices are looked up by email case-insensitively
+DROP INDEX IF EXISTS invoices_email_idx;
+CREATE INDEX invoices_email_idx ON invoices (lower(email));
\ No newline at end of file
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS invoice_line_items;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS last_four;
+ALTER TABLE invoices DROP COLUMN IF EXISTS card_brand;
+ALTER TABLE invoices DROP COLUMN IF EXISTS tax_rate_bps;
+ALTER TABLE invoices DROP COLUMN IF EXISTS shipping;
+ALTER TABLE invoices DROP COLUMN IF EXISTS discount_label;
+ALTER TABLE invoices DROP COLUMN IF EXISTS discount;
+ALTER TABLE invoices DROP COLUMN IF EXISTS currency;
\ No newline at end of file
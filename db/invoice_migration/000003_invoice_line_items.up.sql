@@ -0,0 +1,24 @@
+ALTER TABLE invoices
+    ADD COLUMN currency VARCHAR NOT NULL DEFAULT 'usd',
+    ADD COLUMN discount integer NOT NULL DEFAULT 0,
+    ADD COLUMN discount_label VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN shipping integer NOT NULL DEFAULT 0,
+    ADD COLUMN tax_rate_bps integer NOT NULL DEFAULT 0,
+    ADD COLUMN card_brand VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN last_four VARCHAR NOT NULL DEFAULT '';
+
+CREATE TABLE invoice_line_items (
+  id bigserial PRIMARY KEY,
+  invoice_id bigint NOT NULL REFERENCES invoices (id) ON DELETE CASCADE,
+  position integer NOT NULL,
+  description varchar NOT NULL,
+  quantity integer NOT NULL,
+  unit_price integer NOT NULL,
+  amount integer NOT NULL,
+  UNIQUE (invoice_id, position)
+);
+
+-- invoices issued before line items existed had a single product line
+INSERT INTO invoice_line_items (invoice_id, position, description, quantity, unit_price, amount)
+SELECT id, 1, product, quantity, subtotal / greatest(quantity, 1), subtotal
+FROM invoices;
\ No newline at end of file
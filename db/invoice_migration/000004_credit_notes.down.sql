@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS credit_notes;
+DROP TABLE IF EXISTS credit_note_sequences;
\ No newline at end of file
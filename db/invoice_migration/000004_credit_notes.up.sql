@@ -0,0 +1,25 @@
+-- credit notes are numbered like invoices, in their own gap-free sequence per year
+CREATE TABLE credit_note_sequences (
+  year integer PRIMARY KEY,
+  last_number integer NOT NULL
+);
+
+CREATE TABLE credit_notes (
+  id bigserial PRIMARY KEY,
+  number varchar NOT NULL UNIQUE,
+  invoice_id bigint NOT NULL REFERENCES invoices (id),
+  -- identifies what was credited, e.g. the Stripe refund id, so a retried request
+  -- returns the existing credit note instead of issuing another one
+  reference varchar NOT NULL UNIQUE,
+  amount integer NOT NULL CHECK (amount > 0),
+  currency varchar NOT NULL,
+  reason varchar NOT NULL DEFAULT '',
+  status varchar NOT NULL DEFAULT 'issued' CHECK (status IN ('issued', 'sent')),
+  pdf_location varchar NOT NULL DEFAULT '',
+  issued_at timestamptz NOT NULL,
+  sent_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT (now()),
+  updated_at timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX credit_notes_invoice_id_idx ON credit_notes (invoice_id);
\ No newline at end of file
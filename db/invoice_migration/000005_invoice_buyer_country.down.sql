@@ -0,0 +1 @@
+ALTER TABLE invoices DROP COLUMN IF EXISTS buyer_country;
\ No newline at end of file
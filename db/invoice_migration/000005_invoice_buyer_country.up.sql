@@ -0,0 +1,2 @@
+-- ISO 3166-1 alpha-2 country of the buyer, required in structured e-invoices
+ALTER TABLE invoices ADD COLUMN buyer_country VARCHAR NOT NULL DEFAULT '';
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE invoices DROP COLUMN IF EXISTS locale;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- language the invoice, its credit notes and their emails are written in
+ALTER TABLE invoices ADD COLUMN locale VARCHAR NOT NULL DEFAULT 'en';
\ No newline at end of file
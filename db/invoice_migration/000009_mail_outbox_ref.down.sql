@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS mail_outbox_ref_idx;
+
+ALTER TABLE mail_outbox DROP COLUMN IF EXISTS ref;
\ No newline at end of file
@@ -0,0 +1,17 @@
+CREATE TABLE "password_resets" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "token_hash" bytea NOT NULL,
+  "expiry" timestamptz NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE password_resets
+  ADD CONSTRAINT fk_password_resets_user_id
+  FOREIGN KEY (user_id)
+  REFERENCES users(id)
+  ON DELETE CASCADE
+  ON UPDATE CASCADE;
+
+CREATE UNIQUE INDEX password_resets_token_hash_idx ON password_resets (token_hash);
\ No newline at end of file
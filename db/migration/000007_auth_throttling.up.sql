@@ -0,0 +1,22 @@
+CREATE TABLE "auth_attempts" (
+  "id" bigserial PRIMARY KEY,
+  "action" varchar NOT NULL,
+  "key" varchar NOT NULL,
+  "failures" integer NOT NULL DEFAULT 0,
+  "last_failure_at" timestamptz NOT NULL DEFAULT (now()),
+  "locked_until" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX auth_attempts_action_key_idx ON auth_attempts (action, key);
+
+CREATE TABLE "auth_lockouts" (
+  "id" bigserial PRIMARY KEY,
+  "action" varchar NOT NULL,
+  "key" varchar NOT NULL,
+  "ip" varchar NOT NULL,
+  "failures" integer NOT NULL,
+  "locked_until" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX auth_lockouts_created_at_idx ON auth_lockouts (created_at);
\ No newline at end of file
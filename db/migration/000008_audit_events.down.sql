@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS audit_events_no_change ON audit_events;
+DROP FUNCTION IF EXISTS audit_events_append_only();
+DROP TABLE IF EXISTS audit_events;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ADD COLUMN oidc_subject VARCHAR,
+    ADD COLUMN role VARCHAR NOT NULL DEFAULT 'admin';
+
+CREATE UNIQUE INDEX users_oidc_subject_idx ON users (oidc_subject);
\ No newline at end of file
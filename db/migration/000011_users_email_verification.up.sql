@@ -0,0 +1,6 @@
+ALTER TABLE users
+    ADD COLUMN email_verified_at timestamptz,
+    ADD COLUMN pending_email VARCHAR;
+
+-- existing accounts were in use before verification existed, so treat them as verified
+UPDATE users SET email_verified_at = now();
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS invoice_outbox;
\ No newline at end of file
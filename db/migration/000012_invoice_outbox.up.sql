@@ -0,0 +1,20 @@
+CREATE TABLE "invoice_outbox" (
+  "id" bigserial PRIMARY KEY,
+  "order_id" bigint NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+  "attempts" integer NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "last_error" text NOT NULL DEFAULT '',
+  "invoice_number" varchar NOT NULL DEFAULT '',
+  "delivered_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE invoice_outbox
+  ADD CONSTRAINT fk_invoice_outbox_order_id
+  FOREIGN KEY (order_id) REFERENCES orders(id);
+
+CREATE UNIQUE INDEX invoice_outbox_order_id_idx ON invoice_outbox (order_id);
+CREATE INDEX invoice_outbox_due_idx ON invoice_outbox (next_attempt_at) WHERE status = 'pending';
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS locale;
+ALTER TABLE customers DROP COLUMN IF EXISTS locale;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- BCP 47 language of the customer, used to localize their invoices and emails
+ALTER TABLE customers ADD COLUMN locale VARCHAR NOT NULL DEFAULT 'en';
+ALTER TABLE orders ADD COLUMN locale VARCHAR NOT NULL DEFAULT 'en';
\ No newline at end of file
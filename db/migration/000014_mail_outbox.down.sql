@@ -0,0 +1 @@
+DROP TABLE IF EXISTS mail_outbox;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE "email_template_versions" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "locale" varchar NOT NULL DEFAULT '',
+  "version" integer NOT NULL,
+  "html" text NOT NULL DEFAULT '',
+  "plain" text NOT NULL DEFAULT '',
+  "uses_default" boolean NOT NULL DEFAULT false,
+  "created_by" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("name", "locale", "version")
+);
\ No newline at end of file
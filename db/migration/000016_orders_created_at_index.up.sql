@@ -0,0 +1 @@
+CREATE INDEX orders_created_at_idx ON orders (created_at);
\ No newline at end of file
@@ -0,0 +1,4 @@
+UPDATE items SET image = '/static/yoyo4.png' WHERE image = '/static/yoyo_4.png';
+
+ALTER TABLE items DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE items DROP COLUMN IF EXISTS stripe_product_id;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- the Stripe product a recurring item's plan (a price) belongs to
+ALTER TABLE items ADD COLUMN stripe_product_id VARCHAR NOT NULL DEFAULT '';
+-- archived items can no longer be bought, but stay on the orders that were placed for them
+ALTER TABLE items ADD COLUMN archived_at TIMESTAMPTZ;
+
+-- the seeded yoyo pointed at an image that doesn't exist
+UPDATE items SET image = '/static/yoyo_4.png' WHERE image = '/static/yoyo4.png';
\ No newline at end of file
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS items_search_idx;
+ALTER TABLE items DROP COLUMN IF EXISTS search;
+DROP INDEX IF EXISTS items_category_idx;
+DROP INDEX IF EXISTS items_slug_idx;
+ALTER TABLE items DROP COLUMN IF EXISTS category;
+ALTER TABLE items DROP COLUMN IF EXISTS slug;
\ No newline at end of file
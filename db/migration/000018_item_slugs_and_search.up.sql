@@ -0,0 +1,28 @@
+-- items are linked to by their slug, made from the name when none is given
+ALTER TABLE items ADD COLUMN slug VARCHAR;
+ALTER TABLE items ADD COLUMN category VARCHAR NOT NULL DEFAULT '';
+
+-- existing items get a slug from their name, with their id appended when two names make the same one
+WITH slugs AS (
+  SELECT
+    id,
+    coalesce(nullif(trim(BOTH '-' FROM regexp_replace(lower(name), '[^a-z0-9]+', '-', 'g')), ''), 'item') AS base,
+    row_number() OVER (PARTITION BY coalesce(nullif(trim(BOTH '-' FROM regexp_replace(lower(name), '[^a-z0-9]+', '-', 'g')), ''), 'item') ORDER BY id) AS n
+  FROM items
+)
+UPDATE items SET slug = CASE WHEN slugs.n = 1 THEN slugs.base ELSE slugs.base || '-' || items.id END
+FROM slugs
+WHERE slugs.id = items.id;
+
+ALTER TABLE items ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX items_slug_idx ON items (slug);
+
+UPDATE items SET category = CASE WHEN is_recurring THEN 'Subscriptions' ELSE 'Yoyos' END;
+CREATE INDEX items_category_idx ON items (category);
+
+-- the storefront searches names first, then descriptions
+ALTER TABLE items ADD COLUMN search TSVECTOR GENERATED ALWAYS AS (
+  setweight(to_tsvector('english', name), 'A') ||
+  setweight(to_tsvector('english', coalesce(description, '')), 'B')
+) STORED;
+CREATE INDEX items_search_idx ON items USING GIN (search);
\ No newline at end of file
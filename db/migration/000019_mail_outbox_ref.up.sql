@@ -0,0 +1,4 @@
+-- what a message is about, e.g. invoice:12, so the service that queued it can tell when it was delivered
+ALTER TABLE mail_outbox ADD COLUMN ref varchar NOT NULL DEFAULT '';
+
+CREATE INDEX mail_outbox_ref_idx ON mail_outbox (ref) WHERE ref <> '';
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS audit_events_actor_api_key_id_idx;
+
+ALTER TABLE audit_events DROP COLUMN IF EXISTS actor_api_key_id;
\ No newline at end of file
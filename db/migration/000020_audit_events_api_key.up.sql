@@ -0,0 +1,4 @@
+-- the API key that made a change, for changes not made by a user
+ALTER TABLE audit_events ADD COLUMN actor_api_key_id bigint;
+
+CREATE INDEX audit_events_actor_api_key_id_idx ON audit_events (actor_api_key_id);
\ No newline at end of file
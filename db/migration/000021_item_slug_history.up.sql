@@ -0,0 +1,8 @@
+-- the slugs items had before, so that links to them keep working
+CREATE TABLE "item_slug_history" (
+  "slug" varchar PRIMARY KEY,
+  "item_id" bigint NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX item_slug_history_item_id_idx ON item_slug_history (item_id);
\ No newline at end of file
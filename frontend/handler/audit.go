@@ -0,0 +1,14 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+)
+
+// AuditLog shows the admin audit log page
+func (server *Server) AuditLog(w http.ResponseWriter, r *http.Request) {
+	if err := server.renderTemplate(w, r, "audit", &templateData{}); err != nil {
+		log.Error().Err(err).Msg("AuditLog")
+	}
+}
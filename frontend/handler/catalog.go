@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// catalogPageSize is how many items a page of the catalog shows
+	catalogPageSize = 12
+	// homePageItems is how many items the home page shows
+	homePageItems = 6
+	// bronzePlanID is the item the old bronze plan page sold
+	bronzePlanID = 2
+)
+
+// productPath is where the product page of the item with slug is
+func productPath(slug string) string {
+	return "/products/" + url.PathEscape(slug)
+}
+
+// catalogURL links to a page of the catalog, keeping the search and category
+func catalogURL(query, category string, page int) string {
+	v := url.Values{}
+	if query != "" {
+		v.Set("q", query)
+	}
+	if category != "" {
+		v.Set("category", category)
+	}
+	if page > 1 {
+		v.Set("page", strconv.Itoa(page))
+	}
+	if len(v) == 0 {
+		return "/catalog"
+	}
+	return "/catalog?" + v.Encode()
+}
+
+// Catalog displays the items on sale, filtered by category and searched by text
+func (server *Server) Catalog(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	category := strings.TrimSpace(r.URL.Query().Get("category"))
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	items, pages, total, err := server.DB.GetCatalogPaginated(models.CatalogFilter{
+		Category: category,
+		Query:    query,
+	}, catalogPageSize, page)
+	if err != nil {
+		log.Error().Err(err).Msg("Catalog")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	categories, err := server.DB.GetItemCategories()
+	if err != nil {
+		log.Error().Err(err).Msg("Catalog")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	data := make(map[string]interface{})
+	data["items"] = items
+	data["categories"] = categories
+	data["query"] = query
+	data["category"] = category
+	if page > 1 {
+		data["prev"] = catalogURL(query, category, page-1)
+	}
+	if page < pages {
+		data["next"] = catalogURL(query, category, page+1)
+	}
+
+	if err := server.renderTemplate(w, r, "catalog", &templateData{
+		Data:   data,
+		IntMap: map[string]int{"page": page, "pages": pages, "total": total},
+	}, "product-cards"); err != nil {
+		log.Error().Err(err).Msg("Catalog")
+	}
+}
+
+// Product displays the page to buy an item (by slug, from the url), or to subscribe to it if it
+// is recurring. The slugs an item had before redirect permanently to its page.
+func (server *Server) Product(w http.ResponseWriter, r *http.Request) {
+	item, renamed, err := findProduct(&server.DB, chi.URLParam(r, "slug"))
+	if errors.Is(err, sql.ErrNoRows) {
+		http.NotFound(w, r)
+		return
+	} else if err != nil {
+		log.Error().Err(err).Msg("Product")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if renamed {
+		http.Redirect(w, r, productPath(item.Slug), http.StatusMovedPermanently)
+		return
+	}
+
+	data := make(map[string]interface{})
+	data["item"] = item
+	data["canonical"] = server.config.FrontendAddr + productPath(item.Slug)
+
+	if item.IsRecurring {
+		err = server.renderTemplate(w, r, "plan", &templateData{
+			Data: data,
+		})
+	} else {
+		err = server.renderTemplate(w, r, "buy-once", &templateData{
+			Data: data,
+		}, "stripe-js")
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Product")
+	}
+}
+
+// productFinder looks up items by their slug, or one they had before. Having this interface
+// allows the use of gomock in tests.
+type productFinder interface {
+	GetItemBySlug(slug string) (models.Item, error)
+	GetItemByOldSlug(slug string) (models.Item, error)
+}
+
+// findProduct returns the item on sale at slug, and whether slug is one the item had before it
+// was renamed. It returns sql.ErrNoRows when no item on sale has or had slug.
+func findProduct(db productFinder, slug string) (models.Item, bool, error) {
+	item, err := db.GetItemBySlug(slug)
+	renamed := false
+	if errors.Is(err, sql.ErrNoRows) {
+		item, err = db.GetItemByOldSlug(slug)
+		renamed = true
+	}
+	if err != nil {
+		return item, false, err
+	}
+	if item.Archived {
+		return item, false, sql.ErrNoRows
+	}
+
+	return item, renamed, nil
+}
+
+// redirectToProduct permanently redirects the old link of item id to its product page
+func (server *Server) redirectToProduct(w http.ResponseWriter, r *http.Request, id int) {
+	item, err := server.DB.GetItem(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.NotFound(w, r)
+		return
+	} else if err != nil {
+		log.Error().Err(err).Msg("redirectToProduct")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, productPath(item.Slug), http.StatusMovedPermanently)
+}
+
+// ChargeOnce redirects the old /yoyo/{id} links to the product page
+func (server *Server) ChargeOnce(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	server.redirectToProduct(w, r, id)
+}
+
+// BronzePlan redirects the old bronze plan page, still linked to from emails, to its product page
+func (server *Server) BronzePlan(w http.ResponseWriter, r *http.Request) {
+	server.redirectToProduct(w, r, bronzePlanID)
+}
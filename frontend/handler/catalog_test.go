@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCatalogURL(t *testing.T) {
+	tests := []struct {
+		query, category string
+		page            int
+		want            string
+	}{
+		{"", "", 1, "/catalog"},
+		{"", "", 2, "/catalog?page=2"},
+		{"red yoyo", "", 1, "/catalog?q=red+yoyo"},
+		{"red", "Yoyos & More", 3, "/catalog?category=Yoyos+%26+More&page=3&q=red"},
+	}
+
+	for _, tt := range tests {
+		if got := catalogURL(tt.query, tt.category, tt.page); got != tt.want {
+			t.Errorf("catalogURL(%q, %q, %d) = %q, want %q", tt.query, tt.category, tt.page, got, tt.want)
+		}
+	}
+}
+
+func TestProductPath(t *testing.T) {
+	if got := productPath("bronze-plan"); got != "/products/bronze-plan" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestFindProduct(t *testing.T) {
+	yoyo := models.Item{ID: 1, Slug: "red-yoyo"}
+	archived := models.Item{ID: 2, Slug: "old-yoyo", Archived: true}
+
+	tests := []struct {
+		name        string
+		slug        string
+		setup       func(db *MockproductFinder)
+		wantItem    int
+		wantRenamed bool
+		wantErr     error
+	}{
+		{
+			name: "current slug",
+			slug: "red-yoyo",
+			setup: func(db *MockproductFinder) {
+				db.EXPECT().GetItemBySlug("red-yoyo").Return(yoyo, nil)
+			},
+			wantItem: 1,
+		},
+		{
+			name: "old slug",
+			slug: "yoyo",
+			setup: func(db *MockproductFinder) {
+				db.EXPECT().GetItemBySlug("yoyo").Return(models.Item{}, sql.ErrNoRows)
+				db.EXPECT().GetItemByOldSlug("yoyo").Return(yoyo, nil)
+			},
+			wantItem:    1,
+			wantRenamed: true,
+		},
+		{
+			name: "unknown slug",
+			slug: "blue-yoyo",
+			setup: func(db *MockproductFinder) {
+				db.EXPECT().GetItemBySlug("blue-yoyo").Return(models.Item{}, sql.ErrNoRows)
+				db.EXPECT().GetItemByOldSlug("blue-yoyo").Return(models.Item{}, sql.ErrNoRows)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "archived",
+			slug: "old-yoyo",
+			setup: func(db *MockproductFinder) {
+				db.EXPECT().GetItemBySlug("old-yoyo").Return(archived, nil)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "old slug of an archived item",
+			slug: "older-yoyo",
+			setup: func(db *MockproductFinder) {
+				db.EXPECT().GetItemBySlug("older-yoyo").Return(models.Item{}, sql.ErrNoRows)
+				db.EXPECT().GetItemByOldSlug("older-yoyo").Return(archived, nil)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := NewMockproductFinder(ctrl)
+			tt.setup(mockDB)
+
+			item, renamed, err := findProduct(mockDB, tt.slug)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if item.ID != tt.wantItem || renamed != tt.wantRenamed {
+				t.Errorf("expected item %d, renamed %v, got item %d, renamed %v", tt.wantItem, tt.wantRenamed, item.ID, renamed)
+			}
+		})
+	}
+}
@@ -3,11 +3,12 @@ package handler
 import "github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
 
 // SaveCustomer saves a customer and returns id
-func (server *Server) SaveCustomer(firstName, lastName, email string) (int, error) {
+func (server *Server) SaveCustomer(firstName, lastName, email, locale string) (int, error) {
 	customer := models.Customer{
 		FirstName: firstName,
 		LastName:  lastName,
 		Email:     email,
+		Locale:    locale,
 	}
 
 	id, err := server.DB.InsertCustomer(customer)
@@ -0,0 +1,14 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Dashboard shows the admin sales dashboard page
+func (server *Server) Dashboard(w http.ResponseWriter, r *http.Request) {
+	if err := server.renderTemplate(w, r, "dashboard", &templateData{}); err != nil {
+		log.Error().Err(err).Msg("Dashboard")
+	}
+}
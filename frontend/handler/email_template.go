@@ -0,0 +1,14 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+)
+
+// EmailTemplates shows the page to preview email templates and send test copies of them
+func (server *Server) EmailTemplates(w http.ResponseWriter, r *http.Request) {
+	if err := server.renderTemplate(w, r, "email-templates", &templateData{}); err != nil {
+		log.Error().Err(err).Msg("EmailTemplates")
+	}
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/events"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+// ListenForEvents hands the events published by any service to the websocket clients of this
+// frontend, until ctx is done. Every replica listens, so every open page hears of them.
+func (server *Server) ListenForEvents(ctx context.Context) {
+	events.Listen(ctx, server.config.DBSource, server.handleEvent)
+}
+
+// handleEvent sends the clients an event is meant for what they need to know of it
+func (server *Server) handleEvent(e events.Event) {
+	switch e.Type {
+	case events.UserDeleted:
+		server.hub.SendToUser(e.UserID, WsJsonResponse{
+			Action:  "logout",
+			Message: "Your account has been deleted",
+			UserID:  e.UserID,
+		})
+	case events.SaleCreated:
+		server.hub.SendToRole(models.RoleAdmin, WsJsonResponse{
+			Action:  "sale",
+			Message: fmt.Sprintf("New sale: %s, %s", e.Product, formatAmount(e.Amount, e.Currency)),
+		})
+	case events.RefundIssued:
+		server.hub.SendToRole(models.RoleAdmin, WsJsonResponse{
+			Action:  "refund",
+			Message: fmt.Sprintf("Order %d refunded: %s", e.OrderID, formatAmount(e.Amount, e.Currency)),
+		})
+	case events.SubscriptionCancelled:
+		server.hub.SendToRole(models.RoleAdmin, WsJsonResponse{
+			Action:  "subscription_cancelled",
+			Message: fmt.Sprintf("Subscription %d cancelled: %s", e.OrderID, e.Product),
+		})
+	default:
+		log.Warn().Str("type", e.Type).Msg("handleEvent")
+	}
+}
+
+// formatAmount formats an amount in cents for a notification
+func formatAmount(amount int, currency string) string {
+	return fmt.Sprintf("%.2f %s", float64(amount)/100, strings.ToUpper(currency))
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/events"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+)
+
+func TestHandleEvent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := &Server{hub: NewHub()}
+	go server.hub.Run(ctx)
+
+	admin := newTestClient(server.hub, 1, models.RoleAdmin, 8)
+	deleted := newTestClient(server.hub, 2, "staff", 8)
+
+	server.handleEvent(events.Event{Type: events.UserDeleted, UserID: 2})
+	server.handleEvent(events.Event{Type: events.SaleCreated, OrderID: 5, Product: "Yoyo", Amount: 1050, Currency: "usd"})
+	server.handleEvent(events.Event{Type: events.RefundIssued, OrderID: 5, Amount: 1050, Currency: "usd"})
+	server.handleEvent(events.Event{Type: events.SubscriptionCancelled, OrderID: 6, Product: "Bronze Plan"})
+	server.handleEvent(events.Event{Type: "unknown"})
+
+	if got := received(server.hub, admin); len(got) != 3 || got[0] != "sale" || got[1] != "refund" || got[2] != "subscription_cancelled" {
+		t.Errorf("expected admins to hear of sales, refunds and cancellations, got %v", got)
+	}
+	if got := received(server.hub, deleted); len(got) != 1 || got[0] != "logout" {
+		t.Errorf("expected only the deleted user to be logged out, got %v", got)
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	if got := formatAmount(1050, "usd"); got != "10.50 USD" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -3,12 +3,25 @@ package handler
 import (
 	"net/http"
 
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
 	"github.com/rs/zerolog/log"
 )
 
-// Home displays the home page
+// Home displays the home page, with the first items of the catalog
 func (server *Server) Home(w http.ResponseWriter, r *http.Request) {
-	if err := server.renderTemplate(w, r, "home", &templateData{}); err != nil {
+	// the page still works without products
+	items, _, total, err := server.DB.GetCatalogPaginated(models.CatalogFilter{}, homePageItems, 1)
+	if err != nil {
+		log.Error().Err(err).Msg("Home")
+	}
+
+	data := make(map[string]interface{})
+	data["items"] = items
+	data["more"] = total > len(items)
+
+	if err := server.renderTemplate(w, r, "home", &templateData{
+		Data: data,
+	}, "product-cards"); err != nil {
 		log.Error().Err(err).Msg("Home")
 	}
 }
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// AllItems shows the admin page listing the items for sale
+func (server *Server) AllItems(w http.ResponseWriter, r *http.Request) {
+	if err := server.renderTemplate(w, r, "all-items", &templateData{}); err != nil {
+		log.Error().Err(err).Msg("AllItems")
+	}
+}
+
+// OneItem shows the admin page to add or edit an item; the id is 0 for a new one
+func (server *Server) OneItem(w http.ResponseWriter, r *http.Request) {
+	if err := server.renderTemplate(w, r, "item", &templateData{}); err != nil {
+		log.Error().Err(err).Msg("OneItem")
+	}
+}
+
+// Upload serves a file from the upload store, such as an item image. Every upload is stored
+// under a new key, so it can be cached for good.
+func (server *Server) Upload(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "*")
+
+	body, err := server.uploads.Get(r.Context(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	} else if err != nil {
+		log.Error().Err(err).Str("key", key).Msg("Upload")
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	defer func() { _ = body.Close() }()
+
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	if _, err := io.Copy(w, body); err != nil {
+		log.Error().Err(err).Str("key", key).Msg("Upload")
+	}
+}
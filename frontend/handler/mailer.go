@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/i18n"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/mailer"
+	"github.com/rs/zerolog/log"
+)
+
+// orderConfirmation is the data of the order confirmation email, with the field names of the
+// main server's notification templates
+type orderConfirmation struct {
+	FirstName string
+	OrderID   int
+	Product   string
+	Amount    int
+	Currency  string
+}
+
+// queueOrderConfirmation queues the order confirmation email by template name. The email
+// templates live in the main server, whose mail dispatcher renders and sends it. Failures are
+// logged rather than returned, since the order has already been placed.
+func (server *Server) queueOrderConfirmation(to, locale string, data orderConfirmation) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		log.Error().Err(err).Msg("queueOrderConfirmation")
+		return
+	}
+
+	msg := mailer.Message{
+		From:     "info@yoyo.com",
+		To:       to,
+		Subject:  i18n.For(locale).T("email.order_confirmation.subject"),
+		Template: "order-confirmation",
+		Locale:   locale,
+		Data:     body,
+	}
+
+	if err := server.mail.Send(context.Background(), msg); err != nil {
+		log.Error().Err(err).Msg("queueOrderConfirmation")
+	}
+}
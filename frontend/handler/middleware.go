@@ -2,6 +2,9 @@ package handler
 
 import (
 	"net/http"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/rs/zerolog/log"
 )
 
 // SessionLoad peforms the load and save of a session, per request
@@ -10,13 +13,27 @@ func (server *Server) SessionLoad(next http.Handler) http.Handler {
 }
 
 // Auth checks for user authentication status by checking for the key
-// userID in the session
+// userID in the session, and only lets admin users through
 func (server *Server) Auth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !server.Session.Exists(r.Context(), "userID") {
+		id := server.Session.GetInt(r.Context(), "userID")
+		if id == 0 {
+			http.Redirect(w, r, "/login", http.StatusTemporaryRedirect)
+			return
+		}
+
+		user, err := server.DB.GetOneUser(id)
+		if err != nil {
+			log.Error().Err(err).Msg("Auth")
 			http.Redirect(w, r, "/login", http.StatusTemporaryRedirect)
 			return
 		}
+
+		if user.Role != models.RoleAdmin {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
 		next.ServeHTTP(w, r)
 	})
 }
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/LamThanhNguyen/yoyo-store-backend/frontend/handler (interfaces: productFinder)
+//
+// Generated by this command:
+//
+//	mockgen -package handler -destination frontend/handler/mock_interfaces_test.go github.com/LamThanhNguyen/yoyo-store-backend/frontend/handler productFinder
+//
+
+// Package handler is a generated GoMock package.
+package handler
+
+import (
+	reflect "reflect"
+
+	models "github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockproductFinder is a mock of productFinder interface.
+type MockproductFinder struct {
+	ctrl     *gomock.Controller
+	recorder *MockproductFinderMockRecorder
+	isgomock struct{}
+}
+
+// MockproductFinderMockRecorder is the mock recorder for MockproductFinder.
+type MockproductFinderMockRecorder struct {
+	mock *MockproductFinder
+}
+
+// NewMockproductFinder creates a new mock instance.
+func NewMockproductFinder(ctrl *gomock.Controller) *MockproductFinder {
+	mock := &MockproductFinder{ctrl: ctrl}
+	mock.recorder = &MockproductFinderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockproductFinder) EXPECT() *MockproductFinderMockRecorder {
+	return m.recorder
+}
+
+// GetItemByOldSlug mocks base method.
+func (m *MockproductFinder) GetItemByOldSlug(slug string) (models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemByOldSlug", slug)
+	ret0, _ := ret[0].(models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemByOldSlug indicates an expected call of GetItemByOldSlug.
+func (mr *MockproductFinderMockRecorder) GetItemByOldSlug(slug any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemByOldSlug", reflect.TypeOf((*MockproductFinder)(nil).GetItemByOldSlug), slug)
+}
+
+// GetItemBySlug mocks base method.
+func (m *MockproductFinder) GetItemBySlug(slug string) (models.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemBySlug", slug)
+	ret0, _ := ret[0].(models.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemBySlug indicates an expected call of GetItemBySlug.
+func (mr *MockproductFinderMockRecorder) GetItemBySlug(slug any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemBySlug", reflect.TypeOf((*MockproductFinder)(nil).GetItemBySlug), slug)
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/frontend/util"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/oauth2"
+)
+
+// oidcProvider is the identity provider used for admin single sign-on
+type oidcProvider struct {
+	oauth2   oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// idClaims are the ID token claims used to map an identity to a user
+type idClaims struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
+}
+
+// newOIDCProvider discovers the identity provider at the configured issuer URL
+func newOIDCProvider(ctx context.Context, config util.Config) (*oidcProvider, error) {
+	provider, err := oidc.NewProvider(ctx, config.OidcIssuerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oidcProvider{
+		oauth2: oauth2.Config{
+			ClientID:     config.OidcClientID,
+			ClientSecret: config.OidcClientSecret,
+			RedirectURL:  config.OidcRedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       []string{oidc.ScopeOpenID, "profile", "email"},
+		},
+		verifier: provider.Verifier(&oidc.Config{ClientID: config.OidcClientID}),
+	}, nil
+}
+
+// authCodeURL returns the provider's login URL, bound to state, nonce and the PKCE verifier
+func (p *oidcProvider) authCodeURL(state, nonce, verifier string) string {
+	return p.oauth2.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+}
+
+// exchange trades an authorization code for the claims of a verified ID token
+func (p *oidcProvider) exchange(ctx context.Context, code, verifier, nonce string) (idClaims, error) {
+	var claims idClaims
+
+	token, err := p.oauth2.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return claims, err
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return claims, errors.New("no id_token in token response")
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return claims, err
+	}
+
+	if idToken.Nonce != nonce {
+		return claims, errors.New("id token nonce mismatch")
+	}
+
+	if err := idToken.Claims(&claims); err != nil {
+		return claims, err
+	}
+
+	if claims.Subject == "" || claims.Email == "" {
+		return claims, errors.New("id token is missing the subject or email claim")
+	}
+
+	return claims, nil
+}
+
+// SSOLogin starts an authorization code flow with PKCE against the identity provider
+func (server *Server) SSOLogin(w http.ResponseWriter, r *http.Request) {
+	if server.oidc == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	state := oauth2.GenerateVerifier()
+	nonce := oauth2.GenerateVerifier()
+	verifier := oauth2.GenerateVerifier()
+
+	server.Session.Put(r.Context(), "oidc_state", state)
+	server.Session.Put(r.Context(), "oidc_nonce", nonce)
+	server.Session.Put(r.Context(), "oidc_verifier", verifier)
+
+	http.Redirect(w, r, server.oidc.authCodeURL(state, nonce, verifier), http.StatusFound)
+}
+
+// SSOCallback completes single sign-on, and logs the user in the same way PostLoginPage does
+func (server *Server) SSOCallback(w http.ResponseWriter, r *http.Request) {
+	if server.oidc == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	state := server.Session.PopString(r.Context(), "oidc_state")
+	nonce := server.Session.PopString(r.Context(), "oidc_nonce")
+	verifier := server.Session.PopString(r.Context(), "oidc_verifier")
+
+	if errParam := r.URL.Query().Get("error"); errParam != "" {
+		log.Error().Str("error", errParam).Msg("SSOCallback: identity provider returned an error")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if state == "" || r.URL.Query().Get("state") != state {
+		log.Error().Msg("SSOCallback: state mismatch")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := server.oidc.exchange(ctx, r.URL.Query().Get("code"), verifier, nonce)
+	if err != nil {
+		log.Error().Err(err).Msg("SSOCallback")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	user, err := server.userForClaims(claims)
+	if err != nil {
+		log.Error().Err(err).Str("subject", claims.Subject).Msg("SSOCallback")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if user.Role != models.RoleAdmin {
+		log.Info().Int("user_id", user.ID).Str("role", user.Role).Msg("SSOCallback: user is not an admin")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	// issue the same api token the password login gets from /api/v1/authenticate, before
+	// signing the user in, so that a failure doesn't leave a session without a token
+	token, err := models.GenerateToken(user.ID, 24*time.Hour, models.ScopeAuthentication)
+	if err != nil {
+		log.Error().Err(err).Msg("SSOCallback")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if err := server.DB.InsertToken(token, user); err != nil {
+		log.Error().Err(err).Msg("SSOCallback")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if err := server.Session.RenewToken(r.Context()); err != nil {
+		log.Error().Err(err).Msg("Session.RenewToken")
+	}
+
+	server.Session.Put(r.Context(), "userID", user.ID)
+
+	data := make(map[string]interface{})
+	data["token"] = token
+
+	if err := server.renderTemplate(w, r, "sso-login", &templateData{
+		Data: data,
+	}); err != nil {
+		log.Error().Err(err).Msg("SSOCallback")
+	}
+}
+
+// userForClaims maps an identity to a user: first by subject, then by verified email,
+// and finally by provisioning a new user if just-in-time provisioning is enabled
+func (server *Server) userForClaims(claims idClaims) (models.User, error) {
+	user, err := server.DB.GetUserByOIDCSubject(claims.Subject)
+	if err == nil {
+		return user, nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return user, err
+	}
+
+	if !claims.EmailVerified {
+		return user, errors.New("identity provider has not verified the email address")
+	}
+
+	user, err = server.DB.GetUserByEmail(claims.Email)
+	if err == nil {
+		return user, server.DB.LinkOIDCSubject(user.ID, claims.Subject)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return user, err
+	}
+
+	if jit, _ := strconv.ParseBool(server.config.OidcJitProvision); !jit {
+		return user, errors.New("no user matches the identity, and provisioning is disabled")
+	}
+
+	// provisioned users get no access unless a role is chosen for them
+	if server.config.OidcDefaultRole == "" {
+		return user, errors.New("no user matches the identity, and no role is set for provisioned users")
+	}
+
+	user = models.User{
+		FirstName: claims.GivenName,
+		LastName:  claims.FamilyName,
+		Email:     claims.Email,
+		Role:      server.config.OidcDefaultRole,
+	}
+
+	user.ID, err = server.DB.AddOIDCUser(user, claims.Subject)
+	if err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
@@ -3,19 +3,9 @@ package handler
 import (
 	"net/http"
 
-	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
 	"github.com/rs/zerolog/log"
 )
 
-// SaveOrder saves a order and returns id
-func (server *Server) SaveOrder(order models.Order) (int, error) {
-	id, err := server.DB.InsertOrder(order)
-	if err != nil {
-		return 0, err
-	}
-	return id, nil
-}
-
 // AllSales shows the all sales page
 func (server *Server) AllSales(w http.ResponseWriter, r *http.Request) {
 	if err := server.renderTemplate(w, r, "all-sales", &templateData{}); err != nil {
@@ -5,50 +5,11 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/events"
 	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
-	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
 )
 
-// ChargeOnce displays the page to buy one yoyo
-func (server *Server) ChargeOnce(w http.ResponseWriter, r *http.Request) {
-	id := chi.URLParam(r, "id")
-	itemID, _ := strconv.Atoi(id)
-
-	yoyo, err := server.DB.GetItem(itemID)
-	if err != nil {
-		log.Error().Err(err).Msg("ChargeOnce")
-		return
-	}
-
-	data := make(map[string]interface{})
-	data["item"] = yoyo
-
-	if err := server.renderTemplate(w, r, "buy-once", &templateData{
-		Data: data,
-	}, "stripe-js"); err != nil {
-		log.Error().Err(err).Msg("ChargeOnce")
-	}
-}
-
-// BronzePlan displays the bronze plan page
-func (server *Server) BronzePlan(w http.ResponseWriter, r *http.Request) {
-	item, err := server.DB.GetItem(2)
-	if err != nil {
-		log.Error().Err(err).Msg("BronzePlan")
-		return
-	}
-
-	data := make(map[string]interface{})
-	data["item"] = item
-
-	if err := server.renderTemplate(w, r, "bronze-plan", &templateData{
-		Data: data,
-	}); err != nil {
-		log.Error().Err(err).Msg("BronzePlan")
-	}
-}
-
 // BronzePlanReceipt displays the receipt for bronze plans
 func (server *Server) BronzePlanReceipt(w http.ResponseWriter, r *http.Request) {
 	if err := server.renderTemplate(w, r, "receipt-plan", &templateData{}); err != nil {
@@ -74,7 +35,7 @@ func (server *Server) PaymentSucceeded(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// create a new customer
-	customerID, err := server.SaveCustomer(txnData.FirstName, txnData.LastName, txnData.Email)
+	customerID, err := server.SaveCustomer(txnData.FirstName, txnData.LastName, txnData.Email, txnData.Locale)
 	if err != nil {
 		log.Error().Err(err).Msg("PaymentSucceeded")
 		return
@@ -107,34 +68,52 @@ func (server *Server) PaymentSucceeded(w http.ResponseWriter, r *http.Request) {
 		StatusID:      1,
 		Quantity:      1,
 		Amount:        txnData.PaymentAmount,
+		Locale:        txnData.Locale,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
 
-	orderID, err := server.SaveOrder(order)
-	if err != nil {
-		log.Error().Err(err).Msg("PaymentSucceeded")
-		return
-	}
-
-	// call microservice
-	inv := Invoice{
-		ID:        orderID,
+	// the invoice is requested in the same transaction as the order, and delivered by the
+	// main server, so a slow invoice service can't fail a payment that already went through
+	orderID, err := server.DB.InsertOrderWithInvoiceRequest(order, models.InvoiceRequest{
 		Amount:    order.Amount,
 		Product:   "Yoyo",
 		Quantity:  order.Quantity,
+		Currency:  txnData.PaymentCurrency,
+		CardBrand: txnData.CardBrand,
+		LastFour:  txnData.LastFour,
+		Country:   txnData.Country,
+		Locale:    txnData.Locale,
 		FirstName: txnData.FirstName,
 		LastName:  txnData.LastName,
 		Email:     txnData.Email,
 		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("PaymentSucceeded")
+		return
 	}
 
-	err = server.callInvoiceMicro(inv)
+	err = server.DB.PublishEvent(events.Event{
+		Type:     events.SaleCreated,
+		OrderID:  orderID,
+		Product:  "Yoyo",
+		Amount:   order.Amount,
+		Currency: txnData.PaymentCurrency,
+		At:       time.Now(),
+	})
 	if err != nil {
 		log.Error().Err(err).Msg("PaymentSucceeded")
-		return
 	}
 
+	server.queueOrderConfirmation(txnData.Email, txnData.Locale, orderConfirmation{
+		FirstName: txnData.FirstName,
+		OrderID:   orderID,
+		Product:   "Yoyo",
+		Amount:    order.Amount,
+		Currency:  txnData.PaymentCurrency,
+	})
+
 	// write this data to session, and then redirect user to new page
 	server.Session.Put(r.Context(), "receipt", txnData)
 	http.Redirect(w, r, "/receipt", http.StatusSeeOther)
@@ -1,13 +1,20 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"html/template"
 	"maps"
 	"net/http"
+	"net/netip"
+	"time"
 
 	"github.com/LamThanhNguyen/yoyo-store-backend/frontend/util"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/mailer"
 	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/storage"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/throttle"
 	"github.com/alexedwards/scs/v2"
 	"github.com/go-chi/chi/v5"
 )
@@ -17,6 +24,11 @@ type Server struct {
 	templateCache map[string]*template.Template
 	DB            models.DBModel
 	Session       *scs.SessionManager
+	oidc          *oidcProvider
+	proxies       []netip.Prefix
+	hub           *Hub
+	uploads       storage.Store
+	mail          *mailer.Outbox
 	router        http.Handler
 }
 
@@ -26,16 +38,47 @@ func NewServer(
 	db models.DBModel,
 	session *scs.SessionManager,
 ) (*Server, error) {
-	return &Server{
+	server := &Server{
 		config:        config,
 		templateCache: templateCache,
 		DB:            db,
 		Session:       session,
-	}, nil
+		hub:           NewHub(),
+	}
+	// the frontend only queues mail, in the database it shares with the main server, whose
+	// dispatcher sends it
+	server.mail = mailer.NewOutbox(&server.DB, nil)
+
+	proxies, err := throttle.ParseProxies(config.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+	server.proxies = proxies
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	uploads, err := storage.New(ctx, config.UploadStorageConfig())
+	if err != nil {
+		return nil, fmt.Errorf("upload storage: %w", err)
+	}
+	server.uploads = uploads
+
+	// single sign-on is optional, and only enabled when an issuer is configured
+	if config.OidcIssuerURL != "" {
+		provider, err := newOIDCProvider(ctx, config)
+		if err != nil {
+			return nil, err
+		}
+		server.oidc = provider
+	}
+
+	return server, nil
 }
 
 func (server *Server) SetupRouter() {
 	mux := chi.NewRouter()
+	mux.Use(throttle.RealIP(server.proxies))
 	mux.Use(server.SessionLoad)
 
 	mux.Get("/", server.Home)
@@ -43,6 +86,7 @@ func (server *Server) SetupRouter() {
 
 	mux.Route("/admin", func(mux chi.Router) {
 		mux.Use(server.Auth)
+		mux.Get("/dashboard", server.Dashboard)
 		mux.Get("/virtual-terminal", server.VirtualTerminal)
 		mux.Get("/all-sales", server.AllSales)
 		mux.Get("/all-subscriptions", server.AllSubscriptions)
@@ -50,22 +94,34 @@ func (server *Server) SetupRouter() {
 		mux.Get("/subscriptions/{id}", server.ShowSubscription)
 		mux.Get("/all-users", server.AllUsers)
 		mux.Get("/all-users/{id}", server.OneUser)
+		mux.Get("/audit", server.AuditLog)
+		mux.Get("/email-templates", server.EmailTemplates)
+		mux.Get("/items", server.AllItems)
+		mux.Get("/items/{id}", server.OneItem)
 	})
 
+	mux.Get("/catalog", server.Catalog)
+	mux.Get("/products/{slug}", server.Product)
+	// old links to products
 	mux.Get("/yoyo/{id}", server.ChargeOnce)
+	mux.Get("/plans/bronze", server.BronzePlan)
+
 	mux.Post("/payment-succeeded", server.PaymentSucceeded)
 	mux.Get("/receipt", server.Receipt)
-
-	mux.Get("/plans/bronze", server.BronzePlan)
 	mux.Get("/receipt/bronze", server.BronzePlanReceipt)
 
 	// auth routes
 	mux.Get("/login", server.LoginPage)
 	mux.Post("/login", server.PostLoginPage)
+	mux.Get("/login/sso", server.SSOLogin)
+	mux.Get("/login/sso/callback", server.SSOCallback)
 	mux.Get("/logout", server.Logout)
 	mux.Get("/forgot-password", server.ForgotPassword)
 	mux.Get("/reset-password", server.ShowResetPassword)
+	mux.Get("/verify-email", server.VerifyEmail)
 
+	// uploads are kept in their own store, which every replica can read
+	mux.Get("/static/uploads/*", server.Upload)
 	fileServer := http.FileServer(http.Dir("./static"))
 	mux.Handle("/static/*", http.StripPrefix("/static", fileServer))
 	mux.Get("/health", server.handleHealthCheck)
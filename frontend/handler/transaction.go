@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/LamThanhNguyen/yoyo-store-backend/internal/cards"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/i18n"
 	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
 	"github.com/rs/zerolog/log"
 )
@@ -18,6 +19,9 @@ type TransactionData struct {
 	PaymentAmount   int
 	PaymentCurrency string
 	LastFour        string
+	CardBrand       string
+	Country         string
+	Locale          string
 	ExpiryMonth     int
 	ExpiryYear      int
 	BankReturnCode  string
@@ -58,6 +62,9 @@ func (server *Server) GetTransactionData(r *http.Request) (TransactionData, erro
 		return txnData, err
 	}
 
+	// invoices and emails are written in the language the customer browses in
+	locale := i18n.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+
 	lastFour := pm.Card.Last4
 	expiryMonth := pm.Card.ExpMonth
 	expiryYear := pm.Card.ExpYear
@@ -71,6 +78,9 @@ func (server *Server) GetTransactionData(r *http.Request) (TransactionData, erro
 		PaymentAmount:   amount,
 		PaymentCurrency: paymentCurrency,
 		LastFour:        lastFour,
+		CardBrand:       string(pm.Card.Brand),
+		Country:         cards.BillingCountry(pm),
+		Locale:          locale,
 		ExpiryMonth:     int(expiryMonth),
 		ExpiryYear:      int(expiryYear),
 		BankReturnCode:  pi.LatestCharge.ID,
@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
-	"github.com/LamThanhNguyen/yoyo-store-backend/internal/encryption"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/throttle"
 	"github.com/LamThanhNguyen/yoyo-store-backend/internal/urlsigner"
 	"github.com/rs/zerolog/log"
 )
@@ -25,7 +29,12 @@ func (server *Server) OneUser(w http.ResponseWriter, r *http.Request) {
 
 // LoginPage displays the login page
 func (server *Server) LoginPage(w http.ResponseWriter, r *http.Request) {
-	if err := server.renderTemplate(w, r, "login", &templateData{}); err != nil {
+	data := make(map[string]interface{})
+	data["sso"] = server.oidc != nil
+
+	if err := server.renderTemplate(w, r, "login", &templateData{
+		Data: data,
+	}); err != nil {
 		log.Error().Err(err).Msg("LoginPage")
 	}
 }
@@ -45,12 +54,37 @@ func (server *Server) PostLoginPage(w http.ResponseWriter, r *http.Request) {
 	email := r.Form.Get("email")
 	password := r.Form.Get("password")
 
+	ip := throttle.ClientIP(r)
+	keys := throttle.Keys(email, ip)
+
+	lockedUntil, err := server.DB.GetAuthLockout(throttle.ActionLogin, keys)
+	if err != nil {
+		log.Error().Err(err).Msg("PostLoginPage")
+	}
+
+	if !lockedUntil.IsZero() {
+		log.Info().Str("ip", ip).Msg("login attempt while locked out")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
 	id, authErr := server.DB.Authenticate(email, password)
-	if authErr != nil {
+	if errors.Is(authErr, models.ErrEmailNotVerified) {
+		// the password was right, so this is not a failed attempt
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	} else if authErr != nil {
+		if err := server.DB.RecordAuthFailure(throttle.ActionLogin, ip, keys); err != nil {
+			log.Error().Err(err).Msg("PostLoginPage")
+		}
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 		return
 	}
 
+	if err := server.DB.ClearAuthFailures(throttle.ActionLogin, throttle.AccountKey(email)); err != nil {
+		log.Error().Err(err).Msg("PostLoginPage")
+	}
+
 	server.Session.Put(r.Context(), "userID", id)
 	http.Redirect(w, r, "/", http.StatusSeeOther)
 }
@@ -74,42 +108,58 @@ func (server *Server) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// ShowResetPassword shows the reset password page (and validates url integrity)
-func (server *Server) ShowResetPassword(w http.ResponseWriter, r *http.Request) {
-	email := r.URL.Query().Get("email")
-	theURL := r.RequestURI
-	testURL := fmt.Sprintf("%s%s", server.config.FrontendAddr, theURL)
+// VerifyEmail confirms an email address from the signed link sent by the api, and shows the result
+func (server *Server) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	testURL := fmt.Sprintf("%s%s", server.config.FrontendAddr, r.RequestURI)
 
 	signer := urlsigner.Signer{
 		Secret: []byte(server.config.TokenSymmetricKey),
 	}
 
-	valid := signer.VerifyToken(testURL)
+	data := make(map[string]interface{})
+	data["verified"] = false
+
+	id, _ := strconv.Atoi(r.URL.Query().Get("id"))
+	email := r.URL.Query().Get("email")
 
-	if !valid {
+	switch {
+	case !signer.VerifyToken(testURL):
 		log.Error().Msg("Invalid url - tampering detected")
-		return
+		data["message"] = "This verification link is not valid."
+	case signer.Expired(testURL, 24*60):
+		data["message"] = "This verification link has expired. Ask an administrator to send a new one."
+	default:
+		err := server.DB.VerifyEmail(id, email)
+		if errors.Is(err, sql.ErrNoRows) {
+			data["message"] = "This verification link has already been used."
+		} else if err != nil {
+			log.Error().Err(err).Msg("VerifyEmail")
+			data["message"] = "Your email address could not be verified. Please try again later."
+		} else {
+			data["verified"] = true
+			data["message"] = fmt.Sprintf("Thank you, %s is now verified.", email)
+		}
 	}
 
-	// make sure not expired
-	expired := signer.Expired(testURL, 60)
-	if expired {
-		log.Error().Msg("Link expired")
-		return
+	if err := server.renderTemplate(w, r, "verify-email", &templateData{
+		Data: data,
+	}); err != nil {
+		log.Error().Err(err).Msg("VerifyEmail")
 	}
+}
 
-	encyrptor := encryption.Encryption{
-		Key: []byte(server.config.TokenSymmetricKey),
-	}
+// ShowResetPassword shows the reset password page (and checks the reset token is still valid)
+func (server *Server) ShowResetPassword(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
 
-	encryptedEmail, err := encyrptor.Encrypt(email)
+	_, err := server.DB.GetUserForPasswordReset(token)
 	if err != nil {
-		log.Error().Msg("Encryption failed")
+		log.Error().Err(err).Msg("ShowResetPassword")
 		return
 	}
 
 	data := make(map[string]interface{})
-	data["email"] = encryptedEmail
+	data["token"] = token
 
 	if err := server.renderTemplate(w, r, "reset-password", &templateData{
 		Data: data,
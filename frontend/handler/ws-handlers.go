@@ -2,113 +2,122 @@ package handler
 
 import (
 	"context"
-	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 
 	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog/log"
 )
 
-type WebSocketConnection struct {
-	*websocket.Conn
-}
-
+// WsPayload is a message sent by a browser
 type WsPayload struct {
-	Action      string              `json:"action"`
-	Message     string              `json:"message"`
-	UserName    string              `json:"username"`
-	MessageType string              `json:"message_type"`
-	UserID      int                 `json:"user_id"`
-	Conn        WebSocketConnection `json:"-"`
+	Action  string `json:"action"`
+	Message string `json:"message"`
+	UserID  int    `json:"user_id"`
 }
 
+// WsJsonResponse is a message sent to browsers
 type WsJsonResponse struct {
 	Action  string `json:"action"`
 	Message string `json:"message"`
 	UserID  int    `json:"user_id"`
 }
 
-var clients = make(map[WebSocketConnection]string)
+// RunWsHub delivers websocket messages until ctx is done
+func (server *Server) RunWsHub(ctx context.Context) {
+	server.hub.Run(ctx)
+}
 
-var wsChan = make(chan WsPayload, 8)
+// handleWsPayload acts on a message sent by client. Browsers have nothing to ask for yet:
+// what they are told about comes from the events published by the services.
+func (server *Server) handleWsPayload(client *wsClient, payload WsPayload) {
+	log.Debug().Int("user_id", client.userID).Str("action", payload.Action).Msg("websocket message ignored")
+}
 
-func (server *Server) ListenToWsChannel(ctx context.Context) {
-	var response WsJsonResponse
-	for {
-		select {
-		case <-ctx.Done():
-			log.Info().Msg("WsChannel stopped")
-			return
-		case e := <-wsChan:
-			switch e.Action {
-			case "deleteUser":
-				response.Action = "logout"
-				response.Message = "Your account has been deleted"
-				response.UserID = e.UserID
-				server.broadcastToAll(response)
-			default:
-			}
-		}
+// checkWsOrigin accepts requests from the frontend itself, from FRONTEND_ADDR and from
+// ALLOWED_ORIGINS. Requests without an Origin header don't come from a browser, and are accepted.
+func (server *Server) checkWsOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
 	}
-}
 
-func (server *Server) broadcastToAll(response WsJsonResponse) {
-	for client := range clients {
-		// broadcast to every connected client
-		err := client.WriteJSON(response)
-		if err != nil {
-			log.Error().Err(err).Str("action", response.Action).Msg("Websocket error")
-			_ = client.Close()
-			delete(clients, client)
-		}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
 	}
-}
 
-var upgradeConnection = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin:     func(r *http.Request) bool { return true },
+	origin = strings.TrimSuffix(strings.ToLower(origin), "/")
+	allowed := append([]string{server.config.FrontendAddr}, server.config.AllowedOrigins...)
+	return slices.ContainsFunc(allowed, func(a string) bool {
+		return a != "" && strings.TrimSuffix(strings.ToLower(a), "/") == origin
+	})
 }
 
+// WsEndPoint upgrades the connection of a signed in user to a websocket
 func (server *Server) WsEndPoint(w http.ResponseWriter, r *http.Request) {
-	ws, err := upgradeConnection.Upgrade(w, r, nil)
+	id := server.Session.GetInt(r.Context(), "userID")
+	if id == 0 {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	user, err := server.DB.GetOneUser(id)
 	if err != nil {
 		log.Error().Err(err).Msg("WsEndPoint")
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 		return
 	}
 
-	log.Info().Str("RemoteAddr", r.RemoteAddr).Msg("Client connected")
-	var response WsJsonResponse
-	response.Message = "Connected to server"
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin:     server.checkWsOrigin,
+	}
 
-	err = ws.WriteJSON(response)
+	ws, err := upgrader.Upgrade(hijacker(w), r, nil)
 	if err != nil {
 		log.Error().Err(err).Msg("WsEndPoint")
 		return
 	}
 
-	conn := WebSocketConnection{Conn: ws}
-	clients[conn] = ""
+	log.Info().Str("RemoteAddr", r.RemoteAddr).Int("user_id", user.ID).Msg("Client connected")
 
-	go server.ListenForWS(&conn)
-}
+	client := &wsClient{
+		conn:   ws,
+		userID: user.ID,
+		role:   user.Role,
+		send:   make(chan WsJsonResponse, wsSendBuffer),
+	}
+	// queued before the hub, which may close the channel, knows about it
+	client.send <- WsJsonResponse{Message: "Connected to server"}
+	if !server.hub.add(client) {
+		_ = ws.Close()
+		return
+	}
 
-func (server *Server) ListenForWS(conn *WebSocketConnection) {
-	defer func() {
-		if r := recover(); r != nil {
-			log.Error().Msg(fmt.Sprintf("ListenForWS ERROR: %v", r))
-		}
+	go client.writePump()
+	go func() {
+		client.readPump(server.handleWsPayload)
+		server.hub.drop(client)
 	}()
+}
 
-	var payload WsPayload
-
+// hijacker returns the http.Hijacker beneath w, which the session middleware wraps
+func hijacker(w http.ResponseWriter) http.ResponseWriter {
 	for {
-		err := conn.ReadJSON(&payload)
-		if err != nil {
-			// do nothing
-		} else {
-			payload.Conn = *conn
-			wsChan <- payload
+		if _, ok := w.(http.Hijacker); ok {
+			return w
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return w
 		}
+		w = u.Unwrap()
 	}
 }
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// wsWriteWait is how long a write to a client may take
+	wsWriteWait = 10 * time.Second
+	// wsPongWait is how long a client may stay silent, pongs included, before it is dropped
+	wsPongWait = 60 * time.Second
+	// wsPingPeriod must be shorter than wsPongWait, so a live client always answers in time
+	wsPingPeriod = wsPongWait * 9 / 10
+	// wsMaxMessageSize limits what a client may send
+	wsMaxMessageSize = 4096
+	// wsSendBuffer is how many messages a client may fall behind by before it is dropped
+	wsSendBuffer = 16
+)
+
+// WsTarget addresses a message: to one user, to every user with a role, or to everyone when
+// both are empty
+type WsTarget struct {
+	UserID int
+	Role   string
+}
+
+// matches reports whether c is addressed by t
+func (t WsTarget) matches(c *wsClient) bool {
+	switch {
+	case t.UserID != 0:
+		return c.userID == t.UserID
+	case t.Role != "":
+		return c.role == t.Role
+	default:
+		return true
+	}
+}
+
+type wsMessage struct {
+	target   WsTarget
+	response WsJsonResponse
+}
+
+// wsClient is one websocket connection of a signed in user. Only the hub sends on, and closes,
+// the send channel; only writePump writes to the connection.
+type wsClient struct {
+	conn   *websocket.Conn
+	userID int
+	role   string
+	send   chan WsJsonResponse
+}
+
+// Hub keeps track of the websocket connections of this frontend and delivers messages to them.
+// The set of clients is only touched by Run, which the other methods talk to through channels.
+type Hub struct {
+	clients    map[*wsClient]struct{}
+	register   chan *wsClient
+	unregister chan *wsClient
+	outbound   chan wsMessage
+	done       chan struct{}
+}
+
+// NewHub returns a hub, which delivers nothing until Run is called
+func NewHub() *Hub {
+	return &Hub{
+		clients:    make(map[*wsClient]struct{}),
+		register:   make(chan *wsClient),
+		unregister: make(chan *wsClient),
+		outbound:   make(chan wsMessage, 64),
+		done:       make(chan struct{}),
+	}
+}
+
+// Run delivers messages until ctx is done, then disconnects every client
+func (h *Hub) Run(ctx context.Context) {
+	defer close(h.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			for c := range h.clients {
+				h.remove(c)
+			}
+			log.Info().Msg("websocket hub stopped")
+			return
+		case c := <-h.register:
+			h.clients[c] = struct{}{}
+		case c := <-h.unregister:
+			h.remove(c)
+		case m := <-h.outbound:
+			for c := range h.clients {
+				if !m.target.matches(c) {
+					continue
+				}
+				select {
+				case c.send <- m.response:
+				default:
+					// a client that can't keep up would hold everyone else back
+					log.Warn().Int("user_id", c.userID).Str("action", m.response.Action).Msg("dropping slow websocket client")
+					h.remove(c)
+				}
+			}
+		}
+	}
+}
+
+func (h *Hub) remove(c *wsClient) {
+	if _, ok := h.clients[c]; ok {
+		delete(h.clients, c)
+		close(c.send)
+	}
+}
+
+// Send queues response for the clients addressed by target. It never blocks on a client, and
+// drops the message once the hub has stopped.
+func (h *Hub) Send(target WsTarget, response WsJsonResponse) {
+	select {
+	case h.outbound <- wsMessage{target: target, response: response}:
+	case <-h.done:
+	}
+}
+
+// SendToUser sends response to every connection of user id
+func (h *Hub) SendToUser(id int, response WsJsonResponse) {
+	h.Send(WsTarget{UserID: id}, response)
+}
+
+// SendToRole sends response to every user with role
+func (h *Hub) SendToRole(role string, response WsJsonResponse) {
+	h.Send(WsTarget{Role: role}, response)
+}
+
+// Broadcast sends response to everyone connected
+func (h *Hub) Broadcast(response WsJsonResponse) {
+	h.Send(WsTarget{}, response)
+}
+
+// add registers c, and reports false if the hub has stopped
+func (h *Hub) add(c *wsClient) bool {
+	select {
+	case h.register <- c:
+		return true
+	case <-h.done:
+		return false
+	}
+}
+
+func (h *Hub) drop(c *wsClient) {
+	select {
+	case h.unregister <- c:
+	case <-h.done:
+	}
+}
+
+// writePump writes the messages for c, and pings it, until the hub closes its send channel or
+// a write fails
+func (c *wsClient) writePump() {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer func() {
+		ticker.Stop()
+		_ = c.conn.Close()
+	}()
+
+	for {
+		select {
+		case response, ok := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if !ok {
+				_ = c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""))
+				return
+			}
+			if err := c.conn.WriteJSON(response); err != nil {
+				log.Error().Err(err).Str("action", response.Action).Msg("writePump")
+				return
+			}
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readPump hands what c sends to handle, until the connection fails or goes quiet for longer
+// than wsPongWait
+func (c *wsClient) readPump(handle func(*wsClient, WsPayload)) {
+	c.conn.SetReadLimit(wsMaxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, msg, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure, websocket.CloseNoStatusReceived) {
+				log.Error().Err(err).Int("user_id", c.userID).Msg("readPump")
+			}
+			return
+		}
+
+		var payload WsPayload
+		if err := json.Unmarshal(msg, &payload); err != nil {
+			log.Warn().Err(err).Int("user_id", c.userID).Msg("readPump")
+			continue
+		}
+		handle(c, payload)
+	}
+}
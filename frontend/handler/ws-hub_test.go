@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/frontend/util"
+)
+
+func newTestClient(h *Hub, userID int, role string, buffer int) *wsClient {
+	c := &wsClient{userID: userID, role: role, send: make(chan WsJsonResponse, buffer)}
+	h.add(c)
+	return c
+}
+
+// received drains what c has been sent. Messages are delivered in the order they are sent, so
+// every earlier one has been once a probe gets its own.
+func received(h *Hub, c *wsClient) []string {
+	probe := newTestClient(h, -1, "", 1)
+	h.SendToUser(-1, WsJsonResponse{Action: "probe"})
+	<-probe.send
+	h.drop(probe)
+
+	var actions []string
+	for {
+		select {
+		case r, ok := <-c.send:
+			if !ok {
+				return append(actions, "closed")
+			}
+			actions = append(actions, r.Action)
+		default:
+			return actions
+		}
+	}
+}
+
+func TestHubTargets(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	h := NewHub()
+	go h.Run(ctx)
+
+	admin := newTestClient(h, 1, "admin", 8)
+	adminTab := newTestClient(h, 1, "admin", 8)
+	staff := newTestClient(h, 2, "staff", 8)
+
+	h.SendToUser(1, WsJsonResponse{Action: "user"})
+	h.SendToRole("staff", WsJsonResponse{Action: "role"})
+	h.Broadcast(WsJsonResponse{Action: "everyone"})
+	h.SendToUser(3, WsJsonResponse{Action: "nobody"})
+
+	tests := []struct {
+		name   string
+		client *wsClient
+		want   []string
+	}{
+		{"admin", admin, []string{"user", "everyone"}},
+		{"second tab", adminTab, []string{"user", "everyone"}},
+		{"staff", staff, []string{"role", "everyone"}},
+	}
+	for _, tt := range tests {
+		got := received(h, tt.client)
+		if len(got) != len(tt.want) || got[0] != tt.want[0] || got[1] != tt.want[1] {
+			t.Errorf("%s got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	h.drop(adminTab)
+	if got := received(h, adminTab); len(got) != 1 || got[0] != "closed" {
+		t.Errorf("expected an unregistered client to be closed, got %v", got)
+	}
+
+	h.Broadcast(WsJsonResponse{Action: "after"})
+	if got := received(h, admin); len(got) != 1 || got[0] != "after" {
+		t.Errorf("expected the other tab to stay connected, got %v", got)
+	}
+}
+
+func TestHubDropsSlowClient(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	h := NewHub()
+	go h.Run(ctx)
+
+	slow := newTestClient(h, 1, "admin", 1)
+	fast := newTestClient(h, 2, "admin", 8)
+
+	h.Broadcast(WsJsonResponse{Action: "one"})
+	h.Broadcast(WsJsonResponse{Action: "two"})
+
+	if got := received(h, slow); len(got) != 2 || got[1] != "closed" {
+		t.Errorf("expected the slow client to be dropped after one message, got %v", got)
+	}
+	if got := received(h, fast); len(got) != 2 {
+		t.Errorf("expected the fast client to get both messages, got %v", got)
+	}
+
+	cancel()
+	<-h.done
+	if _, ok := <-fast.send; ok {
+		t.Error("expected clients to be closed when the hub stops")
+	}
+
+	// sending to a stopped hub must not block
+	h.Broadcast(WsJsonResponse{Action: "late"})
+}
+
+func TestCheckWsOrigin(t *testing.T) {
+	server := &Server{config: util.Config{
+		FrontendAddr:   "https://shop.example.com",
+		AllowedOrigins: []string{"https://admin.example.com/"},
+	}}
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"", true},
+		{"http://frontend.internal:3000", true},
+		{"https://shop.example.com", true},
+		{"https://Admin.example.com", true},
+		{"https://evil.example.com", false},
+		{"https://shop.example.com.evil.com", false},
+		{"null", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "http://frontend.internal:3000/ws", nil)
+		if tt.origin != "" {
+			r.Header.Set("Origin", tt.origin)
+		}
+		if got := server.checkWsOrigin(r); got != tt.want {
+			t.Errorf("checkWsOrigin(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
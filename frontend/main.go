@@ -41,7 +41,7 @@ func main() {
 		log.Fatal().Err(err).Msg("cannot load config")
 	}
 
-	log.Info().Interface("config", config).Msg("loaded config")
+	log.Info().Interface("config", config.Redacted()).Msg("loaded config")
 
 	// connPool, err := pgxpool.New(ctx, runtimeCfg.DBSource)
 	connPool, err := sql.Open("pgx", config.DBSource)
@@ -85,7 +85,12 @@ func runServer(
 	server.SetupRouter() // initialize routes
 
 	waitGroup.Go(func() error {
-		server.ListenToWsChannel(ctx)
+		server.RunWsHub(ctx)
+		return nil
+	})
+
+	waitGroup.Go(func() error {
+		server.ListenForEvents(ctx)
 		return nil
 	})
 
@@ -8,6 +8,8 @@ import (
 	"os"
 	"strings"
 
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/redact"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/storage"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
@@ -15,16 +17,61 @@ import (
 )
 
 type Config struct {
-	Environment       string `mapstructure:"ENVIRONMENT" json:"ENVIRONMENT"`
-	DBSource          string `mapstructure:"DB_SOURCE" json:"DB_SOURCE"`
-	FrontendPort      string `mapstructure:"FRONTEND_PORT" json:"FRONTEND_PORT"`
-	TokenSymmetricKey string `mapstructure:"TOKEN_SYMMETRIC_KEY" json:"TOKEN_SYMMETRIC_KEY"`
-	MainServerAddr    string `mapstructure:"MAIN_SERVER_ADDR" json:"MAIN_SERVER_ADDR"`
-	InvoiceGrpcAddr   string `mapstructure:"INVOICE_GRPC_ADDR" json:"INVOICE_GRPC_ADDR"`
-	FrontendAddr      string `mapstructure:"FRONTEND_ADDR" json:"FRONTEND_ADDR"`
-	FrontendWsAddr    string `mapstructure:"FRONTEND_WS_ADDR" json:"FRONTEND_WS_ADDR"`
-	StripeKey         string `mapstructure:"STRIPE_KEY" json:"STRIPE_KEY"`
-	StripeSecret      string `mapstructure:"STRIPE_SECRET" json:"STRIPE_SECRET"`
+	Environment       string   `mapstructure:"ENVIRONMENT" json:"ENVIRONMENT"`
+	DBSource          string   `mapstructure:"DB_SOURCE" json:"DB_SOURCE"`
+	FrontendPort      string   `mapstructure:"FRONTEND_PORT" json:"FRONTEND_PORT"`
+	TokenSymmetricKey string   `mapstructure:"TOKEN_SYMMETRIC_KEY" json:"TOKEN_SYMMETRIC_KEY"`
+	MainServerAddr    string   `mapstructure:"MAIN_SERVER_ADDR" json:"MAIN_SERVER_ADDR"`
+	FrontendAddr      string   `mapstructure:"FRONTEND_ADDR" json:"FRONTEND_ADDR"`
+	FrontendWsAddr    string   `mapstructure:"FRONTEND_WS_ADDR" json:"FRONTEND_WS_ADDR"`
+	AllowedOrigins    []string `mapstructure:"ALLOWED_ORIGINS" json:"ALLOWED_ORIGINS"`
+	TrustedProxies    []string `mapstructure:"TRUSTED_PROXIES" json:"TRUSTED_PROXIES"`
+	StripeKey         string   `mapstructure:"STRIPE_KEY" json:"STRIPE_KEY"`
+	StripeSecret      string   `mapstructure:"STRIPE_SECRET" json:"STRIPE_SECRET"`
+	OidcIssuerURL     string   `mapstructure:"OIDC_ISSUER_URL" json:"OIDC_ISSUER_URL"`
+	OidcClientID      string   `mapstructure:"OIDC_CLIENT_ID" json:"OIDC_CLIENT_ID"`
+	OidcClientSecret  string   `mapstructure:"OIDC_CLIENT_SECRET" json:"OIDC_CLIENT_SECRET"`
+	OidcRedirectURL   string   `mapstructure:"OIDC_REDIRECT_URL" json:"OIDC_REDIRECT_URL"`
+	OidcJitProvision  string   `mapstructure:"OIDC_JIT_PROVISION" json:"OIDC_JIT_PROVISION"`
+	OidcDefaultRole   string   `mapstructure:"OIDC_DEFAULT_ROLE" json:"OIDC_DEFAULT_ROLE"`
+	UploadStorage     string   `mapstructure:"UPLOAD_STORAGE" json:"UPLOAD_STORAGE"`
+	UploadStorageDir  string   `mapstructure:"UPLOAD_STORAGE_DIR" json:"UPLOAD_STORAGE_DIR"`
+	UploadS3Bucket    string   `mapstructure:"UPLOAD_S3_BUCKET" json:"UPLOAD_S3_BUCKET"`
+	UploadS3Region    string   `mapstructure:"UPLOAD_S3_REGION" json:"UPLOAD_S3_REGION"`
+	UploadS3Endpoint  string   `mapstructure:"UPLOAD_S3_ENDPOINT" json:"UPLOAD_S3_ENDPOINT"`
+	UploadS3AccessKey string   `mapstructure:"UPLOAD_S3_ACCESS_KEY" json:"UPLOAD_S3_ACCESS_KEY"`
+	UploadS3SecretKey string   `mapstructure:"UPLOAD_S3_SECRET_KEY" json:"UPLOAD_S3_SECRET_KEY"`
+}
+
+// UploadStorageConfig returns the configuration of the store uploaded files, such as item
+// images, are kept in
+func (config Config) UploadStorageConfig() storage.Config {
+	dir := config.UploadStorageDir
+	if dir == "" {
+		dir = "./uploads"
+	}
+
+	return storage.Config{
+		Driver: config.UploadStorage,
+		Dir:    dir,
+		S3: storage.S3Config{
+			Bucket:    config.UploadS3Bucket,
+			Region:    config.UploadS3Region,
+			Endpoint:  config.UploadS3Endpoint,
+			AccessKey: config.UploadS3AccessKey,
+			SecretKey: config.UploadS3SecretKey,
+		},
+	}
+}
+
+// Redacted returns config with its secrets masked, so it can be logged
+func (config Config) Redacted() Config {
+	config.DBSource = redact.URL(config.DBSource)
+	config.TokenSymmetricKey = redact.Secret(config.TokenSymmetricKey)
+	config.StripeSecret = redact.Secret(config.StripeSecret)
+	config.OidcClientSecret = redact.Secret(config.OidcClientSecret)
+	config.UploadS3SecretKey = redact.Secret(config.UploadS3SecretKey)
+	return config
 }
 
 // LoadConfig reads configuration from file or environment variables.
@@ -48,6 +95,7 @@ func LoadConfig(ctx context.Context, path string) (Config, error) {
 		if err != nil {
 			return config, fmt.Errorf("viper unmarshal error: %w", err)
 		}
+		config.Environment = environment
 		return config, nil
 
 	case "staging", "production":
@@ -68,6 +116,7 @@ func LoadConfig(ctx context.Context, path string) (Config, error) {
 		if err != nil {
 			return config, fmt.Errorf("unmarshal secret: %w", err)
 		}
+		config.Environment = environment
 		return config, nil
 
 	default:
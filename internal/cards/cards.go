@@ -1,6 +1,8 @@
 package cards
 
 import (
+	"time"
+
 	"github.com/stripe/stripe-go/v82"
 	"github.com/stripe/stripe-go/v82/customer"
 	"github.com/stripe/stripe-go/v82/paymentintent"
@@ -64,6 +66,58 @@ func (c *Card) GetPaymentMethod(s string) (*stripe.PaymentMethod, error) {
 	return pm, nil
 }
 
+// BillingCountry returns the country of the billing address of pm, falling back to the country
+// its card was issued in
+func BillingCountry(pm *stripe.PaymentMethod) string {
+	if pm.BillingDetails != nil && pm.BillingDetails.Address != nil && pm.BillingDetails.Address.Country != "" {
+		return pm.BillingDetails.Address.Country
+	}
+	if pm.Card != nil {
+		return pm.Card.Country
+	}
+	return ""
+}
+
+// InvoiceAdjustments are what Stripe applied to an invoice on top of its line items
+type InvoiceAdjustments struct {
+	Subtotal      int    // the line items, before discounts and exclusive tax
+	Discount      int    // the discounts, in total
+	DiscountLabel string // the promotion code or coupon of the first discount
+	TaxRateBps    int    // the exclusive tax on the discounted subtotal, in basis points
+}
+
+// Adjustments returns the discounts and exclusive tax of inv, such as the latest invoice of a
+// subscription. Taxes included in prices are part of the subtotal, so they are left out.
+func Adjustments(inv *stripe.Invoice) InvoiceAdjustments {
+	a := InvoiceAdjustments{Subtotal: int(inv.Subtotal)}
+
+	for _, d := range inv.TotalDiscountAmounts {
+		a.Discount += int(d.Amount)
+		if a.DiscountLabel != "" || d.Discount == nil {
+			continue
+		}
+		if d.Discount.PromotionCode != nil && d.Discount.PromotionCode.Code != "" {
+			a.DiscountLabel = d.Discount.PromotionCode.Code
+		} else if d.Discount.Coupon != nil {
+			a.DiscountLabel = d.Discount.Coupon.Name
+		}
+	}
+
+	var tax, taxable int64
+	for _, t := range inv.TotalTaxes {
+		if t.TaxBehavior != stripe.InvoiceTotalTaxTaxBehaviorExclusive {
+			continue
+		}
+		tax += t.Amount
+		taxable = max(taxable, t.TaxableAmount)
+	}
+	if tax > 0 && taxable > 0 {
+		a.TaxRateBps = int((tax*10000 + taxable/2) / taxable)
+	}
+
+	return a
+}
+
 // RetrievePaymentIntent gets an existing payment intent by id
 func (c *Card) RetrievePaymentIntent(id string) (*stripe.PaymentIntent, error) {
 	stripe.Key = c.Secret
@@ -119,8 +173,8 @@ func (c *Card) CreateCustomer(pm, email string) (*stripe.Customer, string, error
 	return cust, "", nil
 }
 
-// Refund refunds an amount for a paymentIntent
-func (c *Card) Refund(pi string, amount int) error {
+// Refund refunds an amount for a paymentIntent, and returns the id of the refund
+func (c *Card) Refund(pi string, amount int) (string, error) {
 	stripe.Key = c.Secret
 	amountToRefund := int64(amount)
 
@@ -129,27 +183,36 @@ func (c *Card) Refund(pi string, amount int) error {
 		PaymentIntent: &pi,
 	}
 
-	_, err := refund.New(refundParams)
+	rf, err := refund.New(refundParams)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	return nil
+	return rf.ID, nil
 }
 
-// CancelSubscription cancels a subscription, by subscription id
-func (c *Card) CancelSubscription(subID string) error {
+// CancelSubscription cancels a subscription, by subscription id, at the end of the current
+// period. It returns when the subscription ends.
+func (c *Card) CancelSubscription(subID string) (time.Time, error) {
 	stripe.Key = c.Secret
 
 	params := &stripe.SubscriptionParams{
 		CancelAtPeriodEnd: stripe.Bool(true),
 	}
 
-	_, err := subscription.Update(subID, params)
+	sub, err := subscription.Update(subID, params)
 	if err != nil {
-		return err
+		return time.Time{}, err
+	}
+
+	if sub.CancelAt != 0 {
+		return time.Unix(sub.CancelAt, 0), nil
+	}
+	// the period is kept on the items of the subscription
+	if sub.Items != nil && len(sub.Items.Data) > 0 {
+		return time.Unix(sub.Items.Data[0].CurrentPeriodEnd, 0), nil
 	}
-	return nil
+	return time.Now(), nil
 }
 
 // cardErrorMessage returns human readable versions of card error messages
@@ -0,0 +1,61 @@
+package cards
+
+import (
+	"testing"
+
+	"github.com/stripe/stripe-go/v82"
+)
+
+func TestAdjustments(t *testing.T) {
+	inv := &stripe.Invoice{
+		Subtotal: 2000,
+		TotalDiscountAmounts: []*stripe.InvoiceTotalDiscountAmount{
+			{Amount: 500, Discount: &stripe.Discount{Coupon: &stripe.Coupon{Name: "WELCOME5"}}},
+		},
+		TotalTaxes: []*stripe.InvoiceTotalTax{
+			{Amount: 285, TaxableAmount: 1500, TaxBehavior: stripe.InvoiceTotalTaxTaxBehaviorExclusive},
+			{Amount: 100, TaxableAmount: 1500, TaxBehavior: stripe.InvoiceTotalTaxTaxBehaviorInclusive},
+		},
+	}
+
+	want := InvoiceAdjustments{Subtotal: 2000, Discount: 500, DiscountLabel: "WELCOME5", TaxRateBps: 1900}
+	if got := Adjustments(inv); got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	if got := Adjustments(&stripe.Invoice{Subtotal: 2000}); got != (InvoiceAdjustments{Subtotal: 2000}) {
+		t.Fatalf("expected no adjustments, got %+v", got)
+	}
+}
+
+func TestBillingCountry(t *testing.T) {
+	tests := []struct {
+		name string
+		pm   *stripe.PaymentMethod
+		want string
+	}{
+		{
+			name: "billing address",
+			pm: &stripe.PaymentMethod{
+				BillingDetails: &stripe.PaymentMethodBillingDetails{Address: &stripe.Address{Country: "FR"}},
+				Card:           &stripe.PaymentMethodCard{Country: "US"},
+			},
+			want: "FR",
+		},
+		{
+			name: "card issuer",
+			pm: &stripe.PaymentMethod{
+				BillingDetails: &stripe.PaymentMethodBillingDetails{Address: &stripe.Address{}},
+				Card:           &stripe.PaymentMethodCard{Country: "US"},
+			},
+			want: "US",
+		},
+		{name: "neither", pm: &stripe.PaymentMethod{}, want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := BillingCountry(tt.pm); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package cards
+
+import (
+	"github.com/stripe/stripe-go/v82"
+	"github.com/stripe/stripe-go/v82/price"
+	"github.com/stripe/stripe-go/v82/product"
+)
+
+// Plan is a Stripe product with the monthly price customers subscribe to
+type Plan struct {
+	ProductID   string
+	PriceID     string
+	Name        string
+	Description string
+	// Amount is the monthly price, in cents of the card's currency
+	Amount int
+	Active bool
+}
+
+// PlanChange is what SyncPlan created in Stripe, so that it can be committed once the plan is
+// saved, or rolled back when it can't be
+type PlanChange struct {
+	NewProduct string // the product created, if any
+	NewPrice   string // the price created, if any
+	OldPrice   string // the price NewPrice replaces, left active until CommitPlan
+	OldProduct string // the product of a plan that is no longer sold, left active until CommitPlan
+}
+
+// SyncPlan creates or updates the Stripe product and monthly price of p, and returns p with
+// their ids, and what it created. Prices can't be changed in Stripe, so a new price is created
+// when the amount or currency changes; the old one stays active until CommitPlan, and existing
+// subscribers stay on it. On error, the change holds what was created before it.
+func (c *Card) SyncPlan(p Plan) (Plan, PlanChange, error) {
+	stripe.Key = c.Secret
+
+	var change PlanChange
+
+	var current *stripe.Price
+	if p.PriceID != "" {
+		var err error
+		current, err = price.Get(p.PriceID, nil)
+		if err != nil {
+			return p, change, err
+		}
+		// a plan set up by hand may only have its price
+		if p.ProductID == "" && current.Product != nil {
+			p.ProductID = current.Product.ID
+		}
+	}
+
+	productParams := &stripe.ProductParams{
+		Name:   stripe.String(p.Name),
+		Active: stripe.Bool(p.Active),
+	}
+	// Stripe refuses an empty description
+	if p.Description != "" {
+		productParams.Description = stripe.String(p.Description)
+	}
+
+	if p.ProductID == "" {
+		prod, err := product.New(productParams)
+		if err != nil {
+			return p, change, err
+		}
+		p.ProductID = prod.ID
+		change.NewProduct = prod.ID
+	} else if _, err := product.Update(p.ProductID, productParams); err != nil {
+		return p, change, err
+	}
+
+	if current != nil && current.Active && priceMatches(current, p.ProductID, int64(p.Amount), c.Currency) {
+		return p, change, nil
+	}
+
+	next, err := price.New(&stripe.PriceParams{
+		Product:    stripe.String(p.ProductID),
+		UnitAmount: stripe.Int64(int64(p.Amount)),
+		Currency:   stripe.String(c.Currency),
+		Recurring: &stripe.PriceRecurringParams{
+			Interval: stripe.String(string(stripe.PriceRecurringIntervalMonth)),
+		},
+	})
+	if err != nil {
+		return p, change, err
+	}
+	change.NewPrice = next.ID
+
+	if current != nil && current.Active {
+		change.OldPrice = current.ID
+	}
+
+	p.PriceID = next.ID
+	return p, change, nil
+}
+
+// CommitPlan deactivates the price a synced plan replaced, and the product of a plan that was
+// dropped, once the plan is saved
+func (c *Card) CommitPlan(change PlanChange) error {
+	stripe.Key = c.Secret
+
+	if change.OldPrice != "" {
+		if _, err := price.Update(change.OldPrice, &stripe.PriceParams{Active: stripe.Bool(false)}); err != nil {
+			return err
+		}
+	}
+
+	if change.OldProduct != "" {
+		if _, err := product.Update(change.OldProduct, &stripe.ProductParams{Active: stripe.Bool(false)}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RollbackPlan deactivates the product and price a synced plan created, when the plan can't be
+// saved. Stripe doesn't let products with prices be deleted.
+func (c *Card) RollbackPlan(change PlanChange) error {
+	stripe.Key = c.Secret
+
+	if change.NewPrice != "" {
+		if _, err := price.Update(change.NewPrice, &stripe.PriceParams{Active: stripe.Bool(false)}); err != nil {
+			return err
+		}
+	}
+
+	if change.NewProduct != "" {
+		if _, err := product.Update(change.NewProduct, &stripe.ProductParams{Active: stripe.Bool(false)}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// priceMatches reports whether pr is a monthly price of amount in currency, for product
+func priceMatches(pr *stripe.Price, productID string, amount int64, currency string) bool {
+	return pr.Product != nil && pr.Product.ID == productID &&
+		pr.UnitAmount == amount &&
+		string(pr.Currency) == currency &&
+		pr.Recurring != nil && pr.Recurring.Interval == stripe.PriceRecurringIntervalMonth && pr.Recurring.IntervalCount <= 1
+}
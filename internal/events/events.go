@@ -0,0 +1,141 @@
+// Package events carries domain events between services through Postgres LISTEN/NOTIFY, so that
+// every replica of a service hears about what happened on any other.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// Channel is the Postgres notification channel events are published on
+const Channel = "yoyo_events"
+
+// maxPayload is the largest notification Postgres accepts, less a little headroom
+const maxPayload = 7900
+
+// Types of events
+const (
+	UserDeleted           = "user.deleted"
+	SaleCreated           = "sale.created"
+	RefundIssued          = "refund.issued"
+	SubscriptionCancelled = "subscription.cancelled"
+)
+
+// Event is something that happened, that other services may want to act on. Only the fields
+// that apply to its type are set.
+type Event struct {
+	Type     string    `json:"type"`
+	UserID   int       `json:"user_id,omitempty"`
+	OrderID  int       `json:"order_id,omitempty"`
+	Product  string    `json:"product,omitempty"`
+	Amount   int       `json:"amount,omitempty"`
+	Currency string    `json:"currency,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// Encode returns e as a notification payload
+func Encode(e Event) (string, error) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	if len(b) > maxPayload {
+		return "", fmt.Errorf("event %s is %d bytes, more than a notification can carry", e.Type, len(b))
+	}
+	return string(b), nil
+}
+
+// Decode reads an event from a notification payload
+func Decode(payload string) (Event, error) {
+	var e Event
+	if err := json.Unmarshal([]byte(payload), &e); err != nil {
+		return e, err
+	}
+	if e.Type == "" {
+		return e, errors.New("event has no type")
+	}
+	return e, nil
+}
+
+const (
+	// minBackoff and maxBackoff bound the wait between attempts to reconnect
+	minBackoff = time.Second
+	maxBackoff = 30 * time.Second
+	// healthCheck is how often a quiet connection is pinged, so a database that failed over
+	// without closing it is noticed
+	healthCheck = 30 * time.Second
+)
+
+// Listen calls handle with every event published on Channel, until ctx is done. It has its
+// own connection to dsn, and reconnects whenever that is lost, e.g. when the database fails
+// over; events published while it is reconnecting are missed.
+func Listen(ctx context.Context, dsn string, handle func(Event)) {
+	backoff := minBackoff
+	for {
+		err := listen(ctx, dsn, handle, func() { backoff = minBackoff })
+		if ctx.Err() != nil {
+			log.Info().Msg("event listener stopped")
+			return
+		}
+
+		log.Error().Err(err).Dur("retry_in", backoff).Msg("events.Listen")
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("event listener stopped")
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// listen listens on one connection until it fails, calling connected once it is listening
+func listen(ctx context.Context, dsn string, handle func(Event), connected func()) error {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = conn.Close(closeCtx)
+	}()
+
+	if _, err := conn.Exec(ctx, "listen "+pgx.Identifier{Channel}.Sanitize()); err != nil {
+		return err
+	}
+	connected()
+	log.Info().Str("channel", Channel).Msg("listening for events")
+
+	for {
+		waitCtx, cancel := context.WithTimeout(ctx, healthCheck)
+		n, err := conn.WaitForNotification(waitCtx)
+		cancel()
+
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			err = conn.Ping(pingCtx)
+			cancel()
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		e, err := Decode(n.Payload)
+		if err != nil {
+			log.Error().Err(err).Str("payload", n.Payload).Msg("events.Listen")
+			continue
+		}
+		handle(e)
+	}
+}
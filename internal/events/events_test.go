@@ -0,0 +1,45 @@
+package events
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	e := Event{
+		Type:     SaleCreated,
+		OrderID:  12,
+		Product:  "Bronze Plan",
+		Amount:   2000,
+		Currency: "usd",
+		At:       time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC),
+	}
+
+	payload, err := Encode(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Decode(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != e {
+		t.Errorf("got %+v, want %+v", got, e)
+	}
+}
+
+func TestEncodeTooLarge(t *testing.T) {
+	if _, err := Encode(Event{Type: SaleCreated, Product: strings.Repeat("x", 8000)}); err == nil {
+		t.Error("expected an event too large for a notification to be refused")
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, payload := range []string{"", "not json", `{"order_id": 1}`} {
+		if _, err := Decode(payload); err == nil {
+			t.Errorf("Decode(%q) should fail", payload)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package grpcauth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var ErrInsecureNotAllowed = errors.New("TLS certificates and a service secret are required outside the develop environment")
+
+// TLSFiles are the PEM files used to secure a gRPC connection
+type TLSFiles struct {
+	CAFile   string // server: CA for client certificates, which enables mTLS; client: CA for the server certificate
+	CertFile string
+	KeyFile  string
+}
+
+// InsecureAllowed reports whether plain text connections and unauthenticated calls
+// are allowed in environment. Only develop allows them; an unknown or empty environment,
+// such as a config that failed to record it, doesn't.
+func InsecureAllowed(environment string) bool {
+	return strings.EqualFold(environment, "develop")
+}
+
+// ServerOptions returns the options that secure a gRPC server with TLS, mTLS when
+// files.CAFile is set, and service tokens signed with secret
+func ServerOptions(environment string, files TLSFiles, secret string) ([]grpc.ServerOption, error) {
+	var opts []grpc.ServerOption
+
+	if files.CertFile == "" && files.KeyFile == "" {
+		if !InsecureAllowed(environment) {
+			return nil, ErrInsecureNotAllowed
+		}
+	} else {
+		cert, err := tls.LoadX509KeyPair(files.CertFile, files.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load server certificate: %w", err)
+		}
+
+		tlsConfig := &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}
+
+		if files.CAFile != "" {
+			pool, err := loadCertPool(files.CAFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.ClientCAs = pool
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	if secret == "" {
+		if !InsecureAllowed(environment) {
+			return nil, ErrInsecureNotAllowed
+		}
+		return opts, nil
+	}
+
+	verifier := &Verifier{Secret: []byte(secret)}
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(verifier.UnaryServerInterceptor),
+		grpc.ChainStreamInterceptor(verifier.StreamServerInterceptor),
+	)
+
+	return opts, nil
+}
+
+// DialOptions returns the options a client needs to call a server set up with ServerOptions.
+// service names the caller in its service tokens.
+func DialOptions(environment string, files TLSFiles, service, secret string) ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+
+	secure := files.CAFile != "" || files.CertFile != ""
+
+	if !secure {
+		if !InsecureAllowed(environment) {
+			return nil, ErrInsecureNotAllowed
+		}
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	} else {
+		tlsConfig := &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		}
+
+		if files.CAFile != "" {
+			pool, err := loadCertPool(files.CAFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = pool
+		}
+
+		if files.CertFile != "" {
+			cert, err := tls.LoadX509KeyPair(files.CertFile, files.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("load client certificate: %w", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	}
+
+	if secret == "" {
+		if !InsecureAllowed(environment) {
+			return nil, ErrInsecureNotAllowed
+		}
+		return opts, nil
+	}
+
+	opts = append(opts, grpc.WithPerRPCCredentials(&TokenCredentials{
+		Service:    service,
+		Secret:     []byte(secret),
+		RequireTLS: secure,
+	}))
+
+	return opts, nil
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read CA file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", file)
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,73 @@
+package grpcauth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestToken(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Now()
+	token := SignToken(secret, "server_main", now)
+
+	service, err := ParseToken(secret, token, now)
+	if err != nil || service != "server_main" {
+		t.Fatalf("got %q, %v", service, err)
+	}
+
+	if _, err := ParseToken([]byte("other"), token, now); err == nil {
+		t.Error("expected a token signed with another secret to be rejected")
+	}
+
+	if _, err := ParseToken(secret, token, now.Add(MaxClockSkew+time.Second)); err == nil {
+		t.Error("expected an old token to be rejected")
+	}
+
+	if _, err := ParseToken(secret, "frontend"+token[len("server_main"):], now); err == nil {
+		t.Error("expected a tampered token to be rejected")
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	v := &Verifier{Secret: []byte("secret")}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return ServiceFromContext(ctx), nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"authorization", "Bearer "+SignToken(v.Secret, "frontend", time.Now()),
+	))
+	resp, err := v.UnaryServerInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	if err != nil || resp != "frontend" {
+		t.Fatalf("got %v, %v", resp, err)
+	}
+
+	_, err = v.UnaryServerInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("got %v, want Unauthenticated", err)
+	}
+}
+
+func TestInsecureOnlyInDevelop(t *testing.T) {
+	if _, err := ServerOptions("develop", TLSFiles{}, ""); err != nil {
+		t.Errorf("develop: %v", err)
+	}
+	if _, err := DialOptions("develop", TLSFiles{}, "frontend", ""); err != nil {
+		t.Errorf("develop client: %v", err)
+	}
+	if _, err := DialOptions("", TLSFiles{}, "frontend", ""); err != ErrInsecureNotAllowed {
+		t.Errorf("empty environment: got %v", err)
+	}
+	if _, err := ServerOptions("production", TLSFiles{}, "secret"); err != ErrInsecureNotAllowed {
+		t.Errorf("production server: got %v", err)
+	}
+	if _, err := DialOptions("staging", TLSFiles{}, "frontend", "secret"); err != ErrInsecureNotAllowed {
+		t.Errorf("staging client: got %v", err)
+	}
+}
@@ -0,0 +1,251 @@
+// Package i18n holds the translation catalogs for the text customers see in invoices and
+// emails, and formats dates, numbers and money the way each language writes them.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+)
+
+// DefaultLocale is used for customers without a locale, or whose locale has no catalog
+const DefaultLocale = "en"
+
+//go:embed catalogs/*.json
+var catalogFS embed.FS
+
+// Catalog is the translated strings and number formats of one language
+type Catalog struct {
+	locale   string
+	format   format
+	messages map[string]string
+	fallback *Catalog
+}
+
+// format is how a language writes dates and numbers. Date, Money and Percent are patterns
+// with {placeholders}.
+type format struct {
+	Date    string   `json:"date"`
+	Months  []string `json:"months"`
+	Decimal string   `json:"decimal"`
+	Group   string   `json:"group"`
+	Money   string   `json:"money"`
+	Percent string   `json:"percent"`
+}
+
+var catalogs = mustLoad()
+
+func mustLoad() map[string]*Catalog {
+	files, err := catalogFS.ReadDir("catalogs")
+	if err != nil {
+		panic(err)
+	}
+
+	out := map[string]*Catalog{}
+	for _, f := range files {
+		data, err := catalogFS.ReadFile(path.Join("catalogs", f.Name()))
+		if err != nil {
+			panic(err)
+		}
+
+		var file struct {
+			Format   format            `json:"format"`
+			Messages map[string]string `json:"messages"`
+		}
+		if err := json.Unmarshal(data, &file); err != nil {
+			panic(fmt.Sprintf("i18n: %s: %v", f.Name(), err))
+		}
+		if len(file.Format.Months) != 12 {
+			panic(fmt.Sprintf("i18n: %s: expected 12 months", f.Name()))
+		}
+
+		locale := strings.TrimSuffix(f.Name(), ".json")
+		out[locale] = &Catalog{locale: locale, format: file.Format, messages: file.Messages}
+	}
+
+	en, ok := out[DefaultLocale]
+	if !ok {
+		panic("i18n: no catalog for " + DefaultLocale)
+	}
+	for locale, c := range out {
+		if locale != DefaultLocale {
+			c.fallback = en
+		}
+	}
+
+	return out
+}
+
+// Supported returns the locales there is a catalog for, sorted
+func Supported() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Match returns the supported locale for a BCP 47 language tag, e.g. de for de-AT, or
+// DefaultLocale if the language has no catalog
+func Match(tag string) string {
+	if locale, ok := match(tag); ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+func match(tag string) (string, bool) {
+	lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	lang, _, _ = strings.Cut(lang, "_")
+	_, ok := catalogs[lang]
+	return lang, ok
+}
+
+// FromAcceptLanguage returns the supported locale a browser prefers, going by the quality
+// values of an Accept-Language header, or DefaultLocale if none of them is supported
+func FromAcceptLanguage(header string) string {
+	type choice struct {
+		locale  string
+		quality float64
+	}
+
+	var choices []choice
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		locale, ok := match(tag)
+		if !ok || q <= 0 {
+			continue
+		}
+		choices = append(choices, choice{locale, q})
+	}
+
+	sort.SliceStable(choices, func(i, j int) bool { return choices[i].quality > choices[j].quality })
+	if len(choices) > 0 {
+		return choices[0].locale
+	}
+	return DefaultLocale
+}
+
+// For returns the catalog for locale, falling back to English
+func For(locale string) *Catalog {
+	return catalogs[Match(locale)]
+}
+
+// Locale returns the locale of the catalog, e.g. de
+func (c *Catalog) Locale() string {
+	return c.locale
+}
+
+// T returns the translation of key, formatted with args as by fmt.Sprintf. Keys missing from
+// the catalog are looked up in English, and returned as is if English has none either.
+func (c *Catalog) T(key string, args ...interface{}) string {
+	msg, ok := c.messages[key]
+	if !ok && c.fallback != nil {
+		msg, ok = c.fallback.messages[key]
+	}
+	if !ok {
+		msg = key
+	}
+
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Date formats t as a long date, e.g. January 2, 2006 or 2. Januar 2006
+func (c *Catalog) Date(t time.Time) string {
+	return strings.NewReplacer(
+		"{day}", strconv.Itoa(t.Day()),
+		"{month}", c.format.Months[t.Month()-1],
+		"{year}", strconv.Itoa(t.Year()),
+	).Replace(c.format.Date)
+}
+
+// Number formats n, a number with decimals implied digits after the decimal point, e.g.
+// 123456 with 2 decimals as 1,234.56 or 1.234,56
+func (c *Catalog) Number(n, decimals int) string {
+	sign := ""
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+
+	s := strconv.Itoa(n)
+	if len(s) <= decimals {
+		s = strings.Repeat("0", decimals-len(s)+1) + s
+	}
+	whole, frac := s[:len(s)-decimals], s[len(s)-decimals:]
+
+	for i := len(whole) - 3; i > 0; i -= 3 {
+		whole = whole[:i] + c.format.Group + whole[i:]
+	}
+	if decimals == 0 {
+		return sign + whole
+	}
+	return sign + whole + c.format.Decimal + frac
+}
+
+var currencySymbols = map[string]string{
+	"usd": "$",
+	"eur": "€",
+	"gbp": "£",
+}
+
+// Money formats an amount in the smallest unit of currency, e.g. 123456 usd as $1,234.56.
+// Currencies without a well known symbol are written with their code, as in 10.00 SGD. An
+// empty currency is taken to be usd.
+func (c *Catalog) Money(amount int, currency string) string {
+	currency = strings.ToLower(currency)
+	if currency == "" {
+		currency = "usd"
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	number := c.Number(amount, models.CurrencyDecimals(currency))
+
+	symbol, ok := currencySymbols[currency]
+	if !ok {
+		return sign + number + " " + strings.ToUpper(currency)
+	}
+	return sign + strings.NewReplacer("{amount}", number, "{symbol}", symbol).Replace(c.format.Money)
+}
+
+// Percent formats a rate in basis points as a percentage, e.g. 825 as 8.25% or 8,25 %
+func (c *Catalog) Percent(bps int) string {
+	number := c.Number(bps, 2)
+	if bps%100 == 0 {
+		number = c.Number(bps/100, 0)
+	} else if bps%10 == 0 {
+		number = c.Number(bps/10, 1)
+	}
+	return strings.Replace(c.format.Percent, "{number}", number, 1)
+}
+
+// Funcs returns the template functions t, date and money, bound to the catalog
+func (c *Catalog) Funcs() map[string]interface{} {
+	return map[string]interface{}{
+		"t":     c.T,
+		"date":  c.Date,
+		"money": c.Money,
+	}
+}
@@ -0,0 +1,133 @@
+package i18n
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestMatch(t *testing.T) {
+	tests := map[string]string{
+		"de":    "de",
+		"de-AT": "de",
+		"fr_CA": "fr",
+		"EN-us": "en",
+		"ja":    DefaultLocale,
+		"":      DefaultLocale,
+	}
+
+	for tag, want := range tests {
+		if got := Match(tag); got != want {
+			t.Errorf("Match(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
+
+func TestFromAcceptLanguage(t *testing.T) {
+	tests := map[string]string{
+		"de-DE,de;q=0.9,en;q=0.8": "de",
+		"ja,fr;q=0.5,en;q=0.7":    "en",
+		"en;q=0.2, es-MX;q=0.9":   "es",
+		"ja, *;q=0.1":             DefaultLocale,
+		"fr;q=0, de;q=0.3":        "de",
+		"":                        DefaultLocale,
+	}
+
+	for header, want := range tests {
+		if got := FromAcceptLanguage(header); got != want {
+			t.Errorf("FromAcceptLanguage(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := For("de").T("invoice.paid_by", "Visa", "4242"); got != "Bezahlt mit Visa, Endziffern 4242" {
+		t.Errorf("got %q", got)
+	}
+
+	// a key missing from every catalog comes back as is
+	if got := For("fr").T("no.such.key"); got != "no.such.key" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestCatalogsAreComplete(t *testing.T) {
+	en := catalogs[DefaultLocale]
+	for _, locale := range Supported() {
+		for key := range en.messages {
+			if _, ok := catalogs[locale].messages[key]; !ok {
+				t.Errorf("%s has no translation for %s", locale, key)
+			}
+		}
+	}
+}
+
+func TestFormatting(t *testing.T) {
+	date := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale  string
+		date    string
+		money   string
+		percent string
+	}{
+		{"en", "March 1, 2026", "-$1,234.56", "8.25%"},
+		{"de", "1. März 2026", "-1.234,56\u00a0$", "8,25\u00a0%"},
+		{"fr", "1 mars 2026", "-1\u00a0234,56\u00a0$", "8,25\u00a0%"},
+		{"es", "1 de marzo de 2026", "-1.234,56\u00a0$", "8,25\u00a0%"},
+	}
+
+	for _, tt := range tests {
+		c := For(tt.locale)
+		if got := c.Date(date); got != tt.date {
+			t.Errorf("%s: Date = %q, want %q", tt.locale, got, tt.date)
+		}
+		if got := c.Money(-123456, "usd"); got != tt.money {
+			t.Errorf("%s: Money = %q, want %q", tt.locale, got, tt.money)
+		}
+		if got := c.Percent(825); got != tt.percent {
+			t.Errorf("%s: Percent = %q, want %q", tt.locale, got, tt.percent)
+		}
+	}
+
+	en := For("en")
+	for _, tt := range []struct {
+		amount   int
+		currency string
+		want     string
+	}{
+		{5, "", "$0.05"},
+		{1999, "eur", "€19.99"},
+		{250000, "vnd", "250,000 VND"},
+		{1000, "sgd", "10.00 SGD"},
+	} {
+		if got := en.Money(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("Money(%d, %q) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+	if got := en.Percent(1000); got != "10%" {
+		t.Errorf("Percent(1000) = %q", got)
+	}
+	if got := en.Percent(750); got != "7.5%" {
+		t.Errorf("Percent(750) = %q", got)
+	}
+}
+
+func TestTemplateFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/invoice.html.tmpl":    {},
+		"templates/invoice.de.html.tmpl": {},
+	}
+
+	tests := map[string]string{
+		"de-DE": "templates/invoice.de.html.tmpl",
+		"fr":    "templates/invoice.html.tmpl",
+		"":      "templates/invoice.html.tmpl",
+	}
+
+	for locale, want := range tests {
+		if got := TemplateFile(fsys, "templates", "invoice", "html", locale); got != want {
+			t.Errorf("TemplateFile for %q = %q, want %q", locale, got, want)
+		}
+	}
+}
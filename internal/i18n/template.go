@@ -0,0 +1,20 @@
+package i18n
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+)
+
+// TemplateFile returns the path of the kind (html or plain) of the email template name in
+// dir, preferring a variant written for locale, e.g. invoice.de.html.tmpl, and falling back
+// to the English invoice.html.tmpl
+func TemplateFile(fsys fs.FS, dir, name, kind, locale string) string {
+	if locale = Match(locale); locale != DefaultLocale {
+		variant := path.Join(dir, fmt.Sprintf("%s.%s.%s.tmpl", name, locale, kind))
+		if _, err := fs.Stat(fsys, variant); err == nil {
+			return variant
+		}
+	}
+	return path.Join(dir, fmt.Sprintf("%s.%s.tmpl", name, kind))
+}
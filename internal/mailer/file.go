@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// File writes each message to a directory as an .eml file, which mail clients can open. It
+// is meant for local development.
+type File struct {
+	dir string
+}
+
+// NewFile returns a file transport writing to dir, creating dir if needed
+func NewFile(dir string) (*File, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &File{dir: dir}, nil
+}
+
+// Send writes msg to a new file named after the time it was sent
+func (f *File) Send(ctx context.Context, msg Message) error {
+	email, err := msg.email()
+	if err != nil {
+		return err
+	}
+
+	prefix := fmt.Sprintf("%s-*.eml", time.Now().UTC().Format("20060102T150405.000000000"))
+	file, err := os.CreateTemp(f.dir, prefix)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.WriteString(email.GetMessage()); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,21 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Log only logs who each message is for and its subject. Nothing is delivered.
+type Log struct{}
+
+// Send logs msg
+func (Log) Send(ctx context.Context, msg Message) error {
+	log.Info().
+		Str("from", msg.From).
+		Str("to", msg.To).
+		Str("subject", msg.Subject).
+		Int("attachments", len(msg.Attachments)).
+		Msg("mail")
+	return nil
+}
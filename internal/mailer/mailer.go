@@ -0,0 +1,119 @@
+// Package mailer sends email through a pluggable transport, and queues it in a persistent
+// outbox so that a mail outage doesn't fail the request that sent it.
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	mail "github.com/xhit/go-simple-mail/v2"
+)
+
+// Message is an email ready to send, with its HTML and plain text bodies already rendered
+type Message struct {
+	From        string       `json:"from"`
+	To          string       `json:"to"`
+	Subject     string       `json:"subject"`
+	HTML        string       `json:"html"`
+	Plain       string       `json:"plain"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+	// Ref says what the email is about, e.g. invoice:12. An Outbox hands it to its OnDelivered
+	// hook once the email is sent.
+	Ref string `json:"-"`
+	// Template, Locale and Data, the template data as JSON, are set instead of HTML and Plain by
+	// services without the templates. The Outbox sending the message renders it with its
+	// RenderWith hook.
+	Template string          `json:"template,omitempty"`
+	Locale   string          `json:"locale,omitempty"`
+	Data     json.RawMessage `json:"data,omitempty"`
+}
+
+// Attachment is a file attached to an email
+type Attachment struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+}
+
+// Mailer sends email
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Config selects and configures a transport
+type Config struct {
+	Driver string // smtp (the default), file, log or memory
+	Dir    string // where the file driver writes .eml files
+
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPPoolSize string // idle connections kept open, 2 by default
+}
+
+// New returns the transport selected by config.Driver
+func New(config Config) (Mailer, error) {
+	switch config.Driver {
+	case "", "smtp":
+		port, err := strconv.Atoi(config.SMTPPort)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SMTP_PORT %q: %w", config.SMTPPort, err)
+		}
+
+		poolSize := 2
+		if config.SMTPPoolSize != "" {
+			poolSize, err = strconv.Atoi(config.SMTPPoolSize)
+			if err != nil || poolSize < 1 {
+				return nil, fmt.Errorf("invalid SMTP_POOL_SIZE %q", config.SMTPPoolSize)
+			}
+		}
+
+		return NewSMTP(SMTPConfig{
+			Host:     config.SMTPHost,
+			Port:     port,
+			Username: config.SMTPUsername,
+			Password: config.SMTPPassword,
+			PoolSize: poolSize,
+		}), nil
+
+	case "file":
+		dir := config.Dir
+		if dir == "" {
+			dir = "./mail"
+		}
+		return NewFile(dir)
+
+	case "log":
+		return Log{}, nil
+
+	case "memory":
+		return &Memory{}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown MAIL_DRIVER %q: must be smtp, file, log or memory", config.Driver)
+	}
+}
+
+// email builds the MIME message for msg
+func (msg Message) email() (*mail.Email, error) {
+	email := mail.NewMSG()
+	email.SetFrom(msg.From).
+		AddTo(msg.To).
+		SetSubject(msg.Subject)
+
+	email.SetBody(mail.TextHTML, msg.HTML)
+	email.AddAlternative(mail.TextPlain, msg.Plain)
+
+	for _, x := range msg.Attachments {
+		email.Attach(&mail.File{
+			Name:     x.Name,
+			MimeType: x.ContentType,
+			Data:     x.Data,
+		})
+	}
+
+	return email, email.GetError()
+}
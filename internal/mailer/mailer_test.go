@@ -0,0 +1,257 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"go.uber.org/mock/gomock"
+)
+
+// mailerFunc adapts a function to the Mailer interface
+type mailerFunc func(ctx context.Context, msg Message) error
+
+func (f mailerFunc) Send(ctx context.Context, msg Message) error {
+	return f(ctx, msg)
+}
+
+var testMessage = Message{
+	From:    "info@yoyo.com",
+	To:      "jane@example.com",
+	Subject: "Your invoice",
+	HTML:    "<p>Please find your invoice attached.</p>",
+	Plain:   "Please find your invoice attached.",
+	Attachments: []Attachment{
+		{Name: "INV-2026-000001.pdf", ContentType: "application/pdf", Data: []byte("%PDF-1.3")},
+	},
+	Ref: "invoice:1",
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		config Config
+		want   string
+	}{
+		{Config{SMTPPort: "587"}, "*mailer.SMTP"},
+		{Config{Driver: "file", Dir: t.TempDir()}, "*mailer.File"},
+		{Config{Driver: "log"}, "mailer.Log"},
+		{Config{Driver: "memory"}, "*mailer.Memory"},
+	}
+
+	for _, tt := range tests {
+		m, err := New(tt.config)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.want, err)
+		}
+		if got := fmt.Sprintf("%T", m); got != tt.want {
+			t.Errorf("got %s, want %s", got, tt.want)
+		}
+	}
+
+	for _, bad := range []Config{{Driver: "pigeon"}, {SMTPPort: "smtp"}, {SMTPPort: "25", SMTPPoolSize: "0"}} {
+		if _, err := New(bad); err == nil {
+			t.Errorf("expected an error for %+v", bad)
+		}
+	}
+}
+
+func TestFile(t *testing.T) {
+	dir := t.TempDir()
+	f, err := NewFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Send(context.Background(), testMessage); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected one .eml file, got %v (%v)", files, err)
+	}
+
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"To: <jane@example.com>", "Subject: Your invoice", "multipart/alternative", `filename="INV-2026-000001.pdf"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected the message to contain %q", want)
+		}
+	}
+}
+
+func TestOutboxSendQueues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockOutboxStore(ctrl)
+	transport := &Memory{}
+
+	mockStore.EXPECT().InsertMailOutbox("jane@example.com", "Your invoice", "invoice:1", gomock.Any()).
+		DoAndReturn(func(_, _, _ string, payload []byte) (int, error) {
+			var msg Message
+			if err := json.Unmarshal(payload, &msg); err != nil {
+				t.Fatal(err)
+			}
+			if string(msg.Attachments[0].Data) != "%PDF-1.3" {
+				t.Errorf("expected the attachment to be queued, got %q", msg.Attachments[0].Data)
+			}
+			return 1, nil
+		})
+
+	if err := NewOutbox(mockStore, transport).Send(context.Background(), testMessage); err != nil {
+		t.Fatal(err)
+	}
+	if len(transport.Messages()) != 0 {
+		t.Fatal("expected the message to be queued, not sent")
+	}
+}
+
+func TestOutboxDispatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockOutboxStore(ctrl)
+
+	payload, err := json.Marshal(testMessage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	delivered := &models.MailOutboxEntry{ID: 1, Attempts: 1, Ref: "invoice:1", Payload: payload}
+	retried := &models.MailOutboxEntry{ID: 2, Attempts: 2, Ref: "invoice:2", Payload: payload}
+	exhausted := &models.MailOutboxEntry{ID: 3, Attempts: outboxMaxAttempts, Payload: payload}
+	unreadable := &models.MailOutboxEntry{ID: 4, Attempts: 1, Payload: []byte("{")}
+
+	d := NewOutbox(mockStore, nil).dispatcher()
+
+	// messages are claimed one at a time, for longer than they can take to send, so another
+	// replica can't claim one while it is being sent
+	if d.Lease() <= outboxSendTimeout {
+		t.Fatalf("the lease (%v) must outlast a send (%v)", d.Lease(), outboxSendTimeout)
+	}
+	gomock.InOrder(
+		mockStore.EXPECT().ClaimMailOutbox(1, d.Lease()).Return([]*models.MailOutboxEntry{delivered}, nil),
+		mockStore.EXPECT().ClaimMailOutbox(1, d.Lease()).Return([]*models.MailOutboxEntry{retried}, nil),
+		mockStore.EXPECT().ClaimMailOutbox(1, d.Lease()).Return([]*models.MailOutboxEntry{exhausted}, nil),
+		mockStore.EXPECT().ClaimMailOutbox(1, d.Lease()).Return([]*models.MailOutboxEntry{unreadable}, nil),
+		mockStore.EXPECT().ClaimMailOutbox(1, d.Lease()).Return(nil, nil),
+	)
+
+	down := errors.New("421 service not available")
+	calls := 0
+	transport := mailerFunc(func(ctx context.Context, msg Message) error {
+		if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > outboxSendTimeout {
+			t.Errorf("expected the send to time out within %v", outboxSendTimeout)
+		}
+		calls++
+		if calls == 1 {
+			return nil
+		}
+		return down
+	})
+
+	mockStore.EXPECT().MarkMailOutboxDelivered(1).Return(nil)
+	mockStore.EXPECT().RescheduleMailOutbox(2, down.Error(), gomock.Any()).
+		DoAndReturn(func(_ int, _ string, at time.Time) error {
+			if wait := time.Until(at); wait < 50*time.Second || wait > time.Minute {
+				t.Errorf("expected the second attempt to be retried in about a minute, got %v", wait)
+			}
+			return nil
+		})
+	mockStore.EXPECT().DeadLetterMailOutbox(3, down.Error()).Return(nil)
+	mockStore.EXPECT().DeadLetterMailOutbox(4, gomock.Any()).Return(nil)
+
+	// only delivered messages are reported, and a failing hook still leaves them delivered
+	var refs []string
+	o := NewOutbox(mockStore, transport)
+	o.OnDelivered(func(ref string) error {
+		refs = append(refs, ref)
+		return errors.New("invoice not found")
+	})
+
+	d = o.dispatcher()
+	n := 0
+	for d.Next(context.Background()) {
+		n++
+	}
+	if n != 4 {
+		t.Fatalf("expected 4 messages to be dispatched, got %d", n)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 messages to be handed to the transport, got %d", calls)
+	}
+	if len(refs) != 1 || refs[0] != "invoice:1" {
+		t.Fatalf("expected only invoice:1 to be reported delivered, got %v", refs)
+	}
+}
+
+func TestOutboxPurge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockOutboxStore(ctrl)
+
+	mockStore.EXPECT().PurgeMailOutbox(gomock.Any()).
+		DoAndReturn(func(before time.Time) (int, error) {
+			if age := time.Since(before); age < outboxRetention || age > outboxRetention+time.Minute {
+				t.Errorf("expected messages older than %v to be purged, got %v", outboxRetention, age)
+			}
+			return 3, nil
+		})
+
+	NewOutbox(mockStore, &Memory{}).purge()
+}
+
+func TestOutboxRendersTemplates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockOutboxStore(ctrl)
+	transport := &Memory{}
+
+	queued := Message{
+		From:     "info@yoyo.com",
+		To:       "jane@example.com",
+		Subject:  "Your order",
+		Template: "order-confirmation",
+		Locale:   "de",
+		Data:     json.RawMessage(`{"OrderID":7}`),
+	}
+	payload, err := json.Marshal(queued)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockStore.EXPECT().MarkMailOutboxDelivered(1).Return(nil)
+	mockStore.EXPECT().RescheduleMailOutbox(2, "no renderer for template order-confirmation", gomock.Any()).Return(nil)
+
+	o := NewOutbox(mockStore, transport)
+	o.RenderWith(func(tmpl, locale string, data json.RawMessage) (string, string, error) {
+		return fmt.Sprintf("<p>%s %s %s</p>", tmpl, locale, data), "plain", nil
+	})
+	o.deliver(context.Background(), &models.MailOutboxEntry{ID: 1, Attempts: 1, Payload: payload})
+
+	sent := transport.Messages()
+	if len(sent) != 1 {
+		t.Fatalf("expected 1 message to be sent, got %d", len(sent))
+	}
+	if want := `<p>order-confirmation de {"OrderID":7}</p>`; sent[0].HTML != want || sent[0].Plain != "plain" {
+		t.Errorf("expected the message to be rendered as %q, got %q and %q", want, sent[0].HTML, sent[0].Plain)
+	}
+
+	// without a renderer the message is retried, rather than sent without a body
+	NewOutbox(mockStore, transport).deliver(context.Background(), &models.MailOutboxEntry{ID: 2, Attempts: 1, Payload: payload})
+	if len(transport.Messages()) != 1 {
+		t.Fatal("expected the message not to be sent")
+	}
+}
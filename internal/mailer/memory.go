@@ -0,0 +1,29 @@
+package mailer
+
+import (
+	"context"
+	"sync"
+)
+
+// Memory keeps the messages it is sent, for tests and local development
+type Memory struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// Send records msg
+func (m *Memory) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages returns the messages sent so far, oldest first
+func (m *Memory) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Message(nil), m.messages...)
+}
@@ -0,0 +1,213 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/LamThanhNguyen/yoyo-store-backend/internal/mailer (interfaces: OutboxStore,TemplateStore)
+//
+// Generated by this command:
+//
+//	mockgen -package mailer -destination internal/mailer/mock_stores_test.go github.com/LamThanhNguyen/yoyo-store-backend/internal/mailer OutboxStore,TemplateStore
+//
+
+// Package mailer is a generated GoMock package.
+package mailer
+
+import (
+	reflect "reflect"
+	time "time"
+
+	models "github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOutboxStore is a mock of OutboxStore interface.
+type MockOutboxStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockOutboxStoreMockRecorder is the mock recorder for MockOutboxStore.
+type MockOutboxStoreMockRecorder struct {
+	mock *MockOutboxStore
+}
+
+// NewMockOutboxStore creates a new mock instance.
+func NewMockOutboxStore(ctrl *gomock.Controller) *MockOutboxStore {
+	mock := &MockOutboxStore{ctrl: ctrl}
+	mock.recorder = &MockOutboxStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxStore) EXPECT() *MockOutboxStoreMockRecorder {
+	return m.recorder
+}
+
+// ClaimMailOutbox mocks base method.
+func (m *MockOutboxStore) ClaimMailOutbox(limit int, lease time.Duration) ([]*models.MailOutboxEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimMailOutbox", limit, lease)
+	ret0, _ := ret[0].([]*models.MailOutboxEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimMailOutbox indicates an expected call of ClaimMailOutbox.
+func (mr *MockOutboxStoreMockRecorder) ClaimMailOutbox(limit, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimMailOutbox", reflect.TypeOf((*MockOutboxStore)(nil).ClaimMailOutbox), limit, lease)
+}
+
+// DeadLetterMailOutbox mocks base method.
+func (m *MockOutboxStore) DeadLetterMailOutbox(id int, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeadLetterMailOutbox", id, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeadLetterMailOutbox indicates an expected call of DeadLetterMailOutbox.
+func (mr *MockOutboxStoreMockRecorder) DeadLetterMailOutbox(id, lastError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeadLetterMailOutbox", reflect.TypeOf((*MockOutboxStore)(nil).DeadLetterMailOutbox), id, lastError)
+}
+
+// InsertMailOutbox mocks base method.
+func (m *MockOutboxStore) InsertMailOutbox(recipient, subject, ref string, payload []byte) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertMailOutbox", recipient, subject, ref, payload)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertMailOutbox indicates an expected call of InsertMailOutbox.
+func (mr *MockOutboxStoreMockRecorder) InsertMailOutbox(recipient, subject, ref, payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertMailOutbox", reflect.TypeOf((*MockOutboxStore)(nil).InsertMailOutbox), recipient, subject, ref, payload)
+}
+
+// MarkMailOutboxDelivered mocks base method.
+func (m *MockOutboxStore) MarkMailOutboxDelivered(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkMailOutboxDelivered", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkMailOutboxDelivered indicates an expected call of MarkMailOutboxDelivered.
+func (mr *MockOutboxStoreMockRecorder) MarkMailOutboxDelivered(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkMailOutboxDelivered", reflect.TypeOf((*MockOutboxStore)(nil).MarkMailOutboxDelivered), id)
+}
+
+// PurgeMailOutbox mocks base method.
+func (m *MockOutboxStore) PurgeMailOutbox(before time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeMailOutbox", before)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeMailOutbox indicates an expected call of PurgeMailOutbox.
+func (mr *MockOutboxStoreMockRecorder) PurgeMailOutbox(before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeMailOutbox", reflect.TypeOf((*MockOutboxStore)(nil).PurgeMailOutbox), before)
+}
+
+// RescheduleMailOutbox mocks base method.
+func (m *MockOutboxStore) RescheduleMailOutbox(id int, lastError string, nextAttemptAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RescheduleMailOutbox", id, lastError, nextAttemptAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RescheduleMailOutbox indicates an expected call of RescheduleMailOutbox.
+func (mr *MockOutboxStoreMockRecorder) RescheduleMailOutbox(id, lastError, nextAttemptAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RescheduleMailOutbox", reflect.TypeOf((*MockOutboxStore)(nil).RescheduleMailOutbox), id, lastError, nextAttemptAt)
+}
+
+// MockTemplateStore is a mock of TemplateStore interface.
+type MockTemplateStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTemplateStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockTemplateStoreMockRecorder is the mock recorder for MockTemplateStore.
+type MockTemplateStoreMockRecorder struct {
+	mock *MockTemplateStore
+}
+
+// NewMockTemplateStore creates a new mock instance.
+func NewMockTemplateStore(ctrl *gomock.Controller) *MockTemplateStore {
+	mock := &MockTemplateStore{ctrl: ctrl}
+	mock.recorder = &MockTemplateStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTemplateStore) EXPECT() *MockTemplateStoreMockRecorder {
+	return m.recorder
+}
+
+// CurrentEmailTemplate mocks base method.
+func (m *MockTemplateStore) CurrentEmailTemplate(name, locale string) (models.EmailTemplateVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CurrentEmailTemplate", name, locale)
+	ret0, _ := ret[0].(models.EmailTemplateVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CurrentEmailTemplate indicates an expected call of CurrentEmailTemplate.
+func (mr *MockTemplateStoreMockRecorder) CurrentEmailTemplate(name, locale any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentEmailTemplate", reflect.TypeOf((*MockTemplateStore)(nil).CurrentEmailTemplate), name, locale)
+}
+
+// EmailTemplateVersions mocks base method.
+func (m *MockTemplateStore) EmailTemplateVersions(name, locale string) ([]models.EmailTemplateVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmailTemplateVersions", name, locale)
+	ret0, _ := ret[0].([]models.EmailTemplateVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EmailTemplateVersions indicates an expected call of EmailTemplateVersions.
+func (mr *MockTemplateStoreMockRecorder) EmailTemplateVersions(name, locale any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmailTemplateVersions", reflect.TypeOf((*MockTemplateStore)(nil).EmailTemplateVersions), name, locale)
+}
+
+// GetEmailTemplateVersion mocks base method.
+func (m *MockTemplateStore) GetEmailTemplateVersion(name, locale string, version int) (models.EmailTemplateVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmailTemplateVersion", name, locale, version)
+	ret0, _ := ret[0].(models.EmailTemplateVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmailTemplateVersion indicates an expected call of GetEmailTemplateVersion.
+func (mr *MockTemplateStoreMockRecorder) GetEmailTemplateVersion(name, locale, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmailTemplateVersion", reflect.TypeOf((*MockTemplateStore)(nil).GetEmailTemplateVersion), name, locale, version)
+}
+
+// InsertEmailTemplateVersion mocks base method.
+func (m *MockTemplateStore) InsertEmailTemplateVersion(v models.EmailTemplateVersion) (models.EmailTemplateVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertEmailTemplateVersion", v)
+	ret0, _ := ret[0].(models.EmailTemplateVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertEmailTemplateVersion indicates an expected call of InsertEmailTemplateVersion.
+func (mr *MockTemplateStoreMockRecorder) InsertEmailTemplateVersion(v any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertEmailTemplateVersion", reflect.TypeOf((*MockTemplateStore)(nil).InsertEmailTemplateVersion), v)
+}
@@ -0,0 +1,169 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/outbox"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	outboxSendTimeout = time.Minute
+	outboxMaxAttempts = 10
+	// delivered and dead messages are deleted after outboxRetention, checked every outboxPurgeInterval
+	outboxRetention     = 7 * 24 * time.Hour
+	outboxPurgeInterval = time.Hour
+)
+
+// OutboxStore is the persistent queue behind an Outbox. *models.DBModel implements it.
+type OutboxStore interface {
+	InsertMailOutbox(recipient, subject, ref string, payload []byte) (int, error)
+	ClaimMailOutbox(limit int, lease time.Duration) ([]*models.MailOutboxEntry, error)
+	MarkMailOutboxDelivered(id int) error
+	RescheduleMailOutbox(id int, lastError string, nextAttemptAt time.Time) error
+	DeadLetterMailOutbox(id int, lastError string) error
+	PurgeMailOutbox(before time.Time) (int, error)
+}
+
+// Outbox is a Mailer that queues messages in the database. Run hands them to the transport,
+// retrying with backoff while it is down.
+type Outbox struct {
+	store       OutboxStore
+	transport   Mailer
+	onDelivered func(ref string) error
+	render      func(tmpl, locale string, data json.RawMessage) (string, string, error)
+}
+
+// NewOutbox returns an outbox queuing messages in store for transport
+func NewOutbox(store OutboxStore, transport Mailer) *Outbox {
+	return &Outbox{store: store, transport: transport}
+}
+
+// OnDelivered sets fn to be called with the Ref of each message the transport accepts, before
+// the message is marked delivered. Messages without a Ref are skipped.
+func (o *Outbox) OnDelivered(fn func(ref string) error) {
+	o.onDelivered = fn
+}
+
+// RenderWith sets fn to render the messages queued with a Template rather than bodies, into
+// their HTML and plain text bodies
+func (o *Outbox) RenderWith(fn func(tmpl, locale string, data json.RawMessage) (string, string, error)) {
+	o.render = fn
+}
+
+// Send queues msg. It only fails if msg can't be stored.
+func (o *Outbox) Send(ctx context.Context, msg Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	id, err := o.store.InsertMailOutbox(msg.To, msg.Subject, msg.Ref, payload)
+	if err != nil {
+		return err
+	}
+
+	log.Info().Int("mail_outbox_id", id).Str("subject", msg.Subject).Msg("mail queued")
+	return nil
+}
+
+// Run sends queued messages until ctx is done. It is safe to run in every replica.
+func (o *Outbox) Run(ctx context.Context) error {
+	o.dispatcher().Run(ctx)
+	return nil
+}
+
+// dispatcher returns the dispatcher handing the queued messages to the transport, and purging
+// old ones every outboxPurgeInterval
+func (o *Outbox) dispatcher() outbox.Dispatcher[*models.MailOutboxEntry] {
+	var lastPurge time.Time
+
+	return outbox.Dispatcher[*models.MailOutboxEntry]{
+		Name:        "mail dispatcher",
+		Claim:       o.store.ClaimMailOutbox,
+		Deliver:     o.deliver,
+		SendTimeout: outboxSendTimeout,
+		Housekeeping: func() {
+			if time.Since(lastPurge) >= outboxPurgeInterval {
+				o.purge()
+				lastPurge = time.Now()
+			}
+		},
+	}
+}
+
+// purge deletes the messages that were delivered or given up on more than outboxRetention ago
+func (o *Outbox) purge() {
+	n, err := o.store.PurgeMailOutbox(time.Now().Add(-outboxRetention))
+	if err != nil {
+		log.Error().Err(err).Msg("purge")
+		return
+	}
+	if n > 0 {
+		log.Info().Int("deleted", n).Msg("old mail purged")
+	}
+}
+
+// deliver sends one queued message, then records the outcome; ctx ends after outboxSendTimeout
+func (o *Outbox) deliver(ctx context.Context, e *models.MailOutboxEntry) {
+	var msg Message
+	if err := json.Unmarshal(e.Payload, &msg); err != nil {
+		log.Error().Err(err).Int("mail_outbox_id", e.ID).Msg("queued mail is unreadable, giving up")
+		if err := o.store.DeadLetterMailOutbox(e.ID, err.Error()); err != nil {
+			log.Error().Err(err).Int("mail_outbox_id", e.ID).Msg("deliver")
+		}
+		return
+	}
+
+	err := o.renderBodies(&msg)
+	if err == nil {
+		err = o.transport.Send(ctx, msg)
+	}
+	if err == nil {
+		log.Info().Int("mail_outbox_id", e.ID).Str("subject", msg.Subject).Msg("mail sent")
+		if e.Ref != "" && o.onDelivered != nil {
+			// the mail is gone either way, so a failing hook doesn't get it sent again
+			if err := o.onDelivered(e.Ref); err != nil {
+				log.Error().Err(err).Int("mail_outbox_id", e.ID).Str("ref", e.Ref).Msg("deliver")
+			}
+		}
+		if err := o.store.MarkMailOutboxDelivered(e.ID); err != nil {
+			log.Error().Err(err).Int("mail_outbox_id", e.ID).Msg("deliver")
+		}
+		return
+	}
+
+	if e.Attempts >= outboxMaxAttempts {
+		log.Error().Err(err).Int("mail_outbox_id", e.ID).Int("attempts", e.Attempts).Msg("mail delivery failed, giving up")
+		err = o.store.DeadLetterMailOutbox(e.ID, err.Error())
+	} else {
+		log.Warn().Err(err).Int("mail_outbox_id", e.ID).Int("attempts", e.Attempts).Msg("mail delivery failed, will retry")
+		err = o.store.RescheduleMailOutbox(e.ID, err.Error(), time.Now().Add(outbox.Backoff(e.Attempts)))
+	}
+	if err != nil {
+		log.Error().Err(err).Int("mail_outbox_id", e.ID).Msg("deliver")
+	}
+}
+
+// renderBodies renders the bodies of msg if it was queued with a template. A template that
+// fails to render is retried like a failed send, as it can be fixed by editing it.
+func (o *Outbox) renderBodies(msg *Message) error {
+	if msg.Template == "" {
+		return nil
+	}
+	if o.render == nil {
+		return fmt.Errorf("no renderer for template %s", msg.Template)
+	}
+
+	html, plain, err := o.render(msg.Template, msg.Locale, msg.Data)
+	if err != nil {
+		return err
+	}
+	msg.HTML, msg.Plain = html, plain
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package mailer
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	mail "github.com/xhit/go-simple-mail/v2"
+)
+
+// SMTPConfig is where and how to connect to an SMTP relay
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	PoolSize int
+}
+
+// SMTP sends email through an SMTP relay. Connections are kept open and reused between
+// messages, up to PoolSize idle ones.
+type SMTP struct {
+	server *mail.SMTPServer
+	pool   chan *mail.SMTPClient
+}
+
+// NewSMTP returns an SMTP transport. It connects lazily, on the first message.
+func NewSMTP(config SMTPConfig) *SMTP {
+	server := mail.NewSMTPClient()
+	server.Host = config.Host
+	server.Port = config.Port
+	server.Username = config.Username
+	server.Password = config.Password
+	server.Encryption = mail.EncryptionTLS
+	server.KeepAlive = true
+	server.ConnectTimeout = 30 * time.Second
+	server.SendTimeout = 30 * time.Second
+
+	return &SMTP{
+		server: server,
+		pool:   make(chan *mail.SMTPClient, max(config.PoolSize, 1)),
+	}
+}
+
+// Send sends msg on an idle connection, or a new one if there is none that is still alive
+func (s *SMTP) Send(ctx context.Context, msg Message) error {
+	email, err := msg.email()
+	if err != nil {
+		return err
+	}
+
+	client, err := s.conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := email.Send(client); err != nil {
+		// the connection may be left mid-transaction, so don't reuse it
+		_ = client.Close()
+		return err
+	}
+
+	s.release(client)
+	return nil
+}
+
+// conn takes an idle connection from the pool, checking it is still open, or dials a new one
+func (s *SMTP) conn(ctx context.Context) (*mail.SMTPClient, error) {
+	for {
+		select {
+		case client := <-s.pool:
+			if err := client.Noop(); err == nil {
+				return client, nil
+			}
+			_ = client.Close()
+		default:
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return s.server.Connect()
+		}
+	}
+}
+
+// release returns a connection to the pool, or closes it if the pool is full
+func (s *SMTP) release(client *mail.SMTPClient) {
+	select {
+	case s.pool <- client:
+	default:
+		if err := client.Close(); err != nil {
+			log.Warn().Err(err).Msg("release")
+		}
+	}
+}
+
+// Close closes the idle connections
+func (s *SMTP) Close() error {
+	for {
+		select {
+		case client := <-s.pool:
+			_ = client.Close()
+		default:
+			return nil
+		}
+	}
+}
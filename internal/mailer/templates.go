@@ -0,0 +1,301 @@
+package mailer
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/i18n"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// ErrTemplateNotFound is returned for an email, or a version of one, that doesn't exist
+	ErrTemplateNotFound = errors.New("email template not found")
+	// ErrInvalidTemplate is returned when an edit is refused, wrapping the reason
+	ErrInvalidTemplate = errors.New("invalid email template")
+)
+
+// Templates renders the email templates in a directory. An email is a pair of templates,
+// name.html.tmpl and name.plain.tmpl, each defining "body", with optional localized variants
+// such as name.de.html.tmpl. The compiled templates are only defaults: an edit saved in the
+// store overrides them, until another edit restores them.
+type Templates struct {
+	fsys  fs.FS
+	dir   string
+	store TemplateStore
+}
+
+// TemplateStore keeps the edits of email templates. *models.DBModel implements it.
+type TemplateStore interface {
+	CurrentEmailTemplate(name, locale string) (models.EmailTemplateVersion, error)
+	GetEmailTemplateVersion(name, locale string, version int) (models.EmailTemplateVersion, error)
+	EmailTemplateVersions(name, locale string) ([]models.EmailTemplateVersion, error)
+	InsertEmailTemplateVersion(v models.EmailTemplateVersion) (models.EmailTemplateVersion, error)
+}
+
+// TemplateInfo describes one email of a Templates
+type TemplateInfo struct {
+	Name string `json:"name"`
+	// Locales have their own variant; the others use the default template
+	Locales []string `json:"locales"`
+}
+
+// Source is the text of an email template. Locale is where it was found: the locale asked for,
+// or empty for the default template. Version is 0 for the compiled template.
+type Source struct {
+	Name    string `json:"name"`
+	Locale  string `json:"locale"`
+	Version int    `json:"version"`
+	HTML    string `json:"html"`
+	Plain   string `json:"plain"`
+}
+
+// NewTemplates returns the email templates in dir of fsys, with the edits kept in store. A nil
+// store leaves the compiled templates in use, and can't be edited.
+func NewTemplates(fsys fs.FS, dir string, store TemplateStore) *Templates {
+	return &Templates{fsys: fsys, dir: dir, store: store}
+}
+
+// List returns the emails there are templates for, sorted by name
+func (t *Templates) List() ([]TemplateInfo, error) {
+	files, err := fs.Glob(t.fsys, path.Join(t.dir, "*.html.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+
+	locales := map[string][]string{}
+	for _, f := range files {
+		name := strings.TrimSuffix(path.Base(f), ".html.tmpl")
+		// a localized variant is name.<locale>, and template names have no dots
+		name, locale, _ := strings.Cut(name, ".")
+		if _, ok := locales[name]; !ok {
+			locales[name] = []string{}
+		}
+		if locale != "" {
+			locales[name] = append(locales[name], locale)
+		}
+	}
+
+	out := make([]TemplateInfo, 0, len(locales))
+	for name, l := range locales {
+		sort.Strings(l)
+		out = append(out, TemplateInfo{Name: name, Locales: l})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+
+	return out, nil
+}
+
+// Exists reports whether there is a template for name
+func (t *Templates) Exists(name string) bool {
+	_, err := fs.Stat(t.fsys, path.Join(t.dir, name+".html.tmpl"))
+	return err == nil
+}
+
+// Render renders the HTML and plain text bodies of email name in locale
+func (t *Templates) Render(name, locale string, data interface{}) (string, string, error) {
+	locale = i18n.Match(locale)
+
+	src, err := t.resolve(name, locale, t.store != nil)
+	if err != nil && t.store != nil {
+		// mail shouldn't stop going out because the edits can't be read
+		log.Warn().Err(err).Str("template", name).Msg("rendering the compiled email template")
+		src, err = t.resolve(name, locale, false)
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	html, err := render(src.HTML, locale, data)
+	if err != nil {
+		return "", "", fmt.Errorf("%s.html: %w", name, err)
+	}
+
+	plain, err := render(src.Plain, locale, data)
+	if err != nil {
+		return "", "", fmt.Errorf("%s.plain: %w", name, err)
+	}
+
+	return html, plain, nil
+}
+
+// Source returns the text of email name that is in use for locale, or for the default template
+// when locale is empty
+func (t *Templates) Source(name, locale string) (Source, error) {
+	return t.resolve(name, locale, t.store != nil)
+}
+
+// Versions returns the saved edits of email name in locale, newest first
+func (t *Templates) Versions(name, locale string) ([]models.EmailTemplateVersion, error) {
+	if err := t.editable(name, locale); err != nil {
+		return nil, err
+	}
+
+	return t.store.EmailTemplateVersions(name, locale)
+}
+
+// Save checks an edit of email name in locale, by parsing it and rendering it with sample,
+// then saves it as a new version, which puts it in use. A nil sample skips rendering.
+func (t *Templates) Save(name, locale, html, plain, author string, sample interface{}) (models.EmailTemplateVersion, error) {
+	if err := t.editable(name, locale); err != nil {
+		return models.EmailTemplateVersion{}, err
+	}
+	if err := Check(html, plain, locale, sample); err != nil {
+		return models.EmailTemplateVersion{}, err
+	}
+
+	return t.store.InsertEmailTemplateVersion(models.EmailTemplateVersion{
+		Name:      name,
+		Locale:    locale,
+		HTML:      html,
+		Plain:     plain,
+		CreatedBy: author,
+	})
+}
+
+// Restore puts an earlier version of email name in locale back in use, by saving a copy of it
+// as a new version. Version 0 restores the compiled template.
+func (t *Templates) Restore(name, locale string, version int, author string) (models.EmailTemplateVersion, error) {
+	if err := t.editable(name, locale); err != nil {
+		return models.EmailTemplateVersion{}, err
+	}
+
+	restored := models.EmailTemplateVersion{UsesDefault: true}
+	if version != 0 {
+		var err error
+		restored, err = t.store.GetEmailTemplateVersion(name, locale, version)
+		if errors.Is(err, sql.ErrNoRows) {
+			return restored, fmt.Errorf("%w: %s has no version %d", ErrTemplateNotFound, name, version)
+		} else if err != nil {
+			return restored, err
+		}
+	}
+
+	restored.Name = name
+	restored.Locale = locale
+	restored.CreatedBy = author
+	return t.store.InsertEmailTemplateVersion(restored)
+}
+
+// Check parses the HTML and plain text templates of an email, which must both define "body",
+// and renders them with sample unless it is nil
+func Check(html, plain, locale string, sample interface{}) error {
+	for _, kind := range []string{"html", "plain"} {
+		text := html
+		if kind == "plain" {
+			text = plain
+		}
+
+		tmpl, err := parse(text, locale)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidTemplate, kind, err)
+		}
+		if tmpl.Lookup("body") == nil {
+			return fmt.Errorf("%w: %s: must define \"body\"", ErrInvalidTemplate, kind)
+		}
+		if sample == nil {
+			continue
+		}
+		if err := tmpl.ExecuteTemplate(io.Discard, "body", sample); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidTemplate, kind, err)
+		}
+	}
+
+	return nil
+}
+
+// editable returns an error unless email name in locale can be edited
+func (t *Templates) editable(name, locale string) error {
+	if t.store == nil {
+		return errors.New("email templates can't be edited without a database")
+	}
+	if !t.Exists(name) {
+		return fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	if locale != "" && !slices.Contains(i18n.Supported(), locale) {
+		return fmt.Errorf("%w: unsupported locale %q", ErrInvalidTemplate, locale)
+	}
+	return nil
+}
+
+// resolve finds the text of email name for locale, looking in order for an edit of the locale's
+// variant, the compiled variant, an edit of the default template and the compiled default. The
+// store is only looked in with useStore.
+func (t *Templates) resolve(name, locale string, useStore bool) (Source, error) {
+	locales := []string{""}
+	if locale != "" {
+		locales = []string{locale, ""}
+	}
+
+	for _, l := range locales {
+		if useStore {
+			v, err := t.store.CurrentEmailTemplate(name, l)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return Source{}, err
+			}
+			if err == nil && !v.UsesDefault {
+				return Source{Name: name, Locale: l, Version: v.Version, HTML: v.HTML, Plain: v.Plain}, nil
+			}
+		}
+
+		if src, ok := t.compiled(name, l); ok {
+			return src, nil
+		}
+	}
+
+	return Source{}, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+}
+
+// compiled returns the compiled variant of email name for locale, or the default template when
+// locale is empty. A variant without its own plain text template uses the default one.
+func (t *Templates) compiled(name, locale string) (Source, bool) {
+	file := name + ".html.tmpl"
+	if locale != "" {
+		file = name + "." + locale + ".html.tmpl"
+	}
+
+	html, err := fs.ReadFile(t.fsys, path.Join(t.dir, file))
+	if err != nil {
+		return Source{}, false
+	}
+
+	plainFile := path.Join(t.dir, name+".plain.tmpl")
+	if locale != "" {
+		plainFile = i18n.TemplateFile(t.fsys, t.dir, name, "plain", locale)
+	}
+
+	plain, err := fs.ReadFile(t.fsys, plainFile)
+	if err != nil {
+		return Source{}, false
+	}
+
+	return Source{Name: name, Locale: locale, HTML: string(html), Plain: string(plain)}, true
+}
+
+func parse(text, locale string) (*template.Template, error) {
+	return template.New("email").Funcs(i18n.For(locale).Funcs()).Parse(text)
+}
+
+func render(text, locale string, data interface{}) (string, error) {
+	tmpl, err := parse(text, locale)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "body", data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
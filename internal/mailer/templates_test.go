@@ -0,0 +1,159 @@
+package mailer
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTemplates(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/welcome.html.tmpl":    {Data: []byte(`{{define "body"}}<p>{{t "email.greeting_name" .}}</p>{{end}}`)},
+		"templates/welcome.plain.tmpl":   {Data: []byte(`{{define "body"}}{{t "email.greeting_name" .}}{{end}}`)},
+		"templates/welcome.de.html.tmpl": {Data: []byte(`{{define "body"}}<p>Willkommen, {{.}}</p>{{end}}`)},
+		"templates/receipt.html.tmpl":    {Data: []byte(`{{define "body"}}<p>{{money . "usd"}}</p>{{end}}`)},
+		"templates/receipt.plain.tmpl":   {Data: []byte(`{{define "body"}}{{money . "usd"}}{{end}}`)},
+		"templates/unrelated.json":       {Data: []byte(`{}`)},
+	}
+	tmpl := NewTemplates(fsys, "templates", nil)
+
+	list, err := tmpl.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TemplateInfo{{Name: "receipt", Locales: []string{}}, {Name: "welcome", Locales: []string{"de"}}}
+	if !reflect.DeepEqual(list, want) {
+		t.Errorf("List() = %+v, want %+v", list, want)
+	}
+
+	if !tmpl.Exists("receipt") || tmpl.Exists("unrelated") {
+		t.Error("expected only emails with an HTML template to exist")
+	}
+
+	html, plain, err := tmpl.Render("welcome", "de", "Jane")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if html != "<p>Willkommen, Jane</p>" || plain != "Hallo Jane," {
+		t.Errorf("got %q and %q", html, plain)
+	}
+
+	html, _, err = tmpl.Render("receipt", "en", 2000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if html != "<p>$20.00</p>" {
+		t.Errorf("got %q", html)
+	}
+
+	if _, _, err := tmpl.Render("missing", "en", nil); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected an error naming the missing template, got %v", err)
+	}
+}
+
+func TestTemplatesEdited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fsys := fstest.MapFS{
+		"templates/welcome.html.tmpl":  {Data: []byte(`{{define "body"}}<p>Hello {{.}}</p>{{end}}`)},
+		"templates/welcome.plain.tmpl": {Data: []byte(`{{define "body"}}Hello {{.}}{{end}}`)},
+	}
+	mockStore := NewMockTemplateStore(ctrl)
+	tmpl := NewTemplates(fsys, "templates", mockStore)
+
+	edited := models.EmailTemplateVersion{Name: "welcome", Version: 3, HTML: `{{define "body"}}<p>Hi {{.}}!</p>{{end}}`, Plain: `{{define "body"}}Hi {{.}}!{{end}}`}
+
+	// German has no edit and no variant of its own, so the edited default is used
+	mockStore.EXPECT().CurrentEmailTemplate("welcome", "de").Return(models.EmailTemplateVersion{}, sql.ErrNoRows)
+	mockStore.EXPECT().CurrentEmailTemplate("welcome", "").Return(edited, nil)
+
+	html, plain, err := tmpl.Render("welcome", "de", "Jane")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if html != "<p>Hi Jane!</p>" || plain != "Hi Jane!" {
+		t.Errorf("expected the edit to be used, got %q and %q", html, plain)
+	}
+
+	// a restored default, or a store that can't be read, leaves the compiled template in use
+	mockStore.EXPECT().CurrentEmailTemplate("welcome", "en").Return(models.EmailTemplateVersion{UsesDefault: true, Version: 4}, nil)
+	mockStore.EXPECT().CurrentEmailTemplate("welcome", "").Return(models.EmailTemplateVersion{UsesDefault: true, Version: 4}, nil)
+	mockStore.EXPECT().CurrentEmailTemplate("welcome", "en").Return(models.EmailTemplateVersion{}, errors.New("connection refused"))
+
+	for i := 0; i < 2; i++ {
+		html, _, err = tmpl.Render("welcome", "en", "Jane")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if html != "<p>Hello Jane</p>" {
+			t.Errorf("expected the compiled template, got %q", html)
+		}
+	}
+}
+
+func TestTemplatesSave(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fsys := fstest.MapFS{
+		"templates/welcome.html.tmpl":  {Data: []byte(`{{define "body"}}<p>Hello {{.Name}}</p>{{end}}`)},
+		"templates/welcome.plain.tmpl": {Data: []byte(`{{define "body"}}Hello {{.Name}}{{end}}`)},
+	}
+	mockStore := NewMockTemplateStore(ctrl)
+	tmpl := NewTemplates(fsys, "templates", mockStore)
+	sample := struct{ Name string }{"Jane"}
+
+	refused := []struct {
+		name, locale, html, plain string
+		err                       error
+	}{
+		{"welcome", "", `{{define "body"}}<p>{{.Name}</p>{{end}}`, `{{define "body"}}{{end}}`, ErrInvalidTemplate},
+		{"welcome", "", `<p>{{.Name}}</p>`, `{{define "body"}}{{end}}`, ErrInvalidTemplate},
+		{"welcome", "", `{{define "body"}}{{.Nmae}}{{end}}`, `{{define "body"}}{{end}}`, ErrInvalidTemplate},
+		{"welcome", "ja", `{{define "body"}}{{end}}`, `{{define "body"}}{{end}}`, ErrInvalidTemplate},
+		{"goodbye", "", `{{define "body"}}{{end}}`, `{{define "body"}}{{end}}`, ErrTemplateNotFound},
+	}
+	for _, tt := range refused {
+		if _, err := tmpl.Save(tt.name, tt.locale, tt.html, tt.plain, "admin@example.com", sample); !errors.Is(err, tt.err) {
+			t.Errorf("Save(%q, %q, %q) = %v, want %v", tt.name, tt.locale, tt.html, err, tt.err)
+		}
+	}
+
+	mockStore.EXPECT().InsertEmailTemplateVersion(models.EmailTemplateVersion{
+		Name:      "welcome",
+		Locale:    "de",
+		HTML:      `{{define "body"}}<p>Hallo {{.Name}}</p>{{end}}`,
+		Plain:     `{{define "body"}}Hallo {{.Name}}{{end}}`,
+		CreatedBy: "admin@example.com",
+	}).Return(models.EmailTemplateVersion{ID: 7, Version: 1}, nil)
+
+	v, err := tmpl.Save("welcome", "de", `{{define "body"}}<p>Hallo {{.Name}}</p>{{end}}`, `{{define "body"}}Hallo {{.Name}}{{end}}`, "admin@example.com", sample)
+	if err != nil || v.Version != 1 {
+		t.Fatalf("got %+v, %v", v, err)
+	}
+
+	// restoring copies the old version, or marks the compiled template as in use again
+	mockStore.EXPECT().GetEmailTemplateVersion("welcome", "de", 1).Return(models.EmailTemplateVersion{ID: 7, Version: 1, HTML: "a", Plain: "b", CreatedBy: "someone@example.com"}, nil)
+	mockStore.EXPECT().InsertEmailTemplateVersion(models.EmailTemplateVersion{ID: 7, Name: "welcome", Locale: "de", Version: 1, HTML: "a", Plain: "b", CreatedBy: "admin@example.com"}).
+		Return(models.EmailTemplateVersion{Version: 3}, nil)
+	mockStore.EXPECT().InsertEmailTemplateVersion(models.EmailTemplateVersion{Name: "welcome", Locale: "de", UsesDefault: true, CreatedBy: "admin@example.com"}).
+		Return(models.EmailTemplateVersion{Version: 4}, nil)
+	mockStore.EXPECT().GetEmailTemplateVersion("welcome", "de", 9).Return(models.EmailTemplateVersion{}, sql.ErrNoRows)
+
+	if _, err := tmpl.Restore("welcome", "de", 1, "admin@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tmpl.Restore("welcome", "de", 0, "admin@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tmpl.Restore("welcome", "de", 9, "admin@example.com"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("expected a missing version to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,235 @@
+package models
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base32"
+	"slices"
+	"strings"
+	"time"
+)
+
+// APIKeyPrefix marks a bearer token as an API key rather than a user token
+const APIKeyPrefix = "yk_"
+
+// Scopes that can be granted to API keys
+const (
+	ScopeSalesRead          = "sales:read"
+	ScopeRefundsWrite       = "refunds:write"
+	ScopeSubscriptionsWrite = "subscriptions:write"
+	ScopeTransactionsWrite  = "transactions:write"
+	ScopeUsersRead          = "users:read"
+	ScopeUsersWrite         = "users:write"
+	ScopeAuditRead          = "audit:read"
+	ScopeInvoicesRead       = "invoices:read"
+	ScopeInvoicesWrite      = "invoices:write"
+	ScopeItemsRead          = "items:read"
+	ScopeItemsWrite         = "items:write"
+)
+
+// APIKeyScopes lists every scope an API key may carry
+var APIKeyScopes = []string{
+	ScopeSalesRead,
+	ScopeRefundsWrite,
+	ScopeSubscriptionsWrite,
+	ScopeTransactionsWrite,
+	ScopeUsersRead,
+	ScopeUsersWrite,
+	ScopeAuditRead,
+	ScopeInvoicesRead,
+	ScopeInvoicesWrite,
+	ScopeItemsRead,
+	ScopeItemsWrite,
+}
+
+// APIKey is the type for long-lived service API keys
+type APIKey struct {
+	ID              int        `json:"id"`
+	Name            string     `json:"name"`
+	PlainText       string     `json:"key,omitempty"`
+	Hash            []byte     `json:"-"`
+	Scopes          []string   `json:"scopes"`
+	CreatedByUserID int        `json:"created_by_user_id"`
+	Expiry          *time.Time `json:"expiry"`
+	LastUsedAt      *time.Time `json:"last_used_at"`
+	RevokedAt       *time.Time `json:"revoked_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// HasScope reports whether the key was granted scope
+func (k *APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// GenerateAPIKey generates a new API key with the given scopes. A ttl of zero means the key never expires.
+func GenerateAPIKey(name string, scopes []string, ttl time.Duration) (*APIKey, error) {
+	key := &APIKey{
+		Name:   name,
+		Scopes: scopes,
+	}
+
+	if ttl > 0 {
+		expiry := time.Now().Add(ttl)
+		key.Expiry = &expiry
+	}
+
+	randomBytes := make([]byte, 32)
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	key.PlainText = APIKeyPrefix + base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(randomBytes)
+	hash := sha256.Sum256([]byte(key.PlainText))
+	key.Hash = hash[:]
+	return key, nil
+}
+
+// InsertAPIKey stores an API key created by userID, and returns its id
+func (m *DBModel) InsertAPIKey(k *APIKey, userID int) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `
+		insert into api_keys
+			(name, key_hash, scopes, created_by_user_id, expiry, created_at, updated_at)
+		values ($1, $2, $3, $4, $5, $6, $7)
+		returning id
+	`
+
+	var id int
+	err := m.DB.QueryRowContext(ctx, stmt,
+		k.Name,
+		k.Hash,
+		strings.Join(k.Scopes, " "),
+		userID,
+		k.Expiry,
+		time.Now(),
+		time.Now(),
+	).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetAPIKeyForToken returns the active API key matching a plain text key, and records its use
+func (m *DBModel) GetAPIKeyForToken(token string) (*APIKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tokenHash := sha256.Sum256([]byte(token))
+
+	stmt := `
+		update api_keys set
+			last_used_at = $1
+		where
+			key_hash = $2
+			and revoked_at is null
+			and (expiry is null or expiry > $1)
+		returning
+			id, name, scopes, coalesce(created_by_user_id, 0), expiry,
+			last_used_at, revoked_at, created_at
+	`
+
+	row := m.DB.QueryRowContext(ctx, stmt, time.Now(), tokenHash[:])
+
+	return scanAPIKey(row)
+}
+
+// GetAllAPIKeys returns every API key, newest first. Plain text keys are never stored, so are not returned.
+func (m *DBModel) GetAllAPIKeys() ([]*APIKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var keys []*APIKey
+
+	query := `
+		select
+			id, name, scopes, coalesce(created_by_user_id, 0), expiry,
+			last_used_at, revoked_at, created_at
+		from
+			api_keys
+		order by
+			created_at desc
+	`
+
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	return keys, rows.Err()
+}
+
+// RevokeAPIKey revokes an API key by id
+func (m *DBModel) RevokeAPIKey(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `update api_keys set revoked_at = $1, updated_at = $1 where id = $2 and revoked_at is null`
+
+	res, err := m.DB.ExecContext(ctx, stmt, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIKey(row rowScanner) (*APIKey, error) {
+	var k APIKey
+	var scopes string
+	var expiry, lastUsedAt, revokedAt sql.NullTime
+
+	err := row.Scan(
+		&k.ID,
+		&k.Name,
+		&scopes,
+		&k.CreatedByUserID,
+		&expiry,
+		&lastUsedAt,
+		&revokedAt,
+		&k.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	k.Scopes = strings.Fields(scopes)
+	if expiry.Valid {
+		k.Expiry = &expiry.Time
+	}
+	if lastUsedAt.Valid {
+		k.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		k.RevokedAt = &revokedAt.Time
+	}
+
+	return &k, nil
+}
@@ -0,0 +1,195 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AuditEvent is the type for the admin audit log. The change was made by the user
+// ActorUserID, or with the API key ActorAPIKeyID; zero means none.
+type AuditEvent struct {
+	ID            int             `json:"id"`
+	ActorUserID   int             `json:"actor_user_id"`
+	ActorAPIKeyID int             `json:"actor_api_key_id"`
+	Action        string          `json:"action"`
+	TargetType    string          `json:"target_type"`
+	TargetID      string          `json:"target_id"`
+	Before        json.RawMessage `json:"before"`
+	After         json.RawMessage `json:"after"`
+	IP            string          `json:"ip"`
+	RequestID     string          `json:"request_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// AuditFilter narrows down a listing of audit events; zero values match everything
+type AuditFilter struct {
+	ActorUserID   int
+	ActorAPIKeyID int
+	Action        string
+	TargetType    string
+	TargetID      string
+	From          time.Time
+	To            time.Time
+}
+
+// where builds the where clause and arguments for the filter
+func (f AuditFilter) where() (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if f.ActorUserID > 0 {
+		add("actor_user_id = $%d", f.ActorUserID)
+	}
+	if f.ActorAPIKeyID > 0 {
+		add("actor_api_key_id = $%d", f.ActorAPIKeyID)
+	}
+	if f.Action != "" {
+		add("action = $%d", f.Action)
+	}
+	if f.TargetType != "" {
+		add("target_type = $%d", f.TargetType)
+	}
+	if f.TargetID != "" {
+		add("target_id = $%d", f.TargetID)
+	}
+	if !f.From.IsZero() {
+		add("created_at >= $%d", f.From)
+	}
+	if !f.To.IsZero() {
+		add("created_at < $%d", f.To)
+	}
+
+	if len(conds) == 0 {
+		return "", args
+	}
+
+	return "where " + strings.Join(conds, " and "), args
+}
+
+// InsertAuditEvent appends an event to the audit log
+func (m *DBModel) InsertAuditEvent(e AuditEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `
+		insert into audit_events
+			(actor_user_id, actor_api_key_id, action, target_type, target_id, before, after,
+			ip, request_id, created_at)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	var actorID, apiKeyID interface{}
+	if e.ActorUserID > 0 {
+		actorID = e.ActorUserID
+	}
+	if e.ActorAPIKeyID > 0 {
+		apiKeyID = e.ActorAPIKeyID
+	}
+
+	_, err := m.DB.ExecContext(ctx, stmt,
+		actorID,
+		apiKeyID,
+		e.Action,
+		e.TargetType,
+		e.TargetID,
+		nullJSON(e.Before),
+		nullJSON(e.After),
+		e.IP,
+		e.RequestID,
+		time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAuditEventsPaginated returns a slice of a subset of audit events matching filter, newest first
+func (m *DBModel) GetAuditEventsPaginated(filter AuditFilter, pageSize, page int) ([]*AuditEvent, int, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	offset := (page - 1) * pageSize
+
+	var events []*AuditEvent
+
+	where, args := filter.where()
+
+	query := fmt.Sprintf(`
+		select
+			id, coalesce(actor_user_id, 0), coalesce(actor_api_key_id, 0), action, target_type, target_id,
+			before, after, ip, request_id, created_at
+		from
+			audit_events
+		%s
+		order by
+			created_at desc, id desc
+		limit $%d offset $%d
+	`, where, len(args)+1, len(args)+2)
+
+	rows, err := m.DB.QueryContext(ctx, query, append(args, pageSize, offset)...)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e AuditEvent
+		var before, after []byte
+		err = rows.Scan(
+			&e.ID,
+			&e.ActorUserID,
+			&e.ActorAPIKeyID,
+			&e.Action,
+			&e.TargetType,
+			&e.TargetID,
+			&before,
+			&after,
+			&e.IP,
+			&e.RequestID,
+			&e.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		e.Before = before
+		e.After = after
+		events = append(events, &e)
+	}
+
+	query = fmt.Sprintf(`
+		select
+			count(id)
+		from
+			audit_events
+		%s
+	`, where)
+
+	var totalRecords int
+	countRow := m.DB.QueryRowContext(ctx, query, args...)
+	err = countRow.Scan(&totalRecords)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	lastPage := LastPage(totalRecords, pageSize)
+
+	return events, lastPage, totalRecords, nil
+}
+
+// nullJSON maps an empty document to SQL null
+func nullJSON(doc json.RawMessage) interface{} {
+	if len(doc) == 0 {
+		return nil
+	}
+	return string(doc)
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/throttle"
+)
+
+// GetAuthLockout returns the time until which any of keys is locked out for action.
+// A zero time means none of the keys is currently locked.
+func (m *DBModel) GetAuthLockout(action string, keys []throttle.Key) (time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var lockedUntil time.Time
+
+	query := `
+		select
+			locked_until
+		from
+			auth_attempts
+		where
+			action = $1
+			and key = $2
+			and locked_until > $3
+	`
+
+	for _, k := range keys {
+		var until time.Time
+		err := m.DB.QueryRowContext(ctx, query, action, k.Name, time.Now()).Scan(&until)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		} else if err != nil {
+			return lockedUntil, err
+		}
+		if until.After(lockedUntil) {
+			lockedUntil = until
+		}
+	}
+
+	return lockedUntil, nil
+}
+
+// RecordAuthFailure increments the failure counter of each key for action, and applies
+// the key's backoff policy. Lockouts are written to auth_lockouts for auditing.
+func (m *DBModel) RecordAuthFailure(action, ip string, keys []throttle.Key) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now()
+
+	for _, k := range keys {
+		stmt := `
+			insert into auth_attempts (action, key, failures, last_failure_at, locked_until)
+			values ($1, $2, 1, $3, $3)
+			on conflict (action, key) do update set
+				failures = case
+					when auth_attempts.last_failure_at < $4 then 1
+					else auth_attempts.failures + 1
+				end,
+				last_failure_at = $3
+			returning failures
+		`
+
+		var failures int
+		err = tx.QueryRowContext(ctx, stmt, action, k.Name, now, now.Add(-k.Policy.Window)).Scan(&failures)
+		if err != nil {
+			return err
+		}
+
+		delay := k.Policy.Delay(failures)
+		if delay == 0 {
+			continue
+		}
+
+		lockedUntil := now.Add(delay)
+
+		stmt = `update auth_attempts set locked_until = $1 where action = $2 and key = $3`
+		_, err = tx.ExecContext(ctx, stmt, lockedUntil, action, k.Name)
+		if err != nil {
+			return err
+		}
+
+		if k.Policy.IsLockout(delay) {
+			stmt = `
+				insert into auth_lockouts (action, key, ip, failures, locked_until, created_at)
+				values ($1, $2, $3, $4, $5, $6)
+			`
+			_, err = tx.ExecContext(ctx, stmt, action, k.Name, ip, failures, lockedUntil, now)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ClearAuthFailures resets the failure counter of key for action, after a successful attempt
+func (m *DBModel) ClearAuthFailures(action string, key throttle.Key) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `delete from auth_attempts where action = $1 and key = $2`
+
+	_, err := m.DB.ExecContext(ctx, stmt, action, key.Name)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
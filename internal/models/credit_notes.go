@@ -0,0 +1,236 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Credit note statuses
+const (
+	CreditNoteStatusIssued = "issued"
+	CreditNoteStatusSent   = "sent"
+)
+
+// ErrCreditExceedsInvoice is returned when a credit note would credit more than is left
+// on its invoice
+var ErrCreditExceedsInvoice = errors.New("the credit exceeds the amount left on the invoice")
+
+// CreditNote is the type for credit notes, which credit part or all of an invoice back to
+// the customer after a refund. Amount is in the smallest unit of Currency.
+type CreditNote struct {
+	ID            int        `json:"id"`
+	Number        string     `json:"number"`
+	InvoiceID     int        `json:"invoice_id"`
+	InvoiceNumber string     `json:"invoice_number"`
+	Reference     string     `json:"reference"`
+	Amount        int        `json:"amount"`
+	Currency      string     `json:"currency"`
+	Reason        string     `json:"reason"`
+	Status        string     `json:"status"`
+	PDFLocation   string     `json:"pdf_location"`
+	IssuedAt      time.Time  `json:"issued_at"`
+	SentAt        *time.Time `json:"sent_at"`
+	CreatedAt     time.Time  `json:"-"`
+	UpdatedAt     time.Time  `json:"-"`
+}
+
+// CreditNoteNumber formats the nth credit note of year, e.g. CN-2026-000123
+func CreditNoteNumber(year, n int) string {
+	return fmt.Sprintf("CN-%d-%06d", year, n)
+}
+
+// CreateCreditNote issues a credit note against cn.InvoiceID, taking the next number in the
+// sequence for the year of cn.IssuedAt. If a credit note with the same reference exists, it is
+// returned instead. It returns ErrCreditExceedsInvoice if the invoice's credit notes would add
+// up to more than its total.
+func (m *DBModel) CreateCreditNote(cn CreditNote) (CreditNote, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return cn, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	existing, err := scanCreditNote(tx.QueryRowContext(ctx, `
+		select `+creditNoteColumns+`
+		from
+			credit_notes c
+			join invoices i on (i.id = c.invoice_id)
+		where c.reference = $1`, cn.Reference))
+	if err == nil {
+		return existing, nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return cn, err
+	}
+
+	// locking the invoice makes concurrent credit notes against it take turns
+	var total, credited int
+	err = tx.QueryRowContext(ctx, `
+		select total from invoices where id = $1 for update`, cn.InvoiceID).Scan(&total)
+	if err != nil {
+		return cn, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		select coalesce(sum(amount), 0) from credit_notes where invoice_id = $1`, cn.InvoiceID).Scan(&credited)
+	if err != nil {
+		return cn, err
+	}
+
+	if credited+cn.Amount > total {
+		return cn, ErrCreditExceedsInvoice
+	}
+
+	year := cn.IssuedAt.Year()
+	var n int
+	err = tx.QueryRowContext(ctx, `
+		insert into credit_note_sequences (year, last_number)
+		values ($1, 1)
+		on conflict (year) do update set
+			last_number = credit_note_sequences.last_number + 1
+		returning last_number`, year).Scan(&n)
+	if err != nil {
+		return cn, err
+	}
+
+	cn.Number = CreditNoteNumber(year, n)
+	cn.Status = CreditNoteStatusIssued
+
+	stmt := `
+		insert into credit_notes
+			(number, invoice_id, reference, amount, currency, reason, status, issued_at,
+			created_at, updated_at)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+		returning id, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(ctx, stmt,
+		cn.Number,
+		cn.InvoiceID,
+		cn.Reference,
+		cn.Amount,
+		cn.Currency,
+		cn.Reason,
+		cn.Status,
+		cn.IssuedAt,
+		time.Now(),
+	).Scan(&cn.ID, &cn.CreatedAt, &cn.UpdatedAt)
+	if err != nil {
+		return cn, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return cn, err
+	}
+
+	return cn, nil
+}
+
+// GetCreditNotesByInvoice returns the credit notes issued against an invoice, oldest first
+func (m *DBModel) GetCreditNotesByInvoice(invoiceID int) ([]*CreditNote, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, `
+		select `+creditNoteColumns+`
+		from
+			credit_notes c
+			join invoices i on (i.id = c.invoice_id)
+		where c.invoice_id = $1
+		order by c.issued_at, c.id`, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notes []*CreditNote
+	for rows.Next() {
+		cn, err := scanCreditNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, &cn)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return notes, nil
+}
+
+// SetCreditNotePDF records where the PDF of a credit note is stored
+func (m *DBModel) SetCreditNotePDF(id int, location string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `update credit_notes set pdf_location = $1, updated_at = $2 where id = $3`
+
+	_, err := m.DB.ExecContext(ctx, stmt, location, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarkCreditNoteSent marks a credit note as sent to the customer
+func (m *DBModel) MarkCreditNoteSent(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `
+		update credit_notes set
+			status = $1,
+			sent_at = $2,
+			updated_at = $2
+		where id = $3`
+
+	_, err := m.DB.ExecContext(ctx, stmt, CreditNoteStatusSent, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const creditNoteColumns = `
+			c.id, c.number, c.invoice_id, i.number, c.reference, c.amount, c.currency,
+			c.reason, c.status, c.pdf_location, c.issued_at, c.sent_at, c.created_at,
+			c.updated_at`
+
+func scanCreditNote(row rowScanner) (CreditNote, error) {
+	var cn CreditNote
+	var sentAt sql.NullTime
+
+	err := row.Scan(
+		&cn.ID,
+		&cn.Number,
+		&cn.InvoiceID,
+		&cn.InvoiceNumber,
+		&cn.Reference,
+		&cn.Amount,
+		&cn.Currency,
+		&cn.Reason,
+		&cn.Status,
+		&cn.PDFLocation,
+		&cn.IssuedAt,
+		&sentAt,
+		&cn.CreatedAt,
+		&cn.UpdatedAt,
+	)
+	if err != nil {
+		return cn, err
+	}
+
+	if sentAt.Valid {
+		cn.SentAt = &sentAt.Time
+	}
+
+	return cn, nil
+}
@@ -11,6 +11,7 @@ type Customer struct {
 	FirstName string    `json:"first_name"`
 	LastName  string    `json:"last_name"`
 	Email     string    `json:"email"`
+	Locale    string    `json:"locale"`
 	CreatedAt time.Time `json:"-"`
 	UpdatedAt time.Time `json:"-"`
 }
@@ -22,8 +23,8 @@ func (m *DBModel) InsertCustomer(c Customer) (int, error) {
 
 	stmt := `
 		INSERT INTO customers
-			(first_name, last_name, email, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+			(first_name, last_name, email, locale, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING id
 	`
 
@@ -34,6 +35,7 @@ func (m *DBModel) InsertCustomer(c Customer) (int, error) {
 		c.FirstName,
 		c.LastName,
 		c.Email,
+		c.Locale,
 		time.Now(),
 		time.Now(),
 	).Scan(&id)
@@ -43,3 +45,38 @@ func (m *DBModel) InsertCustomer(c Customer) (int, error) {
 
 	return id, nil
 }
+
+// EmailIsVerified reports whether email belongs to a user who verified it, or to a customer
+// with a cleared order
+func (m *DBModel) EmailIsVerified(email string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	query := `
+		select
+			exists (
+				select 1
+				from
+					users
+				where
+					lower(email) = lower($1)
+					and email_verified_at is not null
+			)
+			or exists (
+				select 1
+				from
+					customers c
+					join orders o on (o.customer_id = c.id)
+				where
+					lower(c.email) = lower($1)
+					and o.status_id = 1
+			)`
+
+	var verified bool
+	err := m.DB.QueryRowContext(ctx, query, email).Scan(&verified)
+	if err != nil {
+		return false, err
+	}
+
+	return verified, nil
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// EmailTemplateVersion is one saved edit of an email template, overriding the template compiled
+// into the service. The latest version of a template and locale is the one in use; a version
+// with UsesDefault set puts the compiled template back in use. Locale is empty for the default
+// template, used by languages without their own.
+type EmailTemplateVersion struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Locale      string    `json:"locale"`
+	Version     int       `json:"version"`
+	HTML        string    `json:"html"`
+	Plain       string    `json:"plain"`
+	UsesDefault bool      `json:"uses_default"`
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+const emailTemplateVersionColumns = `
+	id, name, locale, version, html, plain, uses_default, created_by, created_at
+`
+
+// CurrentEmailTemplate returns the latest version of a template, or sql.ErrNoRows if it has
+// never been edited
+func (m *DBModel) CurrentEmailTemplate(name, locale string) (EmailTemplateVersion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	query := `
+		select ` + emailTemplateVersionColumns + `
+		from
+			email_template_versions
+		where
+			name = $1 and locale = $2
+		order by
+			version desc
+		limit 1
+	`
+
+	return scanEmailTemplateVersion(m.DB.QueryRowContext(ctx, query, name, locale))
+}
+
+// GetEmailTemplateVersion returns one version of a template
+func (m *DBModel) GetEmailTemplateVersion(name, locale string, version int) (EmailTemplateVersion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	query := `
+		select ` + emailTemplateVersionColumns + `
+		from
+			email_template_versions
+		where
+			name = $1 and locale = $2 and version = $3
+	`
+
+	return scanEmailTemplateVersion(m.DB.QueryRowContext(ctx, query, name, locale, version))
+}
+
+// EmailTemplateVersions returns every version of a template, newest first
+func (m *DBModel) EmailTemplateVersions(name, locale string) ([]EmailTemplateVersion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	query := `
+		select ` + emailTemplateVersionColumns + `
+		from
+			email_template_versions
+		where
+			name = $1 and locale = $2
+		order by
+			version desc
+	`
+
+	rows, err := m.DB.QueryContext(ctx, query, name, locale)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []EmailTemplateVersion
+	for rows.Next() {
+		v, err := scanEmailTemplateVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+
+	return versions, rows.Err()
+}
+
+// InsertEmailTemplateVersion saves v as the next version of its template, which puts it in use.
+// It returns v with its id, version and creation time set.
+func (m *DBModel) InsertEmailTemplateVersion(v EmailTemplateVersion) (EmailTemplateVersion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// two edits saved at once would get the same version; the unique key then rejects one
+	stmt := `
+		insert into email_template_versions
+			(name, locale, version, html, plain, uses_default, created_by, created_at)
+		select
+			$1, $2, coalesce(max(version), 0) + 1, $3, $4, $5, $6, $7
+		from
+			email_template_versions
+		where
+			name = $1 and locale = $2
+		returning ` + emailTemplateVersionColumns
+
+	return scanEmailTemplateVersion(m.DB.QueryRowContext(ctx, stmt,
+		v.Name, v.Locale, v.HTML, v.Plain, v.UsesDefault, v.CreatedBy, time.Now()))
+}
+
+func scanEmailTemplateVersion(row rowScanner) (EmailTemplateVersion, error) {
+	var v EmailTemplateVersion
+	err := row.Scan(
+		&v.ID,
+		&v.Name,
+		&v.Locale,
+		&v.Version,
+		&v.HTML,
+		&v.Plain,
+		&v.UsesDefault,
+		&v.CreatedBy,
+		&v.CreatedAt,
+	)
+	return v, err
+}
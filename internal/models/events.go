@@ -0,0 +1,22 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/events"
+)
+
+// PublishEvent notifies everyone listening on events.Channel of e
+func (m *DBModel) PublishEvent(e events.Event) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	payload, err := events.Encode(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.DB.ExecContext(ctx, "select pg_notify($1, $2)", events.Channel, payload)
+	return err
+}
@@ -0,0 +1,336 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// Outbox statuses, shared by the invoice and mail outboxes
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusDelivered = "delivered"
+	OutboxStatusDead      = "dead"
+)
+
+// InvoiceRequest is what the invoice service needs to issue an invoice for an order
+type InvoiceRequest struct {
+	OrderID   int       `json:"order_id"`
+	Amount    int       `json:"amount"`
+	Product   string    `json:"product"`
+	Quantity  int       `json:"quantity"`
+	Currency  string    `json:"currency"`
+	CardBrand string    `json:"card_brand"`
+	LastFour  string    `json:"last_four"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Email     string    `json:"email"`
+	Country   string    `json:"country"`
+	Locale    string    `json:"locale"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// adjustments to Amount, printed on the invoice; tax is charged on the discounted amount
+	Discount      int    `json:"discount"`
+	DiscountLabel string `json:"discount_label"`
+	Shipping      int    `json:"shipping"`
+	TaxRateBps    int    `json:"tax_rate_bps"`
+}
+
+// InvoiceOutboxEntry is an invoice request waiting to be delivered to the invoice service
+type InvoiceOutboxEntry struct {
+	ID            int            `json:"id"`
+	OrderID       int            `json:"order_id"`
+	Payload       InvoiceRequest `json:"payload"`
+	Status        string         `json:"status"`
+	Attempts      int            `json:"attempts"`
+	NextAttemptAt time.Time      `json:"next_attempt_at"`
+	LastError     string         `json:"last_error"`
+	InvoiceNumber string         `json:"invoice_number"`
+	DeliveredAt   *time.Time     `json:"delivered_at"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// InsertOrderWithInvoiceRequest inserts a new order and queues an invoice request for it in the
+// same transaction, so a paid order can never be left without an invoice. It returns the order id.
+func (m *DBModel) InsertOrderWithInvoiceRequest(order Order, req InvoiceRequest) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt := `
+		INSERT INTO orders
+			(item_id, transaction_id, status_id, quantity, customer_id,
+			amount, locale, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+	var id int
+	err = tx.QueryRowContext(
+		ctx,
+		stmt,
+		order.ItemID,
+		order.TransactionID,
+		order.StatusID,
+		order.Quantity,
+		order.CustomerID,
+		order.Amount,
+		order.Locale,
+		time.Now(),
+		time.Now(),
+	).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	req.OrderID = id
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return 0, err
+	}
+
+	stmt = `
+		insert into invoice_outbox
+			(order_id, payload, status, next_attempt_at, created_at, updated_at)
+		values ($1, $2, $3, $4, $4, $4)
+	`
+	_, err = tx.ExecContext(ctx, stmt, id, payload, OutboxStatusPending, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// ClaimInvoiceOutbox claims up to limit pending entries that are due, counting an attempt for each.
+// Claimed entries are not due again until lease has passed, so other dispatchers skip them while
+// they are being delivered.
+func (m *DBModel) ClaimInvoiceOutbox(limit int, lease time.Duration) ([]*InvoiceOutboxEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	now := time.Now()
+
+	rows, err := m.DB.QueryContext(ctx, `
+		update invoice_outbox set
+			attempts = attempts + 1,
+			next_attempt_at = $1,
+			updated_at = $2
+		where id in (
+			select id
+			from
+				invoice_outbox
+			where
+				status = $3
+				and next_attempt_at <= $2
+			order by
+				next_attempt_at
+			limit $4
+			for update skip locked
+		)
+		returning `+invoiceOutboxColumns, now.Add(lease), now, OutboxStatusPending, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*InvoiceOutboxEntry
+	for rows.Next() {
+		e, err := scanInvoiceOutboxEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// MarkInvoiceOutboxDelivered records that an entry was delivered and the invoice it produced
+func (m *DBModel) MarkInvoiceOutboxDelivered(id int, invoiceNumber string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `
+		update invoice_outbox set
+			status = $1,
+			invoice_number = $2,
+			last_error = '',
+			delivered_at = $3,
+			updated_at = $3
+		where id = $4`
+
+	_, err := m.DB.ExecContext(ctx, stmt, OutboxStatusDelivered, invoiceNumber, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RescheduleInvoiceOutbox records a failed delivery and when to try again
+func (m *DBModel) RescheduleInvoiceOutbox(id int, lastError string, nextAttemptAt time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `
+		update invoice_outbox set
+			last_error = $1,
+			next_attempt_at = $2,
+			updated_at = $3
+		where id = $4`
+
+	_, err := m.DB.ExecContext(ctx, stmt, lastError, nextAttemptAt, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeadLetterInvoiceOutbox gives up on an entry. It stays in the outbox until an admin retries it.
+func (m *DBModel) DeadLetterInvoiceOutbox(id int, lastError string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `
+		update invoice_outbox set
+			status = $1,
+			last_error = $2,
+			updated_at = $3
+		where id = $4`
+
+	_, err := m.DB.ExecContext(ctx, stmt, OutboxStatusDead, lastError, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RetryInvoiceOutbox makes an undelivered entry due now, with a fresh set of attempts. It
+// returns sql.ErrNoRows if the entry does not exist or has already been delivered.
+func (m *DBModel) RetryInvoiceOutbox(id int) (*InvoiceOutboxEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return scanInvoiceOutboxEntry(m.DB.QueryRowContext(ctx, `
+		update invoice_outbox set
+			status = $1,
+			attempts = 0,
+			next_attempt_at = $2,
+			updated_at = $2
+		where
+			id = $3
+			and status <> $4
+		returning `+invoiceOutboxColumns, OutboxStatusPending, time.Now(), id, OutboxStatusDelivered))
+}
+
+// GetInvoiceOutboxPaginated returns a page of outbox entries, oldest first, optionally filtered by status
+func (m *DBModel) GetInvoiceOutboxPaginated(status string, pageSize, page int) ([]*InvoiceOutboxEntry, int, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	offset := (page - 1) * pageSize
+
+	var entries []*InvoiceOutboxEntry
+
+	query := `
+		select ` + invoiceOutboxColumns + `
+		from
+			invoice_outbox
+		where
+			$1 = '' or status = $1
+		order by
+			created_at, id
+		limit $2 offset $3
+	`
+
+	rows, err := m.DB.QueryContext(ctx, query, status, pageSize, offset)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e, err := scanInvoiceOutboxEntry(rows)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, 0, err
+	}
+
+	query = `
+		select
+			count(id)
+		from
+			invoice_outbox
+		where
+			$1 = '' or status = $1
+	`
+
+	var totalRecords int
+	countRow := m.DB.QueryRowContext(ctx, query, status)
+	err = countRow.Scan(&totalRecords)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	lastPage := LastPage(totalRecords, pageSize)
+
+	return entries, lastPage, totalRecords, nil
+}
+
+const invoiceOutboxColumns = `
+			id, order_id, payload, status, attempts, next_attempt_at, last_error,
+			invoice_number, delivered_at, created_at, updated_at`
+
+func scanInvoiceOutboxEntry(row rowScanner) (*InvoiceOutboxEntry, error) {
+	var e InvoiceOutboxEntry
+	var payload []byte
+	var deliveredAt sql.NullTime
+
+	err := row.Scan(
+		&e.ID,
+		&e.OrderID,
+		&payload,
+		&e.Status,
+		&e.Attempts,
+		&e.NextAttemptAt,
+		&e.LastError,
+		&e.InvoiceNumber,
+		&deliveredAt,
+		&e.CreatedAt,
+		&e.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(payload, &e.Payload); err != nil {
+		return nil, err
+	}
+	if deliveredAt.Valid {
+		e.DeliveredAt = &deliveredAt.Time
+	}
+
+	return &e, nil
+}
@@ -0,0 +1,428 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Invoice statuses
+const (
+	InvoiceStatusIssued = "issued"
+	InvoiceStatusSent   = "sent"
+	InvoiceStatusVoid   = "void"
+)
+
+// Invoice is the type for invoices issued by the invoice service. Amounts are in cents
+// (or the smallest unit of Currency), and Product summarises the line items.
+type Invoice struct {
+	ID            int               `json:"id"`
+	Number        string            `json:"number"`
+	OrderID       int               `json:"order_id"`
+	FirstName     string            `json:"first_name"`
+	LastName      string            `json:"last_name"`
+	Email         string            `json:"email"`
+	BuyerCountry  string            `json:"buyer_country"`
+	Locale        string            `json:"locale"`
+	Product       string            `json:"product"`
+	Quantity      int               `json:"quantity"`
+	LineItems     []InvoiceLineItem `json:"line_items"`
+	Currency      string            `json:"currency"`
+	Subtotal      int               `json:"subtotal"`
+	Discount      int               `json:"discount"`
+	DiscountLabel string            `json:"discount_label"`
+	Shipping      int               `json:"shipping"`
+	TaxRateBps    int               `json:"tax_rate_bps"`
+	Tax           int               `json:"tax"`
+	Total         int               `json:"total"`
+	CardBrand     string            `json:"card_brand"`
+	LastFour      string            `json:"last_four"`
+	Status        string            `json:"status"`
+	PDFLocation   string            `json:"pdf_location"`
+	IssuedAt      time.Time         `json:"issued_at"`
+	DueAt         time.Time         `json:"due_at"`
+	SentAt        *time.Time        `json:"sent_at"`
+	VoidedAt      *time.Time        `json:"voided_at"`
+	VoidReason    string            `json:"void_reason"`
+	CreatedAt     time.Time         `json:"-"`
+	UpdatedAt     time.Time         `json:"-"`
+}
+
+// InvoiceLineItem is one line of an invoice. Amount is Quantity * UnitPrice, unless the
+// line was priced as a whole.
+type InvoiceLineItem struct {
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+	UnitPrice   int    `json:"unit_price"`
+	Amount      int    `json:"amount"`
+}
+
+// CurrencyDecimals returns the number of decimals in amounts of currency, e.g. 2 for usd
+// (amounts in cents) and 0 for jpy, which has no minor unit
+func CurrencyDecimals(currency string) int {
+	switch strings.ToLower(currency) {
+	case "jpy", "krw", "vnd":
+		return 0
+	}
+	return 2
+}
+
+// CalculateTotals sets the subtotal, tax and total of inv from its line items, discount,
+// shipping and tax rate. Tax is charged on the discounted subtotal, not on shipping.
+func (inv *Invoice) CalculateTotals() {
+	inv.Subtotal = 0
+	inv.Quantity = 0
+	for _, line := range inv.LineItems {
+		inv.Subtotal += line.Amount
+		inv.Quantity += line.Quantity
+	}
+
+	taxable := max(inv.Subtotal-inv.Discount, 0)
+	// round half up to the nearest cent
+	inv.Tax = (taxable*inv.TaxRateBps + 5000) / 10000
+	inv.Total = taxable + inv.Shipping + inv.Tax
+}
+
+// InvoiceNumber formats the nth invoice of year, e.g. INV-2026-000123
+func InvoiceNumber(year, n int) string {
+	return fmt.Sprintf("INV-%d-%06d", year, n)
+}
+
+// CreateInvoice issues an invoice for inv.OrderID, taking the next number in the sequence for
+// the year of inv.IssuedAt. If the order already has an invoice, that invoice is returned instead.
+func (m *DBModel) CreateInvoice(inv Invoice) (Invoice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return inv, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	existing, err := scanInvoice(tx.QueryRowContext(ctx, `
+		select `+invoiceColumns+`
+		from
+			invoices
+		where order_id = $1`, inv.OrderID))
+	if err == nil {
+		existing.LineItems, err = invoiceLineItems(ctx, tx, existing.ID)
+		return existing, err
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return inv, err
+	}
+
+	// the upsert locks the year's row until commit, so concurrent invoices queue up for numbers
+	year := inv.IssuedAt.Year()
+	var n int
+	err = tx.QueryRowContext(ctx, `
+		insert into invoice_sequences (year, last_number)
+		values ($1, 1)
+		on conflict (year) do update set
+			last_number = invoice_sequences.last_number + 1
+		returning last_number`, year).Scan(&n)
+	if err != nil {
+		return inv, err
+	}
+
+	inv.Number = InvoiceNumber(year, n)
+	inv.Status = InvoiceStatusIssued
+
+	stmt := `
+		insert into invoices
+			(number, order_id, first_name, last_name, email, buyer_country, locale, product,
+			quantity, currency, subtotal, discount, discount_label, shipping, tax_rate_bps, tax,
+			total, card_brand, last_four, status, issued_at, due_at, created_at, updated_at)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
+			$17, $18, $19, $20, $21, $22, $23, $23)
+		returning id, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(ctx, stmt,
+		inv.Number,
+		inv.OrderID,
+		inv.FirstName,
+		inv.LastName,
+		inv.Email,
+		inv.BuyerCountry,
+		inv.Locale,
+		inv.Product,
+		inv.Quantity,
+		inv.Currency,
+		inv.Subtotal,
+		inv.Discount,
+		inv.DiscountLabel,
+		inv.Shipping,
+		inv.TaxRateBps,
+		inv.Tax,
+		inv.Total,
+		inv.CardBrand,
+		inv.LastFour,
+		inv.Status,
+		inv.IssuedAt,
+		inv.DueAt,
+		time.Now(),
+	).Scan(&inv.ID, &inv.CreatedAt, &inv.UpdatedAt)
+	if err != nil {
+		return inv, err
+	}
+
+	for i, line := range inv.LineItems {
+		_, err = tx.ExecContext(ctx, `
+			insert into invoice_line_items
+				(invoice_id, position, description, quantity, unit_price, amount)
+			values ($1, $2, $3, $4, $5, $6)`,
+			inv.ID, i+1, line.Description, line.Quantity, line.UnitPrice, line.Amount)
+		if err != nil {
+			return inv, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return inv, err
+	}
+
+	return inv, nil
+}
+
+// GetInvoiceByNumber gets an invoice, with its line items, by its invoice number
+func (m *DBModel) GetInvoiceByNumber(number string) (Invoice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	inv, err := scanInvoice(m.DB.QueryRowContext(ctx, `
+		select `+invoiceColumns+`
+		from
+			invoices
+		where number = $1`, number))
+	if err != nil {
+		return inv, err
+	}
+
+	inv.LineItems, err = invoiceLineItems(ctx, m.DB, inv.ID)
+	return inv, err
+}
+
+// GetInvoiceByOrderID gets the invoice, with its line items, issued for an order
+func (m *DBModel) GetInvoiceByOrderID(orderID int) (Invoice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	inv, err := scanInvoice(m.DB.QueryRowContext(ctx, `
+		select `+invoiceColumns+`
+		from
+			invoices
+		where order_id = $1`, orderID))
+	if err != nil {
+		return inv, err
+	}
+
+	inv.LineItems, err = invoiceLineItems(ctx, m.DB, inv.ID)
+	return inv, err
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func invoiceLineItems(ctx context.Context, db queryer, invoiceID int) ([]InvoiceLineItem, error) {
+	rows, err := db.QueryContext(ctx, `
+		select
+			description, quantity, unit_price, amount
+		from
+			invoice_line_items
+		where invoice_id = $1
+		order by position`, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []InvoiceLineItem
+	for rows.Next() {
+		var line InvoiceLineItem
+		if err := rows.Scan(&line.Description, &line.Quantity, &line.UnitPrice, &line.Amount); err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+// GetInvoicesPaginated returns a page of invoices, newest first, optionally filtered by
+// customer email and order id
+func (m *DBModel) GetInvoicesPaginated(email string, orderID, pageSize, page int) ([]*Invoice, int, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	offset := (page - 1) * pageSize
+
+	var invoices []*Invoice
+
+	// empty filters match everything
+	where := `
+		where
+			($1 = '' or lower(email) = lower($1))
+			and ($2 = 0 or order_id = $2)`
+
+	query := `
+		select ` + invoiceColumns + `
+		from
+			invoices
+		` + where + `
+		order by
+			issued_at desc, id desc
+		limit $3 offset $4
+	`
+
+	rows, err := m.DB.QueryContext(ctx, query, email, orderID, pageSize, offset)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		inv, err := scanInvoice(rows)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		invoices = append(invoices, &inv)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, 0, err
+	}
+
+	query = `
+		select
+			count(id)
+		from
+			invoices
+		` + where
+
+	var totalRecords int
+	countRow := m.DB.QueryRowContext(ctx, query, email, orderID)
+	err = countRow.Scan(&totalRecords)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	lastPage := LastPage(totalRecords, pageSize)
+
+	return invoices, lastPage, totalRecords, nil
+}
+
+// VoidInvoice voids an invoice. The invoice and its number are kept, so the sequence stays
+// gap-free. It returns sql.ErrNoRows if the invoice does not exist or is already void.
+func (m *DBModel) VoidInvoice(id int, reason string) (Invoice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return scanInvoice(m.DB.QueryRowContext(ctx, `
+		update invoices set
+			status = $1,
+			voided_at = $2,
+			void_reason = $3,
+			updated_at = $2
+		where
+			id = $4
+			and status <> $1
+		returning `+invoiceColumns, InvoiceStatusVoid, time.Now(), reason, id))
+}
+
+// SetInvoicePDF records where the PDF of an invoice is stored
+func (m *DBModel) SetInvoicePDF(id int, location string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `update invoices set pdf_location = $1, updated_at = $2 where id = $3`
+
+	_, err := m.DB.ExecContext(ctx, stmt, location, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarkInvoiceSent marks an invoice as sent to the customer. Void invoices are left alone.
+func (m *DBModel) MarkInvoiceSent(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `
+		update invoices set
+			status = $1,
+			sent_at = $2,
+			updated_at = $2
+		where
+			id = $3
+			and status <> $4`
+
+	_, err := m.DB.ExecContext(ctx, stmt, InvoiceStatusSent, time.Now(), id, InvoiceStatusVoid)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const invoiceColumns = `
+			id, number, order_id, first_name, last_name, email, buyer_country, locale, product,
+			quantity, currency, subtotal, discount, discount_label, shipping, tax_rate_bps, tax,
+			total, card_brand, last_four, status, pdf_location, issued_at, due_at, sent_at,
+			voided_at, void_reason, created_at, updated_at`
+
+func scanInvoice(row rowScanner) (Invoice, error) {
+	var inv Invoice
+	var sentAt, voidedAt sql.NullTime
+
+	err := row.Scan(
+		&inv.ID,
+		&inv.Number,
+		&inv.OrderID,
+		&inv.FirstName,
+		&inv.LastName,
+		&inv.Email,
+		&inv.BuyerCountry,
+		&inv.Locale,
+		&inv.Product,
+		&inv.Quantity,
+		&inv.Currency,
+		&inv.Subtotal,
+		&inv.Discount,
+		&inv.DiscountLabel,
+		&inv.Shipping,
+		&inv.TaxRateBps,
+		&inv.Tax,
+		&inv.Total,
+		&inv.CardBrand,
+		&inv.LastFour,
+		&inv.Status,
+		&inv.PDFLocation,
+		&inv.IssuedAt,
+		&inv.DueAt,
+		&sentAt,
+		&voidedAt,
+		&inv.VoidReason,
+		&inv.CreatedAt,
+		&inv.UpdatedAt,
+	)
+	if err != nil {
+		return inv, err
+	}
+
+	if sentAt.Valid {
+		inv.SentAt = &sentAt.Time
+	}
+	if voidedAt.Valid {
+		inv.VoidedAt = &voidedAt.Time
+	}
+
+	return inv, nil
+}
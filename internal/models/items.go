@@ -2,54 +2,391 @@ package models
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"strings"
 	"time"
 )
 
 // Yoyo is the type for all Yoyo
 type Item struct {
-	ID             int       `json:"id"`
-	Name           string    `json:"name"`
-	InventoryLevel int       `json:"inventory_level"`
-	Description    string    `json:"description"`
-	Price          int       `json:"price"`
-	Image          string    `json:"image"`
-	IsRecurring    bool      `json:"is_recurring"`
-	PlanID         string    `json:"plan_id"`
-	CreatedAt      time.Time `json:"-"`
-	UpdatedAt      time.Time `json:"-"`
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	Slug           string `json:"slug"`
+	Category       string `json:"category"`
+	InventoryLevel int    `json:"inventory_level"`
+	Description    string `json:"description"`
+	Price          int    `json:"price"`
+	Image          string `json:"image"`
+	IsRecurring    bool   `json:"is_recurring"`
+	// PlanID is the Stripe price a recurring item is subscribed to
+	PlanID          string    `json:"plan_id"`
+	StripeProductID string    `json:"stripe_product_id"`
+	Archived        bool      `json:"archived"`
+	CreatedAt       time.Time `json:"-"`
+	UpdatedAt       time.Time `json:"-"`
 }
 
+const itemColumns = `
+	id, name, slug, category, inventory_level, coalesce(description, ''), price, coalesce(image, ''),
+	coalesce(is_recurring, false), coalesce(plan_id, ''), stripe_product_id,
+	archived_at is not null, created_at, updated_at
+`
+
 // GetYoyo gets one yoyo by id
 func (m *DBModel) GetItem(id int) (Item, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	var item Item
-
 	row := m.DB.QueryRowContext(ctx, `
-		select 
-			id, name, inventory_level, description, price, coalesce(image, ''),
-			is_recurring, plan_id,
-			created_at, updated_at
+		select `+itemColumns+`
 		from
 			items
 		where id = $1`, id)
 
+	return scanItem(row)
+}
+
+// GetItemBySlug gets one item by its slug
+func (m *DBModel) GetItemBySlug(slug string) (Item, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	row := m.DB.QueryRowContext(ctx, `
+		select `+itemColumns+`
+		from
+			items
+		where slug = $1`, slug)
+
+	return scanItem(row)
+}
+
+// GetItemByOldSlug gets the item that had slug before it was renamed
+func (m *DBModel) GetItemByOldSlug(slug string) (Item, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	row := m.DB.QueryRowContext(ctx, `
+		select `+itemColumns+`
+		from
+			items
+		where id = (select item_id from item_slug_history where slug = $1)`, slug)
+
+	return scanItem(row)
+}
+
+// ItemSlugTaken reports whether an item other than exceptID has slug
+func (m *DBModel) ItemSlugTaken(slug string, exceptID int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var taken bool
+	err := m.DB.QueryRowContext(ctx, `
+		select exists (select 1 from items where slug = $1 and id <> $2)`, slug, exceptID).Scan(&taken)
+
+	return taken, err
+}
+
+// CatalogFilter narrows down the items on sale; zero values match everything
+type CatalogFilter struct {
+	Category string
+	// Query is searched for in the names and descriptions, in the syntax of web search engines
+	Query string
+}
+
+// where builds the where clause and arguments for the filter. Archived items are never on sale.
+func (f CatalogFilter) where() (string, []interface{}) {
+	conds := []string{"archived_at is null"}
+	var args []interface{}
+
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if f.Category != "" {
+		add("category = $%d", f.Category)
+	}
+	if f.Query != "" {
+		add("search @@ websearch_to_tsquery('english', $%d)", f.Query)
+	}
+
+	return "where " + strings.Join(conds, " and "), args
+}
+
+// orderBy sorts the best matches of a search first, and everything else by name
+func (f CatalogFilter) orderBy(args []interface{}) string {
+	if f.Query == "" {
+		return "name"
+	}
+	// the query is the last argument of the where clause
+	return fmt.Sprintf("ts_rank(search, websearch_to_tsquery('english', $%d)) desc, name", len(args))
+}
+
+// GetCatalogPaginated returns a slice of a subset of the items on sale matching filter
+func (m *DBModel) GetCatalogPaginated(filter CatalogFilter, pageSize, page int) ([]Item, int, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	offset := (page - 1) * pageSize
+
+	where, args := filter.where()
+
+	query := fmt.Sprintf(`
+		select `+itemColumns+`
+		from
+			items
+		%s
+		order by
+			%s
+		limit $%d offset $%d
+	`, where, filter.orderBy(args), len(args)+1, len(args)+2)
+
+	rows, err := m.DB.QueryContext(ctx, query, append(args, pageSize, offset)...)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer rows.Close()
+
+	var items []Item
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, 0, err
+	}
+
+	query = fmt.Sprintf(`
+		select
+			count(id)
+		from
+			items
+		%s
+	`, where)
+
+	var totalRecords int
+	countRow := m.DB.QueryRowContext(ctx, query, args...)
+	err = countRow.Scan(&totalRecords)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	lastPage := LastPage(totalRecords, pageSize)
+
+	return items, lastPage, totalRecords, nil
+}
+
+// GetItemCategories returns the categories of the items on sale
+func (m *DBModel) GetItemCategories() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, `
+		select distinct
+			category
+		from
+			items
+		where
+			archived_at is null and category <> ''
+		order by
+			category
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []string
+	for rows.Next() {
+		var c string
+		if err := rows.Scan(&c); err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+
+	return categories, rows.Err()
+}
+
+// GetAllItems returns every item, archived ones last
+func (m *DBModel) GetAllItems() ([]Item, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	query := `
+		select ` + itemColumns + `
+		from
+			items
+		order by
+			archived_at is not null, name
+	`
+
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []Item
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// InsertItem inserts a new item, and returns its id
+func (m *DBModel) InsertItem(item Item) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `
+		insert into items
+			(name, slug, category, inventory_level, description, price, image, is_recurring,
+			plan_id, stripe_product_id, archived_at, created_at, updated_at)
+		values
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, case when $11 then now() end, now(), now())
+		returning id
+	`
+
+	var id int
+	err := m.DB.QueryRowContext(ctx, stmt,
+		item.Name,
+		item.Slug,
+		item.Category,
+		item.InventoryLevel,
+		item.Description,
+		item.Price,
+		item.Image,
+		item.IsRecurring,
+		item.PlanID,
+		item.StripeProductID,
+		item.Archived,
+	).Scan(&id)
+
+	return id, err
+}
+
+// UpdateItem saves the changes to an item. An item archived before keeps when it was archived.
+// When the slug changes, the old one is kept in the item's slug history.
+func (m *DBModel) UpdateItem(item Item) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// the old slug now leads to this item, even if it once led to another
+	_, err = tx.ExecContext(ctx, `
+		insert into item_slug_history (slug, item_id, created_at)
+		select slug, id, now() from items where id = $1 and slug <> $2
+		on conflict (slug) do update set
+			item_id = excluded.item_id,
+			created_at = excluded.created_at`, item.ID, item.Slug)
+	if err != nil {
+		return err
+	}
+
+	// a slug in use is no longer an old one
+	_, err = tx.ExecContext(ctx, `delete from item_slug_history where slug = $1`, item.Slug)
+	if err != nil {
+		return err
+	}
+
+	stmt := `
+		update items set
+			name = $1,
+			slug = $2,
+			category = $3,
+			inventory_level = $4,
+			description = $5,
+			price = $6,
+			image = $7,
+			is_recurring = $8,
+			plan_id = $9,
+			stripe_product_id = $10,
+			archived_at = case when $11 then coalesce(archived_at, now()) end,
+			updated_at = now()
+		where id = $12
+	`
+
+	_, err = tx.ExecContext(ctx, stmt,
+		item.Name,
+		item.Slug,
+		item.Category,
+		item.InventoryLevel,
+		item.Description,
+		item.Price,
+		item.Image,
+		item.IsRecurring,
+		item.PlanID,
+		item.StripeProductID,
+		item.Archived,
+		item.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteItem deletes an item that was never ordered, or returns sql.ErrNoRows. Orders would be
+// deleted with their item, so an item that was ordered has to be archived instead.
+func (m *DBModel) DeleteItem(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `
+		delete from items
+		where
+			id = $1 and not exists (select 1 from orders where item_id = $1)
+	`
+
+	res, err := m.DB.ExecContext(ctx, stmt, id)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func scanItem(row rowScanner) (Item, error) {
+	var item Item
 	err := row.Scan(
 		&item.ID,
 		&item.Name,
+		&item.Slug,
+		&item.Category,
 		&item.InventoryLevel,
 		&item.Description,
 		&item.Price,
 		&item.Image,
 		&item.IsRecurring,
 		&item.PlanID,
+		&item.StripeProductID,
+		&item.Archived,
 		&item.CreatedAt,
 		&item.UpdatedAt,
 	)
-	if err != nil {
-		return item, err
-	}
-
-	return item, nil
+	return item, err
 }
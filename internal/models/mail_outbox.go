@@ -0,0 +1,239 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// MailOutboxEntry is an email waiting to be handed to the mail transport. Payload is the
+// message as JSON; it is emptied once the email is delivered, as bodies can hold reset tokens
+// and signed links. Ref says what the email is about, e.g. invoice:12, and may be empty.
+type MailOutboxEntry struct {
+	ID            int        `json:"id"`
+	Recipient     string     `json:"recipient"`
+	Subject       string     `json:"subject"`
+	Ref           string     `json:"ref"`
+	Payload       []byte     `json:"-"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     string     `json:"last_error"`
+	DeliveredAt   *time.Time `json:"delivered_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// InsertMailOutbox queues an email, due now, and returns its id
+func (m *DBModel) InsertMailOutbox(recipient, subject, ref string, payload []byte) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `
+		insert into mail_outbox
+			(recipient, subject, ref, payload, status, next_attempt_at, created_at, updated_at)
+		values ($1, $2, $3, $4, $5, $6, $6, $6)
+		returning id
+	`
+
+	var id int
+	err := m.DB.QueryRowContext(ctx, stmt, recipient, subject, ref, payload, OutboxStatusPending, time.Now()).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// ClaimMailOutbox claims up to limit pending emails that are due, counting an attempt for each.
+// Claimed emails are not due again until lease has passed, so other dispatchers skip them while
+// they are being sent.
+func (m *DBModel) ClaimMailOutbox(limit int, lease time.Duration) ([]*MailOutboxEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	now := time.Now()
+
+	rows, err := m.DB.QueryContext(ctx, `
+		update mail_outbox set
+			attempts = attempts + 1,
+			next_attempt_at = $1,
+			updated_at = $2
+		where id in (
+			select id
+			from
+				mail_outbox
+			where
+				status = $3
+				and next_attempt_at <= $2
+			order by
+				next_attempt_at
+			limit $4
+			for update skip locked
+		)
+		returning `+mailOutboxColumns, now.Add(lease), now, OutboxStatusPending, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*MailOutboxEntry
+	for rows.Next() {
+		e, err := scanMailOutboxEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// MarkMailOutboxDelivered records that an email was handed to the transport, and empties its
+// payload, which is only needed to send it
+func (m *DBModel) MarkMailOutboxDelivered(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `
+		update mail_outbox set
+			status = $1,
+			payload = '{}',
+			last_error = '',
+			delivered_at = $2,
+			updated_at = $2
+		where id = $3`
+
+	_, err := m.DB.ExecContext(ctx, stmt, OutboxStatusDelivered, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MailOutboxQueued reports whether an email about ref is waiting to be sent or was delivered
+func (m *DBModel) MailOutboxQueued(ref string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `
+		select exists (
+			select 1
+			from
+				mail_outbox
+			where
+				ref = $1
+				and status in ($2, $3)
+		)`
+
+	var queued bool
+	err := m.DB.QueryRowContext(ctx, stmt, ref, OutboxStatusPending, OutboxStatusDelivered).Scan(&queued)
+	if err != nil {
+		return false, err
+	}
+
+	return queued, nil
+}
+
+// RescheduleMailOutbox records a failed attempt and when to try again
+func (m *DBModel) RescheduleMailOutbox(id int, lastError string, nextAttemptAt time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `
+		update mail_outbox set
+			last_error = $1,
+			next_attempt_at = $2,
+			updated_at = $3
+		where id = $4`
+
+	_, err := m.DB.ExecContext(ctx, stmt, lastError, nextAttemptAt, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeadLetterMailOutbox gives up on an email
+func (m *DBModel) DeadLetterMailOutbox(id int, lastError string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `
+		update mail_outbox set
+			status = $1,
+			last_error = $2,
+			updated_at = $3
+		where id = $4`
+
+	_, err := m.DB.ExecContext(ctx, stmt, OutboxStatusDead, lastError, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PurgeMailOutbox deletes the delivered and dead emails last updated before, and returns how
+// many were deleted. Dead emails keep their payload until then, to be looked into.
+func (m *DBModel) PurgeMailOutbox(before time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `
+		delete from mail_outbox
+		where
+			status in ($1, $2)
+			and updated_at < $3`
+
+	res, err := m.DB.ExecContext(ctx, stmt, OutboxStatusDelivered, OutboxStatusDead, before)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n), nil
+}
+
+const mailOutboxColumns = `
+			id, recipient, subject, ref, payload, status, attempts, next_attempt_at, last_error,
+			delivered_at, created_at, updated_at`
+
+func scanMailOutboxEntry(row rowScanner) (*MailOutboxEntry, error) {
+	var e MailOutboxEntry
+	var deliveredAt sql.NullTime
+
+	err := row.Scan(
+		&e.ID,
+		&e.Recipient,
+		&e.Subject,
+		&e.Ref,
+		&e.Payload,
+		&e.Status,
+		&e.Attempts,
+		&e.NextAttemptAt,
+		&e.LastError,
+		&deliveredAt,
+		&e.CreatedAt,
+		&e.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if deliveredAt.Valid {
+		e.DeliveredAt = &deliveredAt.Time
+	}
+
+	return &e, nil
+}
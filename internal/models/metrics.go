@@ -0,0 +1,106 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// SalesTotal sums up the orders placed in one currency since some time
+type SalesTotal struct {
+	Currency string
+	Orders   int
+	Refunds  int
+	// Revenue is in cents, and leaves out refunded orders
+	Revenue int
+}
+
+// SubscriptionTotal sums up the active subscriptions in one currency
+type SubscriptionTotal struct {
+	Currency string
+	Active   int
+	// MRR is the monthly recurring revenue, in cents
+	MRR int
+}
+
+// GetSalesTotals sums up the orders placed since a time, one-time sales and subscriptions alike,
+// per currency
+func (m *DBModel) GetSalesTotals(since time.Time) ([]SalesTotal, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	query := `
+		select
+			lower(coalesce(t.currency, '')),
+			count(o.id),
+			count(o.id) filter (where o.status_id = 2),
+			coalesce(sum(o.amount) filter (where o.status_id <> 2), 0)
+		from
+			orders o
+			left join transactions t on (o.transaction_id = t.id)
+		where
+			o.created_at >= $1
+		group by
+			1
+		order by
+			1
+	`
+
+	rows, err := m.DB.QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []SalesTotal
+	for rows.Next() {
+		var t SalesTotal
+		if err := rows.Scan(&t.Currency, &t.Orders, &t.Refunds, &t.Revenue); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+
+	return totals, rows.Err()
+}
+
+// GetSubscriptionTotals sums up the subscriptions that are still active, per currency
+func (m *DBModel) GetSubscriptionTotals() ([]SubscriptionTotal, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// plans are billed monthly, so what an active subscription was sold for is what it brings
+	// in every month
+	query := `
+		select
+			lower(coalesce(t.currency, '')),
+			count(o.id),
+			coalesce(sum(o.amount), 0)
+		from
+			orders o
+			left join items i on (o.item_id = i.id)
+			left join transactions t on (o.transaction_id = t.id)
+		where
+			i.is_recurring = true and o.status_id = 1
+		group by
+			1
+		order by
+			1
+	`
+
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []SubscriptionTotal
+	for rows.Next() {
+		var t SubscriptionTotal
+		if err := rows.Scan(&t.Currency, &t.Active, &t.MRR); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+
+	return totals, rows.Err()
+}
@@ -18,3 +18,9 @@ func NewModels(db *sql.DB) Models {
 		DB: DBModel{DB: db},
 	}
 }
+
+// LastPage returns the number of the last page of total records, pageSize to a page. There is
+// always a first page, even if it is empty.
+func LastPage(total, pageSize int) int {
+	return max(1, (total+pageSize-1)/pageSize)
+}
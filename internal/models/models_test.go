@@ -0,0 +1,39 @@
+package models
+
+import "testing"
+
+func TestLastPage(t *testing.T) {
+	tests := []struct {
+		total, pageSize, want int
+	}{
+		{0, 10, 1},
+		{1, 10, 1},
+		{10, 10, 1},
+		{11, 10, 2},
+		{30, 10, 3},
+		{31, 10, 4},
+	}
+
+	for _, tt := range tests {
+		if got := LastPage(tt.total, tt.pageSize); got != tt.want {
+			t.Errorf("LastPage(%d, %d) = %d, want %d", tt.total, tt.pageSize, got, tt.want)
+		}
+	}
+}
+
+func TestInvoiceNumber(t *testing.T) {
+	tests := []struct {
+		year, n int
+		want    string
+	}{
+		{2026, 1, "INV-2026-000001"},
+		{2026, 123, "INV-2026-000123"},
+		{2027, 999999, "INV-2027-999999"},
+	}
+
+	for _, tt := range tests {
+		if got := InvoiceNumber(tt.year, tt.n); got != tt.want {
+			t.Errorf("InvoiceNumber(%d, %d) = %q, want %q", tt.year, tt.n, got, tt.want)
+		}
+	}
+}
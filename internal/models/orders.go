@@ -14,6 +14,7 @@ type Order struct {
 	StatusID      int         `json:"status_id"`
 	Quantity      int         `json:"quantity"`
 	Amount        int         `json:"amount"`
+	Locale        string      `json:"locale"`
 	CreatedAt     time.Time   `json:"-"`
 	UpdatedAt     time.Time   `json:"-"`
 	Item          Item        `json:"item"`
@@ -29,8 +30,8 @@ func (m *DBModel) InsertOrder(order Order) (int, error) {
 	stmt := `
 		INSERT INTO orders
 			(item_id, transaction_id, status_id, quantity, customer_id,
-			amount, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			amount, locale, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id
 	`
 	var id int
@@ -43,6 +44,7 @@ func (m *DBModel) InsertOrder(order Order) (int, error) {
 		order.Quantity,
 		order.CustomerID,
 		order.Amount,
+		order.Locale,
 		time.Now(),
 		time.Now(),
 	).Scan(&id)
@@ -138,7 +140,7 @@ func (m *DBModel) GetAllOrdersPaginated(pageSize, page int) ([]*Order, int, int,
 		return nil, 0, 0, err
 	}
 
-	lastPage := totalRecords / pageSize
+	lastPage := LastPage(totalRecords, pageSize)
 
 	return orders, lastPage, totalRecords, nil
 }
@@ -228,7 +230,7 @@ func (m *DBModel) GetAllSubscriptionsPaginated(pageSize, page int) ([]*Order, in
 		return nil, 0, 0, err
 	}
 
-	lastPage := totalRecords / pageSize
+	lastPage := LastPage(totalRecords, pageSize)
 
 	return orders, lastPage, totalRecords, nil
 }
@@ -243,7 +245,7 @@ func (m *DBModel) GetOrderByID(id int) (Order, error) {
 	query := `
 		select
 			o.id, o.item_id, o.transaction_id, o.customer_id,
-			o.status_id, o.quantity, o.amount, o.created_at,
+			o.status_id, o.quantity, o.amount, o.locale, o.created_at,
 			o.updated_at, i.id, i.name, t.id, t.amount, t.currency,
 			t.last_four, t.expiry_month, t.expiry_year, t.payment_intent,
 			t.bank_return_code, c.id, c.first_name, c.last_name, c.email
@@ -266,6 +268,7 @@ func (m *DBModel) GetOrderByID(id int) (Order, error) {
 		&o.StatusID,
 		&o.Quantity,
 		&o.Amount,
+		&o.Locale,
 		&o.CreatedAt,
 		&o.UpdatedAt,
 		&o.Item.ID,
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"time"
+)
+
+// ErrInvalidResetToken is returned when a password reset token is unknown,
+// expired or has already been used
+var ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+
+// InsertPasswordReset stores the hash of a password reset token for a user
+func (m *DBModel) InsertPasswordReset(t *Token) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `insert into password_resets (user_id, token_hash, expiry, created_at)
+			values ($1, $2, $3, $4)`
+
+	_, err := m.DB.ExecContext(ctx, stmt,
+		t.UserID,
+		t.Hash,
+		t.Expiry,
+		time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetUserForPasswordReset returns the user a password reset token was issued to,
+// without consuming the token
+func (m *DBModel) GetUserForPasswordReset(token string) (User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tokenHash := sha256.Sum256([]byte(token))
+	var u User
+
+	query := `
+		select
+			u.id, u.first_name, u.last_name, u.email
+		from
+			users u
+			inner join password_resets p on (u.id = p.user_id)
+		where
+			p.token_hash = $1
+			and p.used_at is null
+			and p.expiry > $2
+	`
+
+	err := m.DB.QueryRowContext(ctx, query, tokenHash[:], time.Now()).Scan(
+		&u.ID,
+		&u.FirstName,
+		&u.LastName,
+		&u.Email,
+	)
+	if err != nil {
+		return u, ErrInvalidResetToken
+	}
+
+	return u, nil
+}
+
+// ResetPasswordWithToken consumes a password reset token and sets the new password
+// hash in a single transaction, so a token can only ever be used once. Any other
+// outstanding reset tokens and auth tokens for the user are invalidated as well.
+func (m *DBModel) ResetPasswordWithToken(token, hash string) (User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tokenHash := sha256.Sum256([]byte(token))
+	var u User
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return u, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt := `
+		update password_resets set
+			used_at = $1
+		where
+			token_hash = $2
+			and used_at is null
+			and expiry > $1
+		returning user_id
+	`
+
+	err = tx.QueryRowContext(ctx, stmt, time.Now(), tokenHash[:]).Scan(&u.ID)
+	if err != nil {
+		return u, ErrInvalidResetToken
+	}
+
+	stmt = `
+		update users set
+			password = $1,
+			password_changed_at = $2,
+			updated_at = $2
+		where
+			id = $3
+		returning first_name, last_name, email
+	`
+
+	err = tx.QueryRowContext(ctx, stmt, hash, time.Now(), u.ID).Scan(
+		&u.FirstName,
+		&u.LastName,
+		&u.Email,
+	)
+	if err != nil {
+		return u, err
+	}
+
+	stmt = `update password_resets set used_at = $1 where user_id = $2 and used_at is null`
+	_, err = tx.ExecContext(ctx, stmt, time.Now(), u.ID)
+	if err != nil {
+		return u, err
+	}
+
+	stmt = `delete from tokens where user_id = $1`
+	_, err = tx.ExecContext(ctx, stmt, u.ID)
+	if err != nil {
+		return u, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return u, err
+	}
+
+	return u, nil
+}
@@ -12,6 +12,7 @@ import (
 
 const (
 	ScopeAuthentication = "authentication"
+	ScopePasswordReset  = "password-reset"
 )
 
 // Token is the type for authentication tokens
@@ -83,7 +84,7 @@ func (m *DBModel) GetUserForToken(token string) (*User, error) {
 
 	query := `
 		select
-			u.id, u.first_name, u.last_name, u.email
+			u.id, u.first_name, u.last_name, u.email, u.role, u.email_verified_at is not null
 		from
 			users u
 			inner join tokens t on (u.id = t.user_id)
@@ -97,6 +98,8 @@ func (m *DBModel) GetUserForToken(token string) (*User, error) {
 		&user.FirstName,
 		&user.LastName,
 		&user.Email,
+		&user.Role,
+		&user.EmailVerified,
 	)
 
 	if err != nil {
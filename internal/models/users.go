@@ -2,25 +2,35 @@ package models
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"strings"
 	"time"
 
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/passwords"
 	"github.com/rs/zerolog/log"
-	"golang.org/x/crypto/bcrypt"
 )
 
+// RoleAdmin is the role of users with full access to the admin area
+const RoleAdmin = "admin"
+
 // User is the type for users
 type User struct {
-	ID        int       `json:"id"`
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	Email     string    `json:"email"`
-	Password  string    `json:"password"`
-	CreatedAt time.Time `json:"-"`
-	UpdatedAt time.Time `json:"-"`
+	ID            int       `json:"id"`
+	FirstName     string    `json:"first_name"`
+	LastName      string    `json:"last_name"`
+	Email         string    `json:"email"`
+	Password      string    `json:"password"`
+	Role          string    `json:"role"`
+	EmailVerified bool      `json:"email_verified"`
+	PendingEmail  string    `json:"pending_email,omitempty"`
+	CreatedAt     time.Time `json:"-"`
+	UpdatedAt     time.Time `json:"-"`
 }
 
+// ErrEmailNotVerified is returned when an unverified user tries to sign in
+var ErrEmailNotVerified = errors.New("email address has not been verified")
+
 // GetUserByEmail gets a user by email address
 func (m *DBModel) GetUserByEmail(email string) (User, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -31,7 +41,8 @@ func (m *DBModel) GetUserByEmail(email string) (User, error) {
 
 	row := m.DB.QueryRowContext(ctx, `
 		select
-			id, first_name, last_name, email, password, created_at, updated_at
+			id, first_name, last_name, email, password, role,
+			email_verified_at is not null, coalesce(pending_email, ''), created_at, updated_at
 		from
 			users
 		where email = $1`, email)
@@ -42,6 +53,9 @@ func (m *DBModel) GetUserByEmail(email string) (User, error) {
 		&u.LastName,
 		&u.Email,
 		&u.Password,
+		&u.Role,
+		&u.EmailVerified,
+		&u.PendingEmail,
 		&u.CreatedAt,
 		&u.UpdatedAt,
 	)
@@ -78,7 +92,8 @@ func (m *DBModel) GetAllUsersPaginated(pageSize, page int) ([]*User, int, int, e
 
 	query := `
 		select
-			id, last_name, first_name, email, created_at, updated_at
+			id, last_name, first_name, email, role,
+			email_verified_at is not null, coalesce(pending_email, ''), created_at, updated_at
 		from
 			users
 		order by
@@ -99,6 +114,9 @@ func (m *DBModel) GetAllUsersPaginated(pageSize, page int) ([]*User, int, int, e
 			&u.LastName,
 			&u.FirstName,
 			&u.Email,
+			&u.Role,
+			&u.EmailVerified,
+			&u.PendingEmail,
 			&u.CreatedAt,
 			&u.UpdatedAt,
 		)
@@ -122,7 +140,7 @@ func (m *DBModel) GetAllUsersPaginated(pageSize, page int) ([]*User, int, int, e
 		return nil, 0, 0, err
 	}
 
-	lastPage := totalRecords / pageSize
+	lastPage := LastPage(totalRecords, pageSize)
 
 	return users, lastPage, totalRecords, nil
 }
@@ -136,7 +154,8 @@ func (m *DBModel) GetOneUser(id int) (User, error) {
 
 	query := `
 		select
-			id, last_name, first_name, email, created_at, updated_at
+			id, last_name, first_name, email, role,
+			email_verified_at is not null, coalesce(pending_email, ''), created_at, updated_at
 		from
 			users
 		where id = $1`
@@ -148,6 +167,9 @@ func (m *DBModel) GetOneUser(id int) (User, error) {
 		&u.LastName,
 		&u.FirstName,
 		&u.Email,
+		&u.Role,
+		&u.EmailVerified,
+		&u.PendingEmail,
 		&u.CreatedAt,
 		&u.UpdatedAt,
 	)
@@ -157,7 +179,7 @@ func (m *DBModel) GetOneUser(id int) (User, error) {
 	return u, nil
 }
 
-// EditUser edits an existing user
+// EditUser edits an existing user's name. Email changes go through SetPendingEmail.
 func (m *DBModel) EditUser(u User) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
@@ -166,15 +188,13 @@ func (m *DBModel) EditUser(u User) error {
 		update users set
 			first_name = $1,
 			last_name = $2,
-			email = $3,
-			updated_at = $4
+			updated_at = $3
 		where
-			id = $5`
+			id = $4`
 
 	_, err := m.DB.ExecContext(ctx, stmt,
 		u.FirstName,
 		u.LastName,
-		u.Email,
 		time.Now(),
 		u.ID,
 	)
@@ -185,30 +205,79 @@ func (m *DBModel) EditUser(u User) error {
 	return nil
 }
 
-// AddUser inserts a user into the database
-func (m *DBModel) AddUser(u User, hash string) error {
+// SetPendingEmail records an email change, which takes effect once VerifyEmail confirms it
+func (m *DBModel) SetPendingEmail(id int, email string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `update users set pending_email = $1, updated_at = $2 where id = $3`
+
+	_, err := m.DB.ExecContext(ctx, stmt, strings.ToLower(email), time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// VerifyEmail confirms that user id owns email, which is either their pending email
+// or their unverified current email. It returns sql.ErrNoRows if there is nothing to verify.
+func (m *DBModel) VerifyEmail(id int, email string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `
+		update users set
+			email = $1,
+			pending_email = null,
+			email_verified_at = $2,
+			updated_at = $2
+		where
+			id = $3
+			and (pending_email = $1 or (email = $1 and email_verified_at is null))`
+
+	res, err := m.DB.ExecContext(ctx, stmt, strings.ToLower(email), time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// AddUser inserts a user into the database, and returns its id
+func (m *DBModel) AddUser(u User, hash string) (int, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
 	stmt := `
 		insert into users (first_name, last_name, email, password, created_at, updated_at) 
 		values ($1, $2, $3, $4, $5, $6)
+		returning id
 	`
 
-	_, err := m.DB.ExecContext(ctx, stmt,
+	var id int
+	err := m.DB.QueryRowContext(ctx, stmt,
 		u.FirstName,
 		u.LastName,
 		u.Email,
 		hash,
 		time.Now(),
 		time.Now(),
-	)
+	).Scan(&id)
 
 	if err != nil {
 		log.Error().Err(err).Msg("add user")
-		return err
+		return 0, err
 	}
-	return nil
+	return id, nil
 }
 
 // DeleteUser deletes a user by id
@@ -232,6 +301,90 @@ func (m *DBModel) DeleteUser(id int) error {
 	return nil
 }
 
+// GetUserByOIDCSubject gets the user linked to an identity provider subject
+func (m *DBModel) GetUserByOIDCSubject(subject string) (User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var u User
+
+	row := m.DB.QueryRowContext(ctx, `
+		select
+			id, first_name, last_name, email, role,
+			email_verified_at is not null, coalesce(pending_email, ''), created_at, updated_at
+		from
+			users
+		where oidc_subject = $1`, subject)
+
+	err := row.Scan(
+		&u.ID,
+		&u.FirstName,
+		&u.LastName,
+		&u.Email,
+		&u.Role,
+		&u.EmailVerified,
+		&u.PendingEmail,
+		&u.CreatedAt,
+		&u.UpdatedAt,
+	)
+	if err != nil {
+		return u, err
+	}
+
+	return u, nil
+}
+
+// LinkOIDCSubject links an existing user to an identity provider subject
+func (m *DBModel) LinkOIDCSubject(id int, subject string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// the identity provider has verified the email address the user was matched by
+	stmt := `
+		update users set
+			oidc_subject = $1,
+			email_verified_at = coalesce(email_verified_at, $2),
+			updated_at = $2
+		where
+			id = $3`
+
+	_, err := m.DB.ExecContext(ctx, stmt, subject, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AddOIDCUser inserts a user provisioned from an identity provider, and returns its id.
+// These users have no local password, so can only sign in through the provider.
+func (m *DBModel) AddOIDCUser(u User, subject string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `
+		insert into users (first_name, last_name, email, password, oidc_subject, role, email_verified_at, created_at, updated_at)
+		values ($1, $2, $3, '', $4, $5, $6, $6, $6)
+		returning id
+	`
+
+	var id int
+	err := m.DB.QueryRowContext(ctx, stmt,
+		u.FirstName,
+		u.LastName,
+		strings.ToLower(u.Email),
+		subject,
+		u.Role,
+		time.Now(),
+	).Scan(&id)
+
+	if err != nil {
+		log.Error().Err(err).Msg("add oidc user")
+		return 0, err
+	}
+	return id, nil
+}
+
 // Authenticate attempts to log a user in by comparing supplied password with password hash
 func (m *DBModel) Authenticate(email, password string) (int, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -239,18 +392,32 @@ func (m *DBModel) Authenticate(email, password string) (int, error) {
 
 	var id int
 	var hashedPassword string
+	var verified bool
 
-	row := m.DB.QueryRowContext(ctx, "select id, password from users where email = $1", email)
-	err := row.Scan(&id, &hashedPassword)
+	row := m.DB.QueryRowContext(ctx, "select id, password, email_verified_at is not null from users where email = $1", email)
+	err := row.Scan(&id, &hashedPassword, &verified)
 	if err != nil {
 		return id, err
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-	if err == bcrypt.ErrMismatchedHashAndPassword {
-		return 0, errors.New("incorrect password")
-	} else if err != nil {
+	match, needsRehash, err := passwords.Verify(password, hashedPassword)
+	if err != nil {
 		return 0, err
+	} else if !match {
+		return 0, errors.New("incorrect password")
+	}
+
+	if !verified {
+		return 0, ErrEmailNotVerified
+	}
+
+	// upgrade bcrypt or outdated argon2id hashes while we have the plain text password
+	if needsRehash {
+		if newHash, err := passwords.Hash(password); err != nil {
+			log.Error().Err(err).Msg("Authenticate")
+		} else if err := m.UpdatePasswordForUser(User{ID: id}, newHash); err != nil {
+			log.Error().Err(err).Msg("Authenticate")
+		}
 	}
 
 	return id, nil
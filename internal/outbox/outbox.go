@@ -0,0 +1,93 @@
+// Package outbox delivers the rows of an outbox table, such as queued emails or invoice requests.
+// Dispatchers are safe to run in every replica.
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// PollInterval is how often an idle dispatcher looks for due entries
+	PollInterval = 5 * time.Second
+	// leaseMargin is how long an entry stays claimed after its send times out, to record the outcome
+	leaseMargin = 30 * time.Second
+	baseBackoff = 30 * time.Second
+	maxBackoff  = time.Hour
+)
+
+// Dispatcher claims the due entries of an outbox and delivers them, one at a time. As an entry
+// is only claimed right before it is sent, its lease only has to outlast its own send, however
+// long the backlog is, and no other replica claims it again while it is being sent.
+type Dispatcher[E any] struct {
+	// Name is logged when the dispatcher stops
+	Name string
+	// Claim claims up to limit due entries, which stay claimed for lease
+	Claim func(limit int, lease time.Duration) ([]E, error)
+	// Deliver sends an entry and records the outcome; its ctx ends after SendTimeout
+	Deliver     func(ctx context.Context, e E)
+	SendTimeout time.Duration
+	// Housekeeping, when set, is called before each poll, e.g. to purge old entries
+	Housekeeping func()
+}
+
+// Lease is how long a claimed entry is skipped by other dispatchers
+func (d Dispatcher[E]) Lease() time.Duration {
+	return d.SendTimeout + leaseMargin
+}
+
+// Next claims one due entry and delivers it, reporting whether there was one
+func (d Dispatcher[E]) Next(ctx context.Context) bool {
+	entries, err := d.Claim(1, d.Lease())
+	if err != nil {
+		log.Error().Err(err).Str("dispatcher", d.Name).Msg("Next")
+		return false
+	}
+	if len(entries) == 0 {
+		return false
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, d.SendTimeout)
+	defer cancel()
+
+	d.Deliver(sendCtx, entries[0])
+	return true
+}
+
+// Run delivers due entries until ctx is done
+func (d Dispatcher[E]) Run(ctx context.Context) {
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if d.Housekeeping != nil {
+			d.Housekeeping()
+		}
+
+		// keep going while there is a backlog, rather than waiting for the next tick
+		for ctx.Err() == nil && d.Next(ctx) {
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info().Msgf("%s is stopped", d.Name)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Backoff is how long to wait before the next attempt, after the given number of attempts:
+// 30s, 1m, 2m, ... up to an hour
+func Backoff(attempts int) time.Duration {
+	backoff := baseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return backoff
+}
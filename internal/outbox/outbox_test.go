@@ -0,0 +1,120 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{50, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := Backoff(tt.attempts); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+// TestLeaseOutlastsSend checks that an entry is still claimed for as long as it can be sending,
+// however many entries are due, so another replica can't claim and send it a second time
+func TestLeaseOutlastsSend(t *testing.T) {
+	backlog := 25
+	var claimedAt time.Time
+	var lease time.Duration
+
+	d := Dispatcher[int]{
+		Name: "test dispatcher",
+		Claim: func(limit int, l time.Duration) ([]int, error) {
+			if limit != 1 {
+				t.Errorf("expected entries to be claimed one at a time, got %d", limit)
+			}
+			if backlog == 0 {
+				return nil, nil
+			}
+			backlog--
+			claimedAt, lease = time.Now(), l
+			return []int{backlog}, nil
+		},
+		Deliver: func(ctx context.Context, e int) {
+			deadline, ok := ctx.Deadline()
+			if !ok {
+				t.Fatal("expected the send to have a deadline")
+			}
+			if leaseEnd := claimedAt.Add(lease); !deadline.Before(leaseEnd) {
+				t.Errorf("entry %d: the send may run until %v, after its lease ends at %v", e, deadline, leaseEnd)
+			}
+		},
+		SendTimeout: time.Minute,
+	}
+
+	n := 0
+	for d.Next(context.Background()) {
+		n++
+	}
+	if n != 25 {
+		t.Fatalf("expected the whole backlog to be delivered, got %d", n)
+	}
+}
+
+func TestNextClaimError(t *testing.T) {
+	d := Dispatcher[int]{
+		Claim: func(int, time.Duration) ([]int, error) { return nil, errors.New("db down") },
+		Deliver: func(context.Context, int) {
+			t.Fatal("expected nothing to be delivered")
+		},
+		SendTimeout: time.Second,
+	}
+
+	if d.Next(context.Background()) {
+		t.Fatal("expected no entry")
+	}
+}
+
+func TestRunDrainsBacklog(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	backlog := 3
+	housekeeping := 0
+	d := Dispatcher[int]{
+		Name: "test dispatcher",
+		Claim: func(int, time.Duration) ([]int, error) {
+			if backlog == 0 {
+				// the backlog is delivered without waiting for the next poll
+				cancel()
+				return nil, nil
+			}
+			backlog--
+			return []int{backlog}, nil
+		},
+		Deliver:      func(context.Context, int) {},
+		SendTimeout:  time.Second,
+		Housekeeping: func() { housekeeping++ },
+	}
+
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(PollInterval / 2):
+		t.Fatal("expected the backlog to be delivered before the next poll")
+	}
+	if housekeeping != 1 {
+		t.Errorf("expected housekeeping once, got %d", housekeeping)
+	}
+}
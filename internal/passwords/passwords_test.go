@@ -0,0 +1,77 @@
+package passwords
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// cheap parameters keep the tests fast
+var testParams = Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestHashAndVerify(t *testing.T) {
+	h := &Hasher{Params: testParams}
+
+	hash, err := h.Hash("correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	match, rehash, err := h.Verify("correct horse battery staple", hash)
+	if err != nil || !match || rehash {
+		t.Fatalf("got match=%v rehash=%v err=%v", match, rehash, err)
+	}
+
+	match, _, err = h.Verify("wrong", hash)
+	if err != nil || match {
+		t.Fatalf("got match=%v err=%v for the wrong password", match, err)
+	}
+
+	// stronger parameters make existing hashes outdated
+	stronger := &Hasher{Params: testParams}
+	stronger.Params.Iterations = 2
+	match, rehash, _ = stronger.Verify("correct horse battery staple", hash)
+	if !match || !rehash {
+		t.Fatalf("got match=%v rehash=%v with stronger params", match, rehash)
+	}
+}
+
+func TestVerifyBcrypt(t *testing.T) {
+	h := &Hasher{Params: testParams}
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+
+	match, rehash, err := h.Verify("secret", string(hash))
+	if err != nil || !match || !rehash {
+		t.Fatalf("got match=%v rehash=%v err=%v", match, rehash, err)
+	}
+
+	match, _, _ = h.Verify("wrong", string(hash))
+	if match {
+		t.Fatal("expected passwords not to match")
+	}
+
+	if _, _, err := h.Verify("secret", "plain"); err != ErrUnknownHash {
+		t.Fatalf("got %v, want ErrUnknownHash", err)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	p := Policy{MinLength: 8}
+
+	tests := []struct {
+		password string
+		ok       bool
+	}{
+		{"short", false},
+		{"Password123", false},
+		{"qwertyuiop", false},
+		{"plum tree lantern", true},
+	}
+
+	for _, tt := range tests {
+		err := p.Check(tt.password)
+		if (err == nil) != tt.ok {
+			t.Errorf("Check(%q) = %v", tt.password, err)
+		}
+	}
+}
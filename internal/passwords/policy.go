@@ -0,0 +1,49 @@
+package passwords
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultMinLength is used when a policy has no minimum length set
+const DefaultMinLength = 10
+
+var ErrCommonPassword = errors.New("password is too common")
+
+//go:embed common-passwords.txt
+var commonPasswordList string
+
+// commonPasswords is the bundled list of common passwords, lower cased
+var commonPasswords = func() map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, p := range strings.Fields(commonPasswordList) {
+		set[p] = struct{}{}
+	}
+	return set
+}()
+
+// Policy describes which passwords users may choose
+type Policy struct {
+	MinLength int
+}
+
+// Check returns an error describing why password is not allowed by the policy, or nil
+func (p Policy) Check(password string) error {
+	minLength := p.MinLength
+	if minLength <= 0 {
+		minLength = DefaultMinLength
+	}
+
+	if utf8.RuneCountInString(password) < minLength {
+		return fmt.Errorf("password must be at least %d characters", minLength)
+	}
+
+	if _, ok := commonPasswords[strings.ToLower(password)]; ok {
+		return ErrCommonPassword
+	}
+
+	return nil
+}
@@ -22,6 +22,78 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// LineItem is one line of an invoice. Amounts are in the smallest unit of the currency;
+// amount defaults to quantity * unit_price when it is zero.
+type LineItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Description   string                 `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	UnitPrice     int32                  `protobuf:"varint,3,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	Amount        int32                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LineItem) Reset() {
+	*x = LineItem{}
+	mi := &file_invoice_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LineItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LineItem) ProtoMessage() {}
+
+func (x *LineItem) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LineItem.ProtoReflect.Descriptor instead.
+func (*LineItem) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LineItem) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *LineItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *LineItem) GetUnitPrice() int32 {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return 0
+}
+
+func (x *LineItem) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+// CreateInvoiceRequest describes an order to invoice. When line_items is empty, the order is
+// invoiced as a single line of quantity x product, for amount in total.
 type CreateInvoiceRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -33,13 +105,25 @@ type CreateInvoiceRequest struct {
 	LastName      string                 `protobuf:"bytes,7,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
 	Email         string                 `protobuf:"bytes,8,opt,name=email,proto3" json:"email,omitempty"`
 	CreatedAt     *timestamp.Timestamp   `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LineItems     []*LineItem            `protobuf:"bytes,10,rep,name=line_items,json=lineItems,proto3" json:"line_items,omitempty"`
+	Currency      string                 `protobuf:"bytes,11,opt,name=currency,proto3" json:"currency,omitempty"`
+	Discount      int32                  `protobuf:"varint,12,opt,name=discount,proto3" json:"discount,omitempty"`
+	DiscountLabel string                 `protobuf:"bytes,13,opt,name=discount_label,json=discountLabel,proto3" json:"discount_label,omitempty"`
+	Shipping      int32                  `protobuf:"varint,14,opt,name=shipping,proto3" json:"shipping,omitempty"`
+	TaxRateBps    int32                  `protobuf:"varint,15,opt,name=tax_rate_bps,json=taxRateBps,proto3" json:"tax_rate_bps,omitempty"`
+	CardBrand     string                 `protobuf:"bytes,16,opt,name=card_brand,json=cardBrand,proto3" json:"card_brand,omitempty"`
+	LastFour      string                 `protobuf:"bytes,17,opt,name=last_four,json=lastFour,proto3" json:"last_four,omitempty"`
+	// ISO 3166-1 alpha-2 country of the customer, required for e-invoices
+	BuyerCountry string `protobuf:"bytes,18,opt,name=buyer_country,json=buyerCountry,proto3" json:"buyer_country,omitempty"`
+	// language of the customer, e.g. de; invoices fall back to English
+	Locale        string `protobuf:"bytes,19,opt,name=locale,proto3" json:"locale,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateInvoiceRequest) Reset() {
 	*x = CreateInvoiceRequest{}
-	mi := &file_invoice_proto_msgTypes[0]
+	mi := &file_invoice_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -51,7 +135,7 @@ func (x *CreateInvoiceRequest) String() string {
 func (*CreateInvoiceRequest) ProtoMessage() {}
 
 func (x *CreateInvoiceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_invoice_proto_msgTypes[0]
+	mi := &file_invoice_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -64,7 +148,7 @@ func (x *CreateInvoiceRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateInvoiceRequest.ProtoReflect.Descriptor instead.
 func (*CreateInvoiceRequest) Descriptor() ([]byte, []int) {
-	return file_invoice_proto_rawDescGZIP(), []int{0}
+	return file_invoice_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *CreateInvoiceRequest) GetId() int32 {
@@ -130,16 +214,87 @@ func (x *CreateInvoiceRequest) GetCreatedAt() *timestamp.Timestamp {
 	return nil
 }
 
+func (x *CreateInvoiceRequest) GetLineItems() []*LineItem {
+	if x != nil {
+		return x.LineItems
+	}
+	return nil
+}
+
+func (x *CreateInvoiceRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CreateInvoiceRequest) GetDiscount() int32 {
+	if x != nil {
+		return x.Discount
+	}
+	return 0
+}
+
+func (x *CreateInvoiceRequest) GetDiscountLabel() string {
+	if x != nil {
+		return x.DiscountLabel
+	}
+	return ""
+}
+
+func (x *CreateInvoiceRequest) GetShipping() int32 {
+	if x != nil {
+		return x.Shipping
+	}
+	return 0
+}
+
+func (x *CreateInvoiceRequest) GetTaxRateBps() int32 {
+	if x != nil {
+		return x.TaxRateBps
+	}
+	return 0
+}
+
+func (x *CreateInvoiceRequest) GetCardBrand() string {
+	if x != nil {
+		return x.CardBrand
+	}
+	return ""
+}
+
+func (x *CreateInvoiceRequest) GetLastFour() string {
+	if x != nil {
+		return x.LastFour
+	}
+	return ""
+}
+
+func (x *CreateInvoiceRequest) GetBuyerCountry() string {
+	if x != nil {
+		return x.BuyerCountry
+	}
+	return ""
+}
+
+func (x *CreateInvoiceRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
 type CreateInvoiceResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	InvoiceNumber string                 `protobuf:"bytes,2,opt,name=invoice_number,json=invoiceNumber,proto3" json:"invoice_number,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateInvoiceResponse) Reset() {
 	*x = CreateInvoiceResponse{}
-	mi := &file_invoice_proto_msgTypes[1]
+	mi := &file_invoice_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -151,7 +306,7 @@ func (x *CreateInvoiceResponse) String() string {
 func (*CreateInvoiceResponse) ProtoMessage() {}
 
 func (x *CreateInvoiceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_invoice_proto_msgTypes[1]
+	mi := &file_invoice_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -164,7 +319,7 @@ func (x *CreateInvoiceResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateInvoiceResponse.ProtoReflect.Descriptor instead.
 func (*CreateInvoiceResponse) Descriptor() ([]byte, []int) {
-	return file_invoice_proto_rawDescGZIP(), []int{1}
+	return file_invoice_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *CreateInvoiceResponse) GetMessage() string {
@@ -174,27 +329,1903 @@ func (x *CreateInvoiceResponse) GetMessage() string {
 	return ""
 }
 
-var File_invoice_proto protoreflect.FileDescriptor
+func (x *CreateInvoiceResponse) GetInvoiceNumber() string {
+	if x != nil {
+		return x.InvoiceNumber
+	}
+	return ""
+}
 
-const file_invoice_proto_rawDesc = "" +
-	"\n" +
-	"\rinvoice.proto\x12\ainvoice\x1a\x1fgoogle/protobuf/timestamp.proto\"\x9a\x02\n" +
-	"\x14CreateInvoiceRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x17\n" +
-	"\aitem_id\x18\x02 \x01(\x05R\x06itemId\x12\x16\n" +
-	"\x06amount\x18\x03 \x01(\x05R\x06amount\x12\x18\n" +
-	"\aproduct\x18\x04 \x01(\tR\aproduct\x12\x1a\n" +
-	"\bquantity\x18\x05 \x01(\x05R\bquantity\x12\x1d\n" +
-	"\n" +
-	"first_name\x18\x06 \x01(\tR\tfirstName\x12\x1b\n" +
-	"\tlast_name\x18\a \x01(\tR\blastName\x12\x14\n" +
-	"\x05email\x18\b \x01(\tR\x05email\x129\n" +
-	"\n" +
-	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"1\n" +
-	"\x15CreateInvoiceResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage2g\n" +
-	"\x0eInvoiceService\x12U\n" +
-	"\x14CreateAndSendInvoice\x12\x1d.invoice.CreateInvoiceRequest\x1a\x1e.invoice.CreateInvoiceResponseB:Z8github.com/LamThanhNguyen/yoyo-store-backend/internal/pbb\x06proto3"
+type Invoice struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Number        string                 `protobuf:"bytes,2,opt,name=number,proto3" json:"number,omitempty"`
+	OrderId       int32                  `protobuf:"varint,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	FirstName     string                 `protobuf:"bytes,4,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName      string                 `protobuf:"bytes,5,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Email         string                 `protobuf:"bytes,6,opt,name=email,proto3" json:"email,omitempty"`
+	Product       string                 `protobuf:"bytes,7,opt,name=product,proto3" json:"product,omitempty"`
+	Quantity      int32                  `protobuf:"varint,8,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Subtotal      int32                  `protobuf:"varint,9,opt,name=subtotal,proto3" json:"subtotal,omitempty"`
+	Tax           int32                  `protobuf:"varint,10,opt,name=tax,proto3" json:"tax,omitempty"`
+	Total         int32                  `protobuf:"varint,11,opt,name=total,proto3" json:"total,omitempty"`
+	Status        string                 `protobuf:"bytes,12,opt,name=status,proto3" json:"status,omitempty"`
+	IssuedAt      *timestamp.Timestamp   `protobuf:"bytes,13,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
+	DueAt         *timestamp.Timestamp   `protobuf:"bytes,14,opt,name=due_at,json=dueAt,proto3" json:"due_at,omitempty"`
+	SentAt        *timestamp.Timestamp   `protobuf:"bytes,15,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	VoidedAt      *timestamp.Timestamp   `protobuf:"bytes,16,opt,name=voided_at,json=voidedAt,proto3" json:"voided_at,omitempty"`
+	VoidReason    string                 `protobuf:"bytes,17,opt,name=void_reason,json=voidReason,proto3" json:"void_reason,omitempty"`
+	LineItems     []*LineItem            `protobuf:"bytes,18,rep,name=line_items,json=lineItems,proto3" json:"line_items,omitempty"`
+	Currency      string                 `protobuf:"bytes,19,opt,name=currency,proto3" json:"currency,omitempty"`
+	Discount      int32                  `protobuf:"varint,20,opt,name=discount,proto3" json:"discount,omitempty"`
+	Shipping      int32                  `protobuf:"varint,21,opt,name=shipping,proto3" json:"shipping,omitempty"`
+	TaxRateBps    int32                  `protobuf:"varint,22,opt,name=tax_rate_bps,json=taxRateBps,proto3" json:"tax_rate_bps,omitempty"`
+	BuyerCountry  string                 `protobuf:"bytes,23,opt,name=buyer_country,json=buyerCountry,proto3" json:"buyer_country,omitempty"`
+	Locale        string                 `protobuf:"bytes,24,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Invoice) Reset() {
+	*x = Invoice{}
+	mi := &file_invoice_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Invoice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Invoice) ProtoMessage() {}
+
+func (x *Invoice) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Invoice.ProtoReflect.Descriptor instead.
+func (*Invoice) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Invoice) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Invoice) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+func (x *Invoice) GetOrderId() int32 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *Invoice) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *Invoice) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *Invoice) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Invoice) GetProduct() string {
+	if x != nil {
+		return x.Product
+	}
+	return ""
+}
+
+func (x *Invoice) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *Invoice) GetSubtotal() int32 {
+	if x != nil {
+		return x.Subtotal
+	}
+	return 0
+}
+
+func (x *Invoice) GetTax() int32 {
+	if x != nil {
+		return x.Tax
+	}
+	return 0
+}
+
+func (x *Invoice) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Invoice) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Invoice) GetIssuedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.IssuedAt
+	}
+	return nil
+}
+
+func (x *Invoice) GetDueAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.DueAt
+	}
+	return nil
+}
+
+func (x *Invoice) GetSentAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+func (x *Invoice) GetVoidedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.VoidedAt
+	}
+	return nil
+}
+
+func (x *Invoice) GetVoidReason() string {
+	if x != nil {
+		return x.VoidReason
+	}
+	return ""
+}
+
+func (x *Invoice) GetLineItems() []*LineItem {
+	if x != nil {
+		return x.LineItems
+	}
+	return nil
+}
+
+func (x *Invoice) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Invoice) GetDiscount() int32 {
+	if x != nil {
+		return x.Discount
+	}
+	return 0
+}
+
+func (x *Invoice) GetShipping() int32 {
+	if x != nil {
+		return x.Shipping
+	}
+	return 0
+}
+
+func (x *Invoice) GetTaxRateBps() int32 {
+	if x != nil {
+		return x.TaxRateBps
+	}
+	return 0
+}
+
+func (x *Invoice) GetBuyerCountry() string {
+	if x != nil {
+		return x.BuyerCountry
+	}
+	return ""
+}
+
+func (x *Invoice) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type GetInvoiceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Number        string                 `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInvoiceRequest) Reset() {
+	*x = GetInvoiceRequest{}
+	mi := &file_invoice_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInvoiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInvoiceRequest) ProtoMessage() {}
+
+func (x *GetInvoiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInvoiceRequest.ProtoReflect.Descriptor instead.
+func (*GetInvoiceRequest) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetInvoiceRequest) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+// ListInvoicesRequest filters by email and/or order id; page numbers start at 1
+type ListInvoicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	OrderId       int32                  `protobuf:"varint,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Page          int32                  `protobuf:"varint,4,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInvoicesRequest) Reset() {
+	*x = ListInvoicesRequest{}
+	mi := &file_invoice_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInvoicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInvoicesRequest) ProtoMessage() {}
+
+func (x *ListInvoicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInvoicesRequest.ProtoReflect.Descriptor instead.
+func (*ListInvoicesRequest) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListInvoicesRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *ListInvoicesRequest) GetOrderId() int32 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *ListInvoicesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListInvoicesRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+type ListInvoicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Invoices      []*Invoice             `protobuf:"bytes,1,rep,name=invoices,proto3" json:"invoices,omitempty"`
+	CurrentPage   int32                  `protobuf:"varint,2,opt,name=current_page,json=currentPage,proto3" json:"current_page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	LastPage      int32                  `protobuf:"varint,4,opt,name=last_page,json=lastPage,proto3" json:"last_page,omitempty"`
+	TotalRecords  int32                  `protobuf:"varint,5,opt,name=total_records,json=totalRecords,proto3" json:"total_records,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInvoicesResponse) Reset() {
+	*x = ListInvoicesResponse{}
+	mi := &file_invoice_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInvoicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInvoicesResponse) ProtoMessage() {}
+
+func (x *ListInvoicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInvoicesResponse.ProtoReflect.Descriptor instead.
+func (*ListInvoicesResponse) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListInvoicesResponse) GetInvoices() []*Invoice {
+	if x != nil {
+		return x.Invoices
+	}
+	return nil
+}
+
+func (x *ListInvoicesResponse) GetCurrentPage() int32 {
+	if x != nil {
+		return x.CurrentPage
+	}
+	return 0
+}
+
+func (x *ListInvoicesResponse) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListInvoicesResponse) GetLastPage() int32 {
+	if x != nil {
+		return x.LastPage
+	}
+	return 0
+}
+
+func (x *ListInvoicesResponse) GetTotalRecords() int32 {
+	if x != nil {
+		return x.TotalRecords
+	}
+	return 0
+}
+
+// ResendInvoiceRequest sends the invoice to email, or to the customer's address when it is empty
+type ResendInvoiceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Number        string                 `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResendInvoiceRequest) Reset() {
+	*x = ResendInvoiceRequest{}
+	mi := &file_invoice_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResendInvoiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResendInvoiceRequest) ProtoMessage() {}
+
+func (x *ResendInvoiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResendInvoiceRequest.ProtoReflect.Descriptor instead.
+func (*ResendInvoiceRequest) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ResendInvoiceRequest) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+func (x *ResendInvoiceRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type ResendInvoiceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResendInvoiceResponse) Reset() {
+	*x = ResendInvoiceResponse{}
+	mi := &file_invoice_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResendInvoiceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResendInvoiceResponse) ProtoMessage() {}
+
+func (x *ResendInvoiceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResendInvoiceResponse.ProtoReflect.Descriptor instead.
+func (*ResendInvoiceResponse) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ResendInvoiceResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type VoidInvoiceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Number        string                 `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VoidInvoiceRequest) Reset() {
+	*x = VoidInvoiceRequest{}
+	mi := &file_invoice_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VoidInvoiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoidInvoiceRequest) ProtoMessage() {}
+
+func (x *VoidInvoiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoidInvoiceRequest.ProtoReflect.Descriptor instead.
+func (*VoidInvoiceRequest) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *VoidInvoiceRequest) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+func (x *VoidInvoiceRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// InvoiceXML is an invoice as a UBL 2.1 XML document
+type InvoiceXML struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InvoiceXML) Reset() {
+	*x = InvoiceXML{}
+	mi := &file_invoice_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvoiceXML) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvoiceXML) ProtoMessage() {}
+
+func (x *InvoiceXML) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvoiceXML.ProtoReflect.Descriptor instead.
+func (*InvoiceXML) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *InvoiceXML) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *InvoiceXML) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type DownloadInvoicePDFRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Number        string                 `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadInvoicePDFRequest) Reset() {
+	*x = DownloadInvoicePDFRequest{}
+	mi := &file_invoice_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadInvoicePDFRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadInvoicePDFRequest) ProtoMessage() {}
+
+func (x *DownloadInvoicePDFRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadInvoicePDFRequest.ProtoReflect.Descriptor instead.
+func (*DownloadInvoicePDFRequest) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DownloadInvoicePDFRequest) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+type InvoicePDFChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InvoicePDFChunk) Reset() {
+	*x = InvoicePDFChunk{}
+	mi := &file_invoice_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvoicePDFChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvoicePDFChunk) ProtoMessage() {}
+
+func (x *InvoicePDFChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvoicePDFChunk.ProtoReflect.Descriptor instead.
+func (*InvoicePDFChunk) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *InvoicePDFChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// CreateCreditNoteRequest credits amount against the invoice with invoice_number, or the invoice
+// of order_id when it is empty. reference identifies what is being credited, such as a Stripe
+// refund id; a request with a reference that was already used returns the existing credit note.
+type CreateCreditNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InvoiceNumber string                 `protobuf:"bytes,1,opt,name=invoice_number,json=invoiceNumber,proto3" json:"invoice_number,omitempty"`
+	OrderId       int32                  `protobuf:"varint,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Amount        int32                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Reference     string                 `protobuf:"bytes,5,opt,name=reference,proto3" json:"reference,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCreditNoteRequest) Reset() {
+	*x = CreateCreditNoteRequest{}
+	mi := &file_invoice_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCreditNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCreditNoteRequest) ProtoMessage() {}
+
+func (x *CreateCreditNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCreditNoteRequest.ProtoReflect.Descriptor instead.
+func (*CreateCreditNoteRequest) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CreateCreditNoteRequest) GetInvoiceNumber() string {
+	if x != nil {
+		return x.InvoiceNumber
+	}
+	return ""
+}
+
+func (x *CreateCreditNoteRequest) GetOrderId() int32 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *CreateCreditNoteRequest) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *CreateCreditNoteRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CreateCreditNoteRequest) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+type CreditNote struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Number        string                 `protobuf:"bytes,2,opt,name=number,proto3" json:"number,omitempty"`
+	InvoiceNumber string                 `protobuf:"bytes,3,opt,name=invoice_number,json=invoiceNumber,proto3" json:"invoice_number,omitempty"`
+	Amount        int32                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency      string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	Reason        string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	Reference     string                 `protobuf:"bytes,8,opt,name=reference,proto3" json:"reference,omitempty"`
+	IssuedAt      *timestamp.Timestamp   `protobuf:"bytes,9,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
+	SentAt        *timestamp.Timestamp   `protobuf:"bytes,10,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreditNote) Reset() {
+	*x = CreditNote{}
+	mi := &file_invoice_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreditNote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreditNote) ProtoMessage() {}
+
+func (x *CreditNote) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreditNote.ProtoReflect.Descriptor instead.
+func (*CreditNote) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreditNote) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *CreditNote) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+func (x *CreditNote) GetInvoiceNumber() string {
+	if x != nil {
+		return x.InvoiceNumber
+	}
+	return ""
+}
+
+func (x *CreditNote) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *CreditNote) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CreditNote) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CreditNote) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CreditNote) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *CreditNote) GetIssuedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.IssuedAt
+	}
+	return nil
+}
+
+func (x *CreditNote) GetSentAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+// EmailTemplate is an email the service sends; locales have their own variant of it
+type EmailTemplate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Locales       []string               `protobuf:"bytes,2,rep,name=locales,proto3" json:"locales,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmailTemplate) Reset() {
+	*x = EmailTemplate{}
+	mi := &file_invoice_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmailTemplate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmailTemplate) ProtoMessage() {}
+
+func (x *EmailTemplate) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmailTemplate.ProtoReflect.Descriptor instead.
+func (*EmailTemplate) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *EmailTemplate) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EmailTemplate) GetLocales() []string {
+	if x != nil {
+		return x.Locales
+	}
+	return nil
+}
+
+type ListEmailTemplatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEmailTemplatesRequest) Reset() {
+	*x = ListEmailTemplatesRequest{}
+	mi := &file_invoice_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEmailTemplatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEmailTemplatesRequest) ProtoMessage() {}
+
+func (x *ListEmailTemplatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEmailTemplatesRequest.ProtoReflect.Descriptor instead.
+func (*ListEmailTemplatesRequest) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{16}
+}
+
+type ListEmailTemplatesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Templates     []*EmailTemplate       `protobuf:"bytes,1,rep,name=templates,proto3" json:"templates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEmailTemplatesResponse) Reset() {
+	*x = ListEmailTemplatesResponse{}
+	mi := &file_invoice_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEmailTemplatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEmailTemplatesResponse) ProtoMessage() {}
+
+func (x *ListEmailTemplatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEmailTemplatesResponse.ProtoReflect.Descriptor instead.
+func (*ListEmailTemplatesResponse) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListEmailTemplatesResponse) GetTemplates() []*EmailTemplate {
+	if x != nil {
+		return x.Templates
+	}
+	return nil
+}
+
+// PreviewEmailTemplateRequest renders the email template name with sample data, in locale
+type PreviewEmailTemplateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Locale        string                 `protobuf:"bytes,2,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreviewEmailTemplateRequest) Reset() {
+	*x = PreviewEmailTemplateRequest{}
+	mi := &file_invoice_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreviewEmailTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreviewEmailTemplateRequest) ProtoMessage() {}
+
+func (x *PreviewEmailTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreviewEmailTemplateRequest.ProtoReflect.Descriptor instead.
+func (*PreviewEmailTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PreviewEmailTemplateRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PreviewEmailTemplateRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type EmailPreview struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Locale        string                 `protobuf:"bytes,2,opt,name=locale,proto3" json:"locale,omitempty"`
+	Subject       string                 `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"`
+	Html          string                 `protobuf:"bytes,4,opt,name=html,proto3" json:"html,omitempty"`
+	Plain         string                 `protobuf:"bytes,5,opt,name=plain,proto3" json:"plain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmailPreview) Reset() {
+	*x = EmailPreview{}
+	mi := &file_invoice_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmailPreview) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmailPreview) ProtoMessage() {}
+
+func (x *EmailPreview) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmailPreview.ProtoReflect.Descriptor instead.
+func (*EmailPreview) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *EmailPreview) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EmailPreview) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *EmailPreview) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *EmailPreview) GetHtml() string {
+	if x != nil {
+		return x.Html
+	}
+	return ""
+}
+
+func (x *EmailPreview) GetPlain() string {
+	if x != nil {
+		return x.Plain
+	}
+	return ""
+}
+
+// SendTestEmailRequest sends the email template name, rendered with sample data, to email
+type SendTestEmailRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Locale        string                 `protobuf:"bytes,2,opt,name=locale,proto3" json:"locale,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendTestEmailRequest) Reset() {
+	*x = SendTestEmailRequest{}
+	mi := &file_invoice_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendTestEmailRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendTestEmailRequest) ProtoMessage() {}
+
+func (x *SendTestEmailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendTestEmailRequest.ProtoReflect.Descriptor instead.
+func (*SendTestEmailRequest) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SendTestEmailRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SendTestEmailRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *SendTestEmailRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type SendTestEmailResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendTestEmailResponse) Reset() {
+	*x = SendTestEmailResponse{}
+	mi := &file_invoice_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendTestEmailResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendTestEmailResponse) ProtoMessage() {}
+
+func (x *SendTestEmailResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendTestEmailResponse.ProtoReflect.Descriptor instead.
+func (*SendTestEmailResponse) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SendTestEmailResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// EmailTemplateRequest names an email template; an empty locale is the default template, used
+// by languages without their own
+type EmailTemplateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Locale        string                 `protobuf:"bytes,2,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmailTemplateRequest) Reset() {
+	*x = EmailTemplateRequest{}
+	mi := &file_invoice_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmailTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmailTemplateRequest) ProtoMessage() {}
+
+func (x *EmailTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmailTemplateRequest.ProtoReflect.Descriptor instead.
+func (*EmailTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *EmailTemplateRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EmailTemplateRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+// EmailTemplateSource is the text of an email template in use. locale is where it was found,
+// and version is 0 for the template compiled into the service.
+type EmailTemplateSource struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Locale        string                 `protobuf:"bytes,2,opt,name=locale,proto3" json:"locale,omitempty"`
+	Version       int32                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Html          string                 `protobuf:"bytes,4,opt,name=html,proto3" json:"html,omitempty"`
+	Plain         string                 `protobuf:"bytes,5,opt,name=plain,proto3" json:"plain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmailTemplateSource) Reset() {
+	*x = EmailTemplateSource{}
+	mi := &file_invoice_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmailTemplateSource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmailTemplateSource) ProtoMessage() {}
+
+func (x *EmailTemplateSource) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmailTemplateSource.ProtoReflect.Descriptor instead.
+func (*EmailTemplateSource) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *EmailTemplateSource) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EmailTemplateSource) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *EmailTemplateSource) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *EmailTemplateSource) GetHtml() string {
+	if x != nil {
+		return x.Html
+	}
+	return ""
+}
+
+func (x *EmailTemplateSource) GetPlain() string {
+	if x != nil {
+		return x.Plain
+	}
+	return ""
+}
+
+// EmailTemplateVersion is one saved edit of an email template; uses_default marks a version
+// that put the compiled template back in use
+type EmailTemplateVersion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Locale        string                 `protobuf:"bytes,3,opt,name=locale,proto3" json:"locale,omitempty"`
+	Version       int32                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	Html          string                 `protobuf:"bytes,5,opt,name=html,proto3" json:"html,omitempty"`
+	Plain         string                 `protobuf:"bytes,6,opt,name=plain,proto3" json:"plain,omitempty"`
+	UsesDefault   bool                   `protobuf:"varint,7,opt,name=uses_default,json=usesDefault,proto3" json:"uses_default,omitempty"`
+	CreatedBy     string                 `protobuf:"bytes,8,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	CreatedAt     *timestamp.Timestamp   `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmailTemplateVersion) Reset() {
+	*x = EmailTemplateVersion{}
+	mi := &file_invoice_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmailTemplateVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmailTemplateVersion) ProtoMessage() {}
+
+func (x *EmailTemplateVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmailTemplateVersion.ProtoReflect.Descriptor instead.
+func (*EmailTemplateVersion) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *EmailTemplateVersion) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *EmailTemplateVersion) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EmailTemplateVersion) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *EmailTemplateVersion) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *EmailTemplateVersion) GetHtml() string {
+	if x != nil {
+		return x.Html
+	}
+	return ""
+}
+
+func (x *EmailTemplateVersion) GetPlain() string {
+	if x != nil {
+		return x.Plain
+	}
+	return ""
+}
+
+func (x *EmailTemplateVersion) GetUsesDefault() bool {
+	if x != nil {
+		return x.UsesDefault
+	}
+	return false
+}
+
+func (x *EmailTemplateVersion) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *EmailTemplateVersion) GetCreatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListEmailTemplateVersionsResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Versions      []*EmailTemplateVersion `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEmailTemplateVersionsResponse) Reset() {
+	*x = ListEmailTemplateVersionsResponse{}
+	mi := &file_invoice_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEmailTemplateVersionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEmailTemplateVersionsResponse) ProtoMessage() {}
+
+func (x *ListEmailTemplateVersionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEmailTemplateVersionsResponse.ProtoReflect.Descriptor instead.
+func (*ListEmailTemplateVersionsResponse) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListEmailTemplateVersionsResponse) GetVersions() []*EmailTemplateVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+type SaveEmailTemplateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Locale        string                 `protobuf:"bytes,2,opt,name=locale,proto3" json:"locale,omitempty"`
+	Html          string                 `protobuf:"bytes,3,opt,name=html,proto3" json:"html,omitempty"`
+	Plain         string                 `protobuf:"bytes,4,opt,name=plain,proto3" json:"plain,omitempty"`
+	Author        string                 `protobuf:"bytes,5,opt,name=author,proto3" json:"author,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveEmailTemplateRequest) Reset() {
+	*x = SaveEmailTemplateRequest{}
+	mi := &file_invoice_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveEmailTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveEmailTemplateRequest) ProtoMessage() {}
+
+func (x *SaveEmailTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveEmailTemplateRequest.ProtoReflect.Descriptor instead.
+func (*SaveEmailTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SaveEmailTemplateRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SaveEmailTemplateRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *SaveEmailTemplateRequest) GetHtml() string {
+	if x != nil {
+		return x.Html
+	}
+	return ""
+}
+
+func (x *SaveEmailTemplateRequest) GetPlain() string {
+	if x != nil {
+		return x.Plain
+	}
+	return ""
+}
+
+func (x *SaveEmailTemplateRequest) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+// RestoreEmailTemplateRequest puts version back in use; version 0 is the compiled template
+type RestoreEmailTemplateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Locale        string                 `protobuf:"bytes,2,opt,name=locale,proto3" json:"locale,omitempty"`
+	Version       int32                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Author        string                 `protobuf:"bytes,4,opt,name=author,proto3" json:"author,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreEmailTemplateRequest) Reset() {
+	*x = RestoreEmailTemplateRequest{}
+	mi := &file_invoice_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreEmailTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreEmailTemplateRequest) ProtoMessage() {}
+
+func (x *RestoreEmailTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreEmailTemplateRequest.ProtoReflect.Descriptor instead.
+func (*RestoreEmailTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RestoreEmailTemplateRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RestoreEmailTemplateRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *RestoreEmailTemplateRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *RestoreEmailTemplateRequest) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+var File_invoice_proto protoreflect.FileDescriptor
+
+const file_invoice_proto_rawDesc = "" +
+	"\n" +
+	"\rinvoice.proto\x12\ainvoice\x1a\x1fgoogle/protobuf/timestamp.proto\"\x7f\n" +
+	"\bLineItem\x12 \n" +
+	"\vdescription\x18\x01 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12\x1d\n" +
+	"\n" +
+	"unit_price\x18\x03 \x01(\x05R\tunitPrice\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x05R\x06amount\"\xe2\x04\n" +
+	"\x14CreateInvoiceRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x17\n" +
+	"\aitem_id\x18\x02 \x01(\x05R\x06itemId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x05R\x06amount\x12\x18\n" +
+	"\aproduct\x18\x04 \x01(\tR\aproduct\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\x05R\bquantity\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x06 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\a \x01(\tR\blastName\x12\x14\n" +
+	"\x05email\x18\b \x01(\tR\x05email\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x120\n" +
+	"\n" +
+	"line_items\x18\n" +
+	" \x03(\v2\x11.invoice.LineItemR\tlineItems\x12\x1a\n" +
+	"\bcurrency\x18\v \x01(\tR\bcurrency\x12\x1a\n" +
+	"\bdiscount\x18\f \x01(\x05R\bdiscount\x12%\n" +
+	"\x0ediscount_label\x18\r \x01(\tR\rdiscountLabel\x12\x1a\n" +
+	"\bshipping\x18\x0e \x01(\x05R\bshipping\x12 \n" +
+	"\ftax_rate_bps\x18\x0f \x01(\x05R\n" +
+	"taxRateBps\x12\x1d\n" +
+	"\n" +
+	"card_brand\x18\x10 \x01(\tR\tcardBrand\x12\x1b\n" +
+	"\tlast_four\x18\x11 \x01(\tR\blastFour\x12#\n" +
+	"\rbuyer_country\x18\x12 \x01(\tR\fbuyerCountry\x12\x16\n" +
+	"\x06locale\x18\x13 \x01(\tR\x06locale\"X\n" +
+	"\x15CreateInvoiceResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12%\n" +
+	"\x0einvoice_number\x18\x02 \x01(\tR\rinvoiceNumber\"\x90\x06\n" +
+	"\aInvoice\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x16\n" +
+	"\x06number\x18\x02 \x01(\tR\x06number\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\x05R\aorderId\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x04 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x05 \x01(\tR\blastName\x12\x14\n" +
+	"\x05email\x18\x06 \x01(\tR\x05email\x12\x18\n" +
+	"\aproduct\x18\a \x01(\tR\aproduct\x12\x1a\n" +
+	"\bquantity\x18\b \x01(\x05R\bquantity\x12\x1a\n" +
+	"\bsubtotal\x18\t \x01(\x05R\bsubtotal\x12\x10\n" +
+	"\x03tax\x18\n" +
+	" \x01(\x05R\x03tax\x12\x14\n" +
+	"\x05total\x18\v \x01(\x05R\x05total\x12\x16\n" +
+	"\x06status\x18\f \x01(\tR\x06status\x127\n" +
+	"\tissued_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\bissuedAt\x121\n" +
+	"\x06due_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\x05dueAt\x123\n" +
+	"\asent_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\x127\n" +
+	"\tvoided_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\bvoidedAt\x12\x1f\n" +
+	"\vvoid_reason\x18\x11 \x01(\tR\n" +
+	"voidReason\x120\n" +
+	"\n" +
+	"line_items\x18\x12 \x03(\v2\x11.invoice.LineItemR\tlineItems\x12\x1a\n" +
+	"\bcurrency\x18\x13 \x01(\tR\bcurrency\x12\x1a\n" +
+	"\bdiscount\x18\x14 \x01(\x05R\bdiscount\x12\x1a\n" +
+	"\bshipping\x18\x15 \x01(\x05R\bshipping\x12 \n" +
+	"\ftax_rate_bps\x18\x16 \x01(\x05R\n" +
+	"taxRateBps\x12#\n" +
+	"\rbuyer_country\x18\x17 \x01(\tR\fbuyerCountry\x12\x16\n" +
+	"\x06locale\x18\x18 \x01(\tR\x06locale\"+\n" +
+	"\x11GetInvoiceRequest\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\tR\x06number\"w\n" +
+	"\x13ListInvoicesRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\x05R\aorderId\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x12\n" +
+	"\x04page\x18\x04 \x01(\x05R\x04page\"\xc6\x01\n" +
+	"\x14ListInvoicesResponse\x12,\n" +
+	"\binvoices\x18\x01 \x03(\v2\x10.invoice.InvoiceR\binvoices\x12!\n" +
+	"\fcurrent_page\x18\x02 \x01(\x05R\vcurrentPage\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1b\n" +
+	"\tlast_page\x18\x04 \x01(\x05R\blastPage\x12#\n" +
+	"\rtotal_records\x18\x05 \x01(\x05R\ftotalRecords\"D\n" +
+	"\x14ResendInvoiceRequest\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\tR\x06number\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\"1\n" +
+	"\x15ResendInvoiceResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"D\n" +
+	"\x12VoidInvoiceRequest\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\tR\x06number\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"<\n" +
+	"\n" +
+	"InvoiceXML\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\"3\n" +
+	"\x19DownloadInvoicePDFRequest\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\tR\x06number\"%\n" +
+	"\x0fInvoicePDFChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"\xa9\x01\n" +
+	"\x17CreateCreditNoteRequest\x12%\n" +
+	"\x0einvoice_number\x18\x01 \x01(\tR\rinvoiceNumber\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\x05R\aorderId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x05R\x06amount\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x1c\n" +
+	"\treference\x18\x05 \x01(\tR\treference\"\xcb\x02\n" +
+	"\n" +
+	"CreditNote\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x16\n" +
+	"\x06number\x18\x02 \x01(\tR\x06number\x12%\n" +
+	"\x0einvoice_number\x18\x03 \x01(\tR\rinvoiceNumber\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x05R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x1c\n" +
+	"\treference\x18\b \x01(\tR\treference\x127\n" +
+	"\tissued_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\bissuedAt\x123\n" +
+	"\asent_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\"=\n" +
+	"\rEmailTemplate\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\alocales\x18\x02 \x03(\tR\alocales\"\x1b\n" +
+	"\x19ListEmailTemplatesRequest\"R\n" +
+	"\x1aListEmailTemplatesResponse\x124\n" +
+	"\ttemplates\x18\x01 \x03(\v2\x16.invoice.EmailTemplateR\ttemplates\"I\n" +
+	"\x1bPreviewEmailTemplateRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06locale\x18\x02 \x01(\tR\x06locale\"~\n" +
+	"\fEmailPreview\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06locale\x18\x02 \x01(\tR\x06locale\x12\x18\n" +
+	"\asubject\x18\x03 \x01(\tR\asubject\x12\x12\n" +
+	"\x04html\x18\x04 \x01(\tR\x04html\x12\x14\n" +
+	"\x05plain\x18\x05 \x01(\tR\x05plain\"X\n" +
+	"\x14SendTestEmailRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06locale\x18\x02 \x01(\tR\x06locale\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\"1\n" +
+	"\x15SendTestEmailResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"B\n" +
+	"\x14EmailTemplateRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06locale\x18\x02 \x01(\tR\x06locale\"\x85\x01\n" +
+	"\x13EmailTemplateSource\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06locale\x18\x02 \x01(\tR\x06locale\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x05R\aversion\x12\x12\n" +
+	"\x04html\x18\x04 \x01(\tR\x04html\x12\x14\n" +
+	"\x05plain\x18\x05 \x01(\tR\x05plain\"\x93\x02\n" +
+	"\x14EmailTemplateVersion\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06locale\x18\x03 \x01(\tR\x06locale\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x05R\aversion\x12\x12\n" +
+	"\x04html\x18\x05 \x01(\tR\x04html\x12\x14\n" +
+	"\x05plain\x18\x06 \x01(\tR\x05plain\x12!\n" +
+	"\fuses_default\x18\a \x01(\bR\vusesDefault\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\b \x01(\tR\tcreatedBy\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"^\n" +
+	"!ListEmailTemplateVersionsResponse\x129\n" +
+	"\bversions\x18\x01 \x03(\v2\x1d.invoice.EmailTemplateVersionR\bversions\"\x88\x01\n" +
+	"\x18SaveEmailTemplateRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06locale\x18\x02 \x01(\tR\x06locale\x12\x12\n" +
+	"\x04html\x18\x03 \x01(\tR\x04html\x12\x14\n" +
+	"\x05plain\x18\x04 \x01(\tR\x05plain\x12\x16\n" +
+	"\x06author\x18\x05 \x01(\tR\x06author\"{\n" +
+	"\x1bRestoreEmailTemplateRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06locale\x18\x02 \x01(\tR\x06locale\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x05R\aversion\x12\x16\n" +
+	"\x06author\x18\x04 \x01(\tR\x06author2\xd8\t\n" +
+	"\x0eInvoiceService\x12U\n" +
+	"\x14CreateAndSendInvoice\x12\x1d.invoice.CreateInvoiceRequest\x1a\x1e.invoice.CreateInvoiceResponse\x12:\n" +
+	"\n" +
+	"GetInvoice\x12\x1a.invoice.GetInvoiceRequest\x1a\x10.invoice.Invoice\x12K\n" +
+	"\fListInvoices\x12\x1c.invoice.ListInvoicesRequest\x1a\x1d.invoice.ListInvoicesResponse\x12N\n" +
+	"\rResendInvoice\x12\x1d.invoice.ResendInvoiceRequest\x1a\x1e.invoice.ResendInvoiceResponse\x12<\n" +
+	"\vVoidInvoice\x12\x1b.invoice.VoidInvoiceRequest\x1a\x10.invoice.Invoice\x12T\n" +
+	"\x12DownloadInvoicePDF\x12\".invoice.DownloadInvoicePDFRequest\x1a\x18.invoice.InvoicePDFChunk0\x01\x12I\n" +
+	"\x10CreateCreditNote\x12 .invoice.CreateCreditNoteRequest\x1a\x13.invoice.CreditNote\x12@\n" +
+	"\rGetInvoiceXML\x12\x1a.invoice.GetInvoiceRequest\x1a\x13.invoice.InvoiceXML\x12]\n" +
+	"\x12ListEmailTemplates\x12\".invoice.ListEmailTemplatesRequest\x1a#.invoice.ListEmailTemplatesResponse\x12S\n" +
+	"\x14PreviewEmailTemplate\x12$.invoice.PreviewEmailTemplateRequest\x1a\x15.invoice.EmailPreview\x12N\n" +
+	"\rSendTestEmail\x12\x1d.invoice.SendTestEmailRequest\x1a\x1e.invoice.SendTestEmailResponse\x12U\n" +
+	"\x16GetEmailTemplateSource\x12\x1d.invoice.EmailTemplateRequest\x1a\x1c.invoice.EmailTemplateSource\x12f\n" +
+	"\x19ListEmailTemplateVersions\x12\x1d.invoice.EmailTemplateRequest\x1a*.invoice.ListEmailTemplateVersionsResponse\x12U\n" +
+	"\x11SaveEmailTemplate\x12!.invoice.SaveEmailTemplateRequest\x1a\x1d.invoice.EmailTemplateVersion\x12[\n" +
+	"\x14RestoreEmailTemplate\x12$.invoice.RestoreEmailTemplateRequest\x1a\x1d.invoice.EmailTemplateVersionB:Z8github.com/LamThanhNguyen/yoyo-store-backend/internal/pbb\x06proto3"
 
 var (
 	file_invoice_proto_rawDescOnce sync.Once
@@ -208,21 +2239,87 @@ func file_invoice_proto_rawDescGZIP() []byte {
 	return file_invoice_proto_rawDescData
 }
 
-var file_invoice_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_invoice_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
 var file_invoice_proto_goTypes = []any{
-	(*CreateInvoiceRequest)(nil),  // 0: invoice.CreateInvoiceRequest
-	(*CreateInvoiceResponse)(nil), // 1: invoice.CreateInvoiceResponse
-	(*timestamp.Timestamp)(nil),   // 2: google.protobuf.Timestamp
+	(*LineItem)(nil),                          // 0: invoice.LineItem
+	(*CreateInvoiceRequest)(nil),              // 1: invoice.CreateInvoiceRequest
+	(*CreateInvoiceResponse)(nil),             // 2: invoice.CreateInvoiceResponse
+	(*Invoice)(nil),                           // 3: invoice.Invoice
+	(*GetInvoiceRequest)(nil),                 // 4: invoice.GetInvoiceRequest
+	(*ListInvoicesRequest)(nil),               // 5: invoice.ListInvoicesRequest
+	(*ListInvoicesResponse)(nil),              // 6: invoice.ListInvoicesResponse
+	(*ResendInvoiceRequest)(nil),              // 7: invoice.ResendInvoiceRequest
+	(*ResendInvoiceResponse)(nil),             // 8: invoice.ResendInvoiceResponse
+	(*VoidInvoiceRequest)(nil),                // 9: invoice.VoidInvoiceRequest
+	(*InvoiceXML)(nil),                        // 10: invoice.InvoiceXML
+	(*DownloadInvoicePDFRequest)(nil),         // 11: invoice.DownloadInvoicePDFRequest
+	(*InvoicePDFChunk)(nil),                   // 12: invoice.InvoicePDFChunk
+	(*CreateCreditNoteRequest)(nil),           // 13: invoice.CreateCreditNoteRequest
+	(*CreditNote)(nil),                        // 14: invoice.CreditNote
+	(*EmailTemplate)(nil),                     // 15: invoice.EmailTemplate
+	(*ListEmailTemplatesRequest)(nil),         // 16: invoice.ListEmailTemplatesRequest
+	(*ListEmailTemplatesResponse)(nil),        // 17: invoice.ListEmailTemplatesResponse
+	(*PreviewEmailTemplateRequest)(nil),       // 18: invoice.PreviewEmailTemplateRequest
+	(*EmailPreview)(nil),                      // 19: invoice.EmailPreview
+	(*SendTestEmailRequest)(nil),              // 20: invoice.SendTestEmailRequest
+	(*SendTestEmailResponse)(nil),             // 21: invoice.SendTestEmailResponse
+	(*EmailTemplateRequest)(nil),              // 22: invoice.EmailTemplateRequest
+	(*EmailTemplateSource)(nil),               // 23: invoice.EmailTemplateSource
+	(*EmailTemplateVersion)(nil),              // 24: invoice.EmailTemplateVersion
+	(*ListEmailTemplateVersionsResponse)(nil), // 25: invoice.ListEmailTemplateVersionsResponse
+	(*SaveEmailTemplateRequest)(nil),          // 26: invoice.SaveEmailTemplateRequest
+	(*RestoreEmailTemplateRequest)(nil),       // 27: invoice.RestoreEmailTemplateRequest
+	(*timestamp.Timestamp)(nil),               // 28: google.protobuf.Timestamp
 }
 var file_invoice_proto_depIdxs = []int32{
-	2, // 0: invoice.CreateInvoiceRequest.created_at:type_name -> google.protobuf.Timestamp
-	0, // 1: invoice.InvoiceService.CreateAndSendInvoice:input_type -> invoice.CreateInvoiceRequest
-	1, // 2: invoice.InvoiceService.CreateAndSendInvoice:output_type -> invoice.CreateInvoiceResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	28, // 0: invoice.CreateInvoiceRequest.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 1: invoice.CreateInvoiceRequest.line_items:type_name -> invoice.LineItem
+	28, // 2: invoice.Invoice.issued_at:type_name -> google.protobuf.Timestamp
+	28, // 3: invoice.Invoice.due_at:type_name -> google.protobuf.Timestamp
+	28, // 4: invoice.Invoice.sent_at:type_name -> google.protobuf.Timestamp
+	28, // 5: invoice.Invoice.voided_at:type_name -> google.protobuf.Timestamp
+	0,  // 6: invoice.Invoice.line_items:type_name -> invoice.LineItem
+	3,  // 7: invoice.ListInvoicesResponse.invoices:type_name -> invoice.Invoice
+	28, // 8: invoice.CreditNote.issued_at:type_name -> google.protobuf.Timestamp
+	28, // 9: invoice.CreditNote.sent_at:type_name -> google.protobuf.Timestamp
+	15, // 10: invoice.ListEmailTemplatesResponse.templates:type_name -> invoice.EmailTemplate
+	28, // 11: invoice.EmailTemplateVersion.created_at:type_name -> google.protobuf.Timestamp
+	24, // 12: invoice.ListEmailTemplateVersionsResponse.versions:type_name -> invoice.EmailTemplateVersion
+	1,  // 13: invoice.InvoiceService.CreateAndSendInvoice:input_type -> invoice.CreateInvoiceRequest
+	4,  // 14: invoice.InvoiceService.GetInvoice:input_type -> invoice.GetInvoiceRequest
+	5,  // 15: invoice.InvoiceService.ListInvoices:input_type -> invoice.ListInvoicesRequest
+	7,  // 16: invoice.InvoiceService.ResendInvoice:input_type -> invoice.ResendInvoiceRequest
+	9,  // 17: invoice.InvoiceService.VoidInvoice:input_type -> invoice.VoidInvoiceRequest
+	11, // 18: invoice.InvoiceService.DownloadInvoicePDF:input_type -> invoice.DownloadInvoicePDFRequest
+	13, // 19: invoice.InvoiceService.CreateCreditNote:input_type -> invoice.CreateCreditNoteRequest
+	4,  // 20: invoice.InvoiceService.GetInvoiceXML:input_type -> invoice.GetInvoiceRequest
+	16, // 21: invoice.InvoiceService.ListEmailTemplates:input_type -> invoice.ListEmailTemplatesRequest
+	18, // 22: invoice.InvoiceService.PreviewEmailTemplate:input_type -> invoice.PreviewEmailTemplateRequest
+	20, // 23: invoice.InvoiceService.SendTestEmail:input_type -> invoice.SendTestEmailRequest
+	22, // 24: invoice.InvoiceService.GetEmailTemplateSource:input_type -> invoice.EmailTemplateRequest
+	22, // 25: invoice.InvoiceService.ListEmailTemplateVersions:input_type -> invoice.EmailTemplateRequest
+	26, // 26: invoice.InvoiceService.SaveEmailTemplate:input_type -> invoice.SaveEmailTemplateRequest
+	27, // 27: invoice.InvoiceService.RestoreEmailTemplate:input_type -> invoice.RestoreEmailTemplateRequest
+	2,  // 28: invoice.InvoiceService.CreateAndSendInvoice:output_type -> invoice.CreateInvoiceResponse
+	3,  // 29: invoice.InvoiceService.GetInvoice:output_type -> invoice.Invoice
+	6,  // 30: invoice.InvoiceService.ListInvoices:output_type -> invoice.ListInvoicesResponse
+	8,  // 31: invoice.InvoiceService.ResendInvoice:output_type -> invoice.ResendInvoiceResponse
+	3,  // 32: invoice.InvoiceService.VoidInvoice:output_type -> invoice.Invoice
+	12, // 33: invoice.InvoiceService.DownloadInvoicePDF:output_type -> invoice.InvoicePDFChunk
+	14, // 34: invoice.InvoiceService.CreateCreditNote:output_type -> invoice.CreditNote
+	10, // 35: invoice.InvoiceService.GetInvoiceXML:output_type -> invoice.InvoiceXML
+	17, // 36: invoice.InvoiceService.ListEmailTemplates:output_type -> invoice.ListEmailTemplatesResponse
+	19, // 37: invoice.InvoiceService.PreviewEmailTemplate:output_type -> invoice.EmailPreview
+	21, // 38: invoice.InvoiceService.SendTestEmail:output_type -> invoice.SendTestEmailResponse
+	23, // 39: invoice.InvoiceService.GetEmailTemplateSource:output_type -> invoice.EmailTemplateSource
+	25, // 40: invoice.InvoiceService.ListEmailTemplateVersions:output_type -> invoice.ListEmailTemplateVersionsResponse
+	24, // 41: invoice.InvoiceService.SaveEmailTemplate:output_type -> invoice.EmailTemplateVersion
+	24, // 42: invoice.InvoiceService.RestoreEmailTemplate:output_type -> invoice.EmailTemplateVersion
+	28, // [28:43] is the sub-list for method output_type
+	13, // [13:28] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_invoice_proto_init() }
@@ -236,7 +2333,7 @@ func file_invoice_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_invoice_proto_rawDesc), len(file_invoice_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   28,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,7 +19,21 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	InvoiceService_CreateAndSendInvoice_FullMethodName = "/invoice.InvoiceService/CreateAndSendInvoice"
+	InvoiceService_CreateAndSendInvoice_FullMethodName      = "/invoice.InvoiceService/CreateAndSendInvoice"
+	InvoiceService_GetInvoice_FullMethodName                = "/invoice.InvoiceService/GetInvoice"
+	InvoiceService_ListInvoices_FullMethodName              = "/invoice.InvoiceService/ListInvoices"
+	InvoiceService_ResendInvoice_FullMethodName             = "/invoice.InvoiceService/ResendInvoice"
+	InvoiceService_VoidInvoice_FullMethodName               = "/invoice.InvoiceService/VoidInvoice"
+	InvoiceService_DownloadInvoicePDF_FullMethodName        = "/invoice.InvoiceService/DownloadInvoicePDF"
+	InvoiceService_CreateCreditNote_FullMethodName          = "/invoice.InvoiceService/CreateCreditNote"
+	InvoiceService_GetInvoiceXML_FullMethodName             = "/invoice.InvoiceService/GetInvoiceXML"
+	InvoiceService_ListEmailTemplates_FullMethodName        = "/invoice.InvoiceService/ListEmailTemplates"
+	InvoiceService_PreviewEmailTemplate_FullMethodName      = "/invoice.InvoiceService/PreviewEmailTemplate"
+	InvoiceService_SendTestEmail_FullMethodName             = "/invoice.InvoiceService/SendTestEmail"
+	InvoiceService_GetEmailTemplateSource_FullMethodName    = "/invoice.InvoiceService/GetEmailTemplateSource"
+	InvoiceService_ListEmailTemplateVersions_FullMethodName = "/invoice.InvoiceService/ListEmailTemplateVersions"
+	InvoiceService_SaveEmailTemplate_FullMethodName         = "/invoice.InvoiceService/SaveEmailTemplate"
+	InvoiceService_RestoreEmailTemplate_FullMethodName      = "/invoice.InvoiceService/RestoreEmailTemplate"
 )
 
 // InvoiceServiceClient is the client API for InvoiceService service.
@@ -27,6 +41,20 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type InvoiceServiceClient interface {
 	CreateAndSendInvoice(ctx context.Context, in *CreateInvoiceRequest, opts ...grpc.CallOption) (*CreateInvoiceResponse, error)
+	GetInvoice(ctx context.Context, in *GetInvoiceRequest, opts ...grpc.CallOption) (*Invoice, error)
+	ListInvoices(ctx context.Context, in *ListInvoicesRequest, opts ...grpc.CallOption) (*ListInvoicesResponse, error)
+	ResendInvoice(ctx context.Context, in *ResendInvoiceRequest, opts ...grpc.CallOption) (*ResendInvoiceResponse, error)
+	VoidInvoice(ctx context.Context, in *VoidInvoiceRequest, opts ...grpc.CallOption) (*Invoice, error)
+	DownloadInvoicePDF(ctx context.Context, in *DownloadInvoicePDFRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InvoicePDFChunk], error)
+	CreateCreditNote(ctx context.Context, in *CreateCreditNoteRequest, opts ...grpc.CallOption) (*CreditNote, error)
+	GetInvoiceXML(ctx context.Context, in *GetInvoiceRequest, opts ...grpc.CallOption) (*InvoiceXML, error)
+	ListEmailTemplates(ctx context.Context, in *ListEmailTemplatesRequest, opts ...grpc.CallOption) (*ListEmailTemplatesResponse, error)
+	PreviewEmailTemplate(ctx context.Context, in *PreviewEmailTemplateRequest, opts ...grpc.CallOption) (*EmailPreview, error)
+	SendTestEmail(ctx context.Context, in *SendTestEmailRequest, opts ...grpc.CallOption) (*SendTestEmailResponse, error)
+	GetEmailTemplateSource(ctx context.Context, in *EmailTemplateRequest, opts ...grpc.CallOption) (*EmailTemplateSource, error)
+	ListEmailTemplateVersions(ctx context.Context, in *EmailTemplateRequest, opts ...grpc.CallOption) (*ListEmailTemplateVersionsResponse, error)
+	SaveEmailTemplate(ctx context.Context, in *SaveEmailTemplateRequest, opts ...grpc.CallOption) (*EmailTemplateVersion, error)
+	RestoreEmailTemplate(ctx context.Context, in *RestoreEmailTemplateRequest, opts ...grpc.CallOption) (*EmailTemplateVersion, error)
 }
 
 type invoiceServiceClient struct {
@@ -47,11 +75,174 @@ func (c *invoiceServiceClient) CreateAndSendInvoice(ctx context.Context, in *Cre
 	return out, nil
 }
 
+func (c *invoiceServiceClient) GetInvoice(ctx context.Context, in *GetInvoiceRequest, opts ...grpc.CallOption) (*Invoice, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Invoice)
+	err := c.cc.Invoke(ctx, InvoiceService_GetInvoice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) ListInvoices(ctx context.Context, in *ListInvoicesRequest, opts ...grpc.CallOption) (*ListInvoicesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListInvoicesResponse)
+	err := c.cc.Invoke(ctx, InvoiceService_ListInvoices_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) ResendInvoice(ctx context.Context, in *ResendInvoiceRequest, opts ...grpc.CallOption) (*ResendInvoiceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResendInvoiceResponse)
+	err := c.cc.Invoke(ctx, InvoiceService_ResendInvoice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) VoidInvoice(ctx context.Context, in *VoidInvoiceRequest, opts ...grpc.CallOption) (*Invoice, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Invoice)
+	err := c.cc.Invoke(ctx, InvoiceService_VoidInvoice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) DownloadInvoicePDF(ctx context.Context, in *DownloadInvoicePDFRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InvoicePDFChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &InvoiceService_ServiceDesc.Streams[0], InvoiceService_DownloadInvoicePDF_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadInvoicePDFRequest, InvoicePDFChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type InvoiceService_DownloadInvoicePDFClient = grpc.ServerStreamingClient[InvoicePDFChunk]
+
+func (c *invoiceServiceClient) CreateCreditNote(ctx context.Context, in *CreateCreditNoteRequest, opts ...grpc.CallOption) (*CreditNote, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreditNote)
+	err := c.cc.Invoke(ctx, InvoiceService_CreateCreditNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) GetInvoiceXML(ctx context.Context, in *GetInvoiceRequest, opts ...grpc.CallOption) (*InvoiceXML, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InvoiceXML)
+	err := c.cc.Invoke(ctx, InvoiceService_GetInvoiceXML_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) ListEmailTemplates(ctx context.Context, in *ListEmailTemplatesRequest, opts ...grpc.CallOption) (*ListEmailTemplatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEmailTemplatesResponse)
+	err := c.cc.Invoke(ctx, InvoiceService_ListEmailTemplates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) PreviewEmailTemplate(ctx context.Context, in *PreviewEmailTemplateRequest, opts ...grpc.CallOption) (*EmailPreview, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmailPreview)
+	err := c.cc.Invoke(ctx, InvoiceService_PreviewEmailTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) SendTestEmail(ctx context.Context, in *SendTestEmailRequest, opts ...grpc.CallOption) (*SendTestEmailResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendTestEmailResponse)
+	err := c.cc.Invoke(ctx, InvoiceService_SendTestEmail_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) GetEmailTemplateSource(ctx context.Context, in *EmailTemplateRequest, opts ...grpc.CallOption) (*EmailTemplateSource, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmailTemplateSource)
+	err := c.cc.Invoke(ctx, InvoiceService_GetEmailTemplateSource_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) ListEmailTemplateVersions(ctx context.Context, in *EmailTemplateRequest, opts ...grpc.CallOption) (*ListEmailTemplateVersionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEmailTemplateVersionsResponse)
+	err := c.cc.Invoke(ctx, InvoiceService_ListEmailTemplateVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) SaveEmailTemplate(ctx context.Context, in *SaveEmailTemplateRequest, opts ...grpc.CallOption) (*EmailTemplateVersion, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmailTemplateVersion)
+	err := c.cc.Invoke(ctx, InvoiceService_SaveEmailTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) RestoreEmailTemplate(ctx context.Context, in *RestoreEmailTemplateRequest, opts ...grpc.CallOption) (*EmailTemplateVersion, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmailTemplateVersion)
+	err := c.cc.Invoke(ctx, InvoiceService_RestoreEmailTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InvoiceServiceServer is the server API for InvoiceService service.
 // All implementations must embed UnimplementedInvoiceServiceServer
 // for forward compatibility.
 type InvoiceServiceServer interface {
 	CreateAndSendInvoice(context.Context, *CreateInvoiceRequest) (*CreateInvoiceResponse, error)
+	GetInvoice(context.Context, *GetInvoiceRequest) (*Invoice, error)
+	ListInvoices(context.Context, *ListInvoicesRequest) (*ListInvoicesResponse, error)
+	ResendInvoice(context.Context, *ResendInvoiceRequest) (*ResendInvoiceResponse, error)
+	VoidInvoice(context.Context, *VoidInvoiceRequest) (*Invoice, error)
+	DownloadInvoicePDF(*DownloadInvoicePDFRequest, grpc.ServerStreamingServer[InvoicePDFChunk]) error
+	CreateCreditNote(context.Context, *CreateCreditNoteRequest) (*CreditNote, error)
+	GetInvoiceXML(context.Context, *GetInvoiceRequest) (*InvoiceXML, error)
+	ListEmailTemplates(context.Context, *ListEmailTemplatesRequest) (*ListEmailTemplatesResponse, error)
+	PreviewEmailTemplate(context.Context, *PreviewEmailTemplateRequest) (*EmailPreview, error)
+	SendTestEmail(context.Context, *SendTestEmailRequest) (*SendTestEmailResponse, error)
+	GetEmailTemplateSource(context.Context, *EmailTemplateRequest) (*EmailTemplateSource, error)
+	ListEmailTemplateVersions(context.Context, *EmailTemplateRequest) (*ListEmailTemplateVersionsResponse, error)
+	SaveEmailTemplate(context.Context, *SaveEmailTemplateRequest) (*EmailTemplateVersion, error)
+	RestoreEmailTemplate(context.Context, *RestoreEmailTemplateRequest) (*EmailTemplateVersion, error)
 	mustEmbedUnimplementedInvoiceServiceServer()
 }
 
@@ -65,6 +256,48 @@ type UnimplementedInvoiceServiceServer struct{}
 func (UnimplementedInvoiceServiceServer) CreateAndSendInvoice(context.Context, *CreateInvoiceRequest) (*CreateInvoiceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateAndSendInvoice not implemented")
 }
+func (UnimplementedInvoiceServiceServer) GetInvoice(context.Context, *GetInvoiceRequest) (*Invoice, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInvoice not implemented")
+}
+func (UnimplementedInvoiceServiceServer) ListInvoices(context.Context, *ListInvoicesRequest) (*ListInvoicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInvoices not implemented")
+}
+func (UnimplementedInvoiceServiceServer) ResendInvoice(context.Context, *ResendInvoiceRequest) (*ResendInvoiceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResendInvoice not implemented")
+}
+func (UnimplementedInvoiceServiceServer) VoidInvoice(context.Context, *VoidInvoiceRequest) (*Invoice, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VoidInvoice not implemented")
+}
+func (UnimplementedInvoiceServiceServer) DownloadInvoicePDF(*DownloadInvoicePDFRequest, grpc.ServerStreamingServer[InvoicePDFChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadInvoicePDF not implemented")
+}
+func (UnimplementedInvoiceServiceServer) CreateCreditNote(context.Context, *CreateCreditNoteRequest) (*CreditNote, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCreditNote not implemented")
+}
+func (UnimplementedInvoiceServiceServer) GetInvoiceXML(context.Context, *GetInvoiceRequest) (*InvoiceXML, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInvoiceXML not implemented")
+}
+func (UnimplementedInvoiceServiceServer) ListEmailTemplates(context.Context, *ListEmailTemplatesRequest) (*ListEmailTemplatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEmailTemplates not implemented")
+}
+func (UnimplementedInvoiceServiceServer) PreviewEmailTemplate(context.Context, *PreviewEmailTemplateRequest) (*EmailPreview, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PreviewEmailTemplate not implemented")
+}
+func (UnimplementedInvoiceServiceServer) SendTestEmail(context.Context, *SendTestEmailRequest) (*SendTestEmailResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendTestEmail not implemented")
+}
+func (UnimplementedInvoiceServiceServer) GetEmailTemplateSource(context.Context, *EmailTemplateRequest) (*EmailTemplateSource, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEmailTemplateSource not implemented")
+}
+func (UnimplementedInvoiceServiceServer) ListEmailTemplateVersions(context.Context, *EmailTemplateRequest) (*ListEmailTemplateVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEmailTemplateVersions not implemented")
+}
+func (UnimplementedInvoiceServiceServer) SaveEmailTemplate(context.Context, *SaveEmailTemplateRequest) (*EmailTemplateVersion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveEmailTemplate not implemented")
+}
+func (UnimplementedInvoiceServiceServer) RestoreEmailTemplate(context.Context, *RestoreEmailTemplateRequest) (*EmailTemplateVersion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreEmailTemplate not implemented")
+}
 func (UnimplementedInvoiceServiceServer) mustEmbedUnimplementedInvoiceServiceServer() {}
 func (UnimplementedInvoiceServiceServer) testEmbeddedByValue()                        {}
 
@@ -104,6 +337,251 @@ func _InvoiceService_CreateAndSendInvoice_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _InvoiceService_GetInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).GetInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_GetInvoice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).GetInvoice(ctx, req.(*GetInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_ListInvoices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInvoicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).ListInvoices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_ListInvoices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).ListInvoices(ctx, req.(*ListInvoicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_ResendInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResendInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).ResendInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_ResendInvoice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).ResendInvoice(ctx, req.(*ResendInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_VoidInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VoidInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).VoidInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_VoidInvoice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).VoidInvoice(ctx, req.(*VoidInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_DownloadInvoicePDF_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadInvoicePDFRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InvoiceServiceServer).DownloadInvoicePDF(m, &grpc.GenericServerStream[DownloadInvoicePDFRequest, InvoicePDFChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type InvoiceService_DownloadInvoicePDFServer = grpc.ServerStreamingServer[InvoicePDFChunk]
+
+func _InvoiceService_CreateCreditNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCreditNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).CreateCreditNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_CreateCreditNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).CreateCreditNote(ctx, req.(*CreateCreditNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_GetInvoiceXML_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).GetInvoiceXML(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_GetInvoiceXML_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).GetInvoiceXML(ctx, req.(*GetInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_ListEmailTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEmailTemplatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).ListEmailTemplates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_ListEmailTemplates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).ListEmailTemplates(ctx, req.(*ListEmailTemplatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_PreviewEmailTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreviewEmailTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).PreviewEmailTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_PreviewEmailTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).PreviewEmailTemplate(ctx, req.(*PreviewEmailTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_SendTestEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendTestEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).SendTestEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_SendTestEmail_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).SendTestEmail(ctx, req.(*SendTestEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_GetEmailTemplateSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).GetEmailTemplateSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_GetEmailTemplateSource_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).GetEmailTemplateSource(ctx, req.(*EmailTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_ListEmailTemplateVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).ListEmailTemplateVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_ListEmailTemplateVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).ListEmailTemplateVersions(ctx, req.(*EmailTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_SaveEmailTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveEmailTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).SaveEmailTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_SaveEmailTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).SaveEmailTemplate(ctx, req.(*SaveEmailTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_RestoreEmailTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreEmailTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).RestoreEmailTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_RestoreEmailTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).RestoreEmailTemplate(ctx, req.(*RestoreEmailTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // InvoiceService_ServiceDesc is the grpc.ServiceDesc for InvoiceService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,7 +593,65 @@ var InvoiceService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateAndSendInvoice",
 			Handler:    _InvoiceService_CreateAndSendInvoice_Handler,
 		},
+		{
+			MethodName: "GetInvoice",
+			Handler:    _InvoiceService_GetInvoice_Handler,
+		},
+		{
+			MethodName: "ListInvoices",
+			Handler:    _InvoiceService_ListInvoices_Handler,
+		},
+		{
+			MethodName: "ResendInvoice",
+			Handler:    _InvoiceService_ResendInvoice_Handler,
+		},
+		{
+			MethodName: "VoidInvoice",
+			Handler:    _InvoiceService_VoidInvoice_Handler,
+		},
+		{
+			MethodName: "CreateCreditNote",
+			Handler:    _InvoiceService_CreateCreditNote_Handler,
+		},
+		{
+			MethodName: "GetInvoiceXML",
+			Handler:    _InvoiceService_GetInvoiceXML_Handler,
+		},
+		{
+			MethodName: "ListEmailTemplates",
+			Handler:    _InvoiceService_ListEmailTemplates_Handler,
+		},
+		{
+			MethodName: "PreviewEmailTemplate",
+			Handler:    _InvoiceService_PreviewEmailTemplate_Handler,
+		},
+		{
+			MethodName: "SendTestEmail",
+			Handler:    _InvoiceService_SendTestEmail_Handler,
+		},
+		{
+			MethodName: "GetEmailTemplateSource",
+			Handler:    _InvoiceService_GetEmailTemplateSource_Handler,
+		},
+		{
+			MethodName: "ListEmailTemplateVersions",
+			Handler:    _InvoiceService_ListEmailTemplateVersions_Handler,
+		},
+		{
+			MethodName: "SaveEmailTemplate",
+			Handler:    _InvoiceService_SaveEmailTemplate_Handler,
+		},
+		{
+			MethodName: "RestoreEmailTemplate",
+			Handler:    _InvoiceService_RestoreEmailTemplate_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "DownloadInvoicePDF",
+			Handler:       _InvoiceService_DownloadInvoicePDF_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "invoice.proto",
 }
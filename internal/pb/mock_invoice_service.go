@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/LamThanhNguyen/yoyo-store-backend/internal/pb (interfaces: InvoiceServiceClient)
+// Source: github.com/LamThanhNguyen/yoyo-store-backend/internal/pb (interfaces: InvoiceServiceClient,InvoiceService_DownloadInvoicePDFClient)
 //
 // Generated by this command:
 //
-//	mockgen -package pb -destination internal/pb/mock_invoice_service.go github.com/LamThanhNguyen/yoyo-store-backend/internal/pb InvoiceServiceClient
+//	mockgen -package pb -destination internal/pb/mock_invoice_service.go github.com/LamThanhNguyen/yoyo-store-backend/internal/pb InvoiceServiceClient,InvoiceService_DownloadInvoicePDFClient
 //
 
 // Package pb is a generated GoMock package.
@@ -15,6 +15,7 @@ import (
 
 	gomock "go.uber.org/mock/gomock"
 	grpc "google.golang.org/grpc"
+	metadata "google.golang.org/grpc/metadata"
 )
 
 // MockInvoiceServiceClient is a mock of InvoiceServiceClient interface.
@@ -60,3 +61,407 @@ func (mr *MockInvoiceServiceClientMockRecorder) CreateAndSendInvoice(ctx, in any
 	varargs := append([]any{ctx, in}, opts...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAndSendInvoice", reflect.TypeOf((*MockInvoiceServiceClient)(nil).CreateAndSendInvoice), varargs...)
 }
+
+// CreateCreditNote mocks base method.
+func (m *MockInvoiceServiceClient) CreateCreditNote(ctx context.Context, in *CreateCreditNoteRequest, opts ...grpc.CallOption) (*CreditNote, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateCreditNote", varargs...)
+	ret0, _ := ret[0].(*CreditNote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCreditNote indicates an expected call of CreateCreditNote.
+func (mr *MockInvoiceServiceClientMockRecorder) CreateCreditNote(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCreditNote", reflect.TypeOf((*MockInvoiceServiceClient)(nil).CreateCreditNote), varargs...)
+}
+
+// DownloadInvoicePDF mocks base method.
+func (m *MockInvoiceServiceClient) DownloadInvoicePDF(ctx context.Context, in *DownloadInvoicePDFRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InvoicePDFChunk], error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DownloadInvoicePDF", varargs...)
+	ret0, _ := ret[0].(grpc.ServerStreamingClient[InvoicePDFChunk])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadInvoicePDF indicates an expected call of DownloadInvoicePDF.
+func (mr *MockInvoiceServiceClientMockRecorder) DownloadInvoicePDF(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadInvoicePDF", reflect.TypeOf((*MockInvoiceServiceClient)(nil).DownloadInvoicePDF), varargs...)
+}
+
+// GetEmailTemplateSource mocks base method.
+func (m *MockInvoiceServiceClient) GetEmailTemplateSource(ctx context.Context, in *EmailTemplateRequest, opts ...grpc.CallOption) (*EmailTemplateSource, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetEmailTemplateSource", varargs...)
+	ret0, _ := ret[0].(*EmailTemplateSource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmailTemplateSource indicates an expected call of GetEmailTemplateSource.
+func (mr *MockInvoiceServiceClientMockRecorder) GetEmailTemplateSource(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmailTemplateSource", reflect.TypeOf((*MockInvoiceServiceClient)(nil).GetEmailTemplateSource), varargs...)
+}
+
+// GetInvoice mocks base method.
+func (m *MockInvoiceServiceClient) GetInvoice(ctx context.Context, in *GetInvoiceRequest, opts ...grpc.CallOption) (*Invoice, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetInvoice", varargs...)
+	ret0, _ := ret[0].(*Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvoice indicates an expected call of GetInvoice.
+func (mr *MockInvoiceServiceClientMockRecorder) GetInvoice(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvoice", reflect.TypeOf((*MockInvoiceServiceClient)(nil).GetInvoice), varargs...)
+}
+
+// GetInvoiceXML mocks base method.
+func (m *MockInvoiceServiceClient) GetInvoiceXML(ctx context.Context, in *GetInvoiceRequest, opts ...grpc.CallOption) (*InvoiceXML, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetInvoiceXML", varargs...)
+	ret0, _ := ret[0].(*InvoiceXML)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvoiceXML indicates an expected call of GetInvoiceXML.
+func (mr *MockInvoiceServiceClientMockRecorder) GetInvoiceXML(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvoiceXML", reflect.TypeOf((*MockInvoiceServiceClient)(nil).GetInvoiceXML), varargs...)
+}
+
+// ListEmailTemplateVersions mocks base method.
+func (m *MockInvoiceServiceClient) ListEmailTemplateVersions(ctx context.Context, in *EmailTemplateRequest, opts ...grpc.CallOption) (*ListEmailTemplateVersionsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListEmailTemplateVersions", varargs...)
+	ret0, _ := ret[0].(*ListEmailTemplateVersionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEmailTemplateVersions indicates an expected call of ListEmailTemplateVersions.
+func (mr *MockInvoiceServiceClientMockRecorder) ListEmailTemplateVersions(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEmailTemplateVersions", reflect.TypeOf((*MockInvoiceServiceClient)(nil).ListEmailTemplateVersions), varargs...)
+}
+
+// ListEmailTemplates mocks base method.
+func (m *MockInvoiceServiceClient) ListEmailTemplates(ctx context.Context, in *ListEmailTemplatesRequest, opts ...grpc.CallOption) (*ListEmailTemplatesResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListEmailTemplates", varargs...)
+	ret0, _ := ret[0].(*ListEmailTemplatesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEmailTemplates indicates an expected call of ListEmailTemplates.
+func (mr *MockInvoiceServiceClientMockRecorder) ListEmailTemplates(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEmailTemplates", reflect.TypeOf((*MockInvoiceServiceClient)(nil).ListEmailTemplates), varargs...)
+}
+
+// ListInvoices mocks base method.
+func (m *MockInvoiceServiceClient) ListInvoices(ctx context.Context, in *ListInvoicesRequest, opts ...grpc.CallOption) (*ListInvoicesResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListInvoices", varargs...)
+	ret0, _ := ret[0].(*ListInvoicesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInvoices indicates an expected call of ListInvoices.
+func (mr *MockInvoiceServiceClientMockRecorder) ListInvoices(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInvoices", reflect.TypeOf((*MockInvoiceServiceClient)(nil).ListInvoices), varargs...)
+}
+
+// PreviewEmailTemplate mocks base method.
+func (m *MockInvoiceServiceClient) PreviewEmailTemplate(ctx context.Context, in *PreviewEmailTemplateRequest, opts ...grpc.CallOption) (*EmailPreview, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PreviewEmailTemplate", varargs...)
+	ret0, _ := ret[0].(*EmailPreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewEmailTemplate indicates an expected call of PreviewEmailTemplate.
+func (mr *MockInvoiceServiceClientMockRecorder) PreviewEmailTemplate(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewEmailTemplate", reflect.TypeOf((*MockInvoiceServiceClient)(nil).PreviewEmailTemplate), varargs...)
+}
+
+// ResendInvoice mocks base method.
+func (m *MockInvoiceServiceClient) ResendInvoice(ctx context.Context, in *ResendInvoiceRequest, opts ...grpc.CallOption) (*ResendInvoiceResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ResendInvoice", varargs...)
+	ret0, _ := ret[0].(*ResendInvoiceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResendInvoice indicates an expected call of ResendInvoice.
+func (mr *MockInvoiceServiceClientMockRecorder) ResendInvoice(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendInvoice", reflect.TypeOf((*MockInvoiceServiceClient)(nil).ResendInvoice), varargs...)
+}
+
+// RestoreEmailTemplate mocks base method.
+func (m *MockInvoiceServiceClient) RestoreEmailTemplate(ctx context.Context, in *RestoreEmailTemplateRequest, opts ...grpc.CallOption) (*EmailTemplateVersion, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RestoreEmailTemplate", varargs...)
+	ret0, _ := ret[0].(*EmailTemplateVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreEmailTemplate indicates an expected call of RestoreEmailTemplate.
+func (mr *MockInvoiceServiceClientMockRecorder) RestoreEmailTemplate(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreEmailTemplate", reflect.TypeOf((*MockInvoiceServiceClient)(nil).RestoreEmailTemplate), varargs...)
+}
+
+// SaveEmailTemplate mocks base method.
+func (m *MockInvoiceServiceClient) SaveEmailTemplate(ctx context.Context, in *SaveEmailTemplateRequest, opts ...grpc.CallOption) (*EmailTemplateVersion, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SaveEmailTemplate", varargs...)
+	ret0, _ := ret[0].(*EmailTemplateVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveEmailTemplate indicates an expected call of SaveEmailTemplate.
+func (mr *MockInvoiceServiceClientMockRecorder) SaveEmailTemplate(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveEmailTemplate", reflect.TypeOf((*MockInvoiceServiceClient)(nil).SaveEmailTemplate), varargs...)
+}
+
+// SendTestEmail mocks base method.
+func (m *MockInvoiceServiceClient) SendTestEmail(ctx context.Context, in *SendTestEmailRequest, opts ...grpc.CallOption) (*SendTestEmailResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SendTestEmail", varargs...)
+	ret0, _ := ret[0].(*SendTestEmailResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendTestEmail indicates an expected call of SendTestEmail.
+func (mr *MockInvoiceServiceClientMockRecorder) SendTestEmail(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTestEmail", reflect.TypeOf((*MockInvoiceServiceClient)(nil).SendTestEmail), varargs...)
+}
+
+// VoidInvoice mocks base method.
+func (m *MockInvoiceServiceClient) VoidInvoice(ctx context.Context, in *VoidInvoiceRequest, opts ...grpc.CallOption) (*Invoice, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "VoidInvoice", varargs...)
+	ret0, _ := ret[0].(*Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoidInvoice indicates an expected call of VoidInvoice.
+func (mr *MockInvoiceServiceClientMockRecorder) VoidInvoice(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidInvoice", reflect.TypeOf((*MockInvoiceServiceClient)(nil).VoidInvoice), varargs...)
+}
+
+// MockInvoiceService_DownloadInvoicePDFClient is a mock of InvoiceService_DownloadInvoicePDFClient interface.
+type MockInvoiceService_DownloadInvoicePDFClient[Res any] struct {
+	ctrl     *gomock.Controller
+	recorder *MockInvoiceService_DownloadInvoicePDFClientMockRecorder[Res]
+	isgomock struct{}
+}
+
+// MockInvoiceService_DownloadInvoicePDFClientMockRecorder is the mock recorder for MockInvoiceService_DownloadInvoicePDFClient.
+type MockInvoiceService_DownloadInvoicePDFClientMockRecorder[Res any] struct {
+	mock *MockInvoiceService_DownloadInvoicePDFClient[Res]
+}
+
+// NewMockInvoiceService_DownloadInvoicePDFClient creates a new mock instance.
+func NewMockInvoiceService_DownloadInvoicePDFClient[Res any](ctrl *gomock.Controller) *MockInvoiceService_DownloadInvoicePDFClient[Res] {
+	mock := &MockInvoiceService_DownloadInvoicePDFClient[Res]{ctrl: ctrl}
+	mock.recorder = &MockInvoiceService_DownloadInvoicePDFClientMockRecorder[Res]{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInvoiceService_DownloadInvoicePDFClient[Res]) EXPECT() *MockInvoiceService_DownloadInvoicePDFClientMockRecorder[Res] {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockInvoiceService_DownloadInvoicePDFClient[Res]) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockInvoiceService_DownloadInvoicePDFClientMockRecorder[Res]) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockInvoiceService_DownloadInvoicePDFClient[Res])(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockInvoiceService_DownloadInvoicePDFClient[Res]) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockInvoiceService_DownloadInvoicePDFClientMockRecorder[Res]) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockInvoiceService_DownloadInvoicePDFClient[Res])(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockInvoiceService_DownloadInvoicePDFClient[Res]) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockInvoiceService_DownloadInvoicePDFClientMockRecorder[Res]) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockInvoiceService_DownloadInvoicePDFClient[Res])(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockInvoiceService_DownloadInvoicePDFClient[Res]) Recv() (*InvoicePDFChunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*InvoicePDFChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockInvoiceService_DownloadInvoicePDFClientMockRecorder[Res]) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockInvoiceService_DownloadInvoicePDFClient[Res])(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockInvoiceService_DownloadInvoicePDFClient[Res]) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockInvoiceService_DownloadInvoicePDFClientMockRecorder[Res]) RecvMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockInvoiceService_DownloadInvoicePDFClient[Res])(nil).RecvMsg), m)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockInvoiceService_DownloadInvoicePDFClient[Res]) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockInvoiceService_DownloadInvoicePDFClientMockRecorder[Res]) SendMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockInvoiceService_DownloadInvoicePDFClient[Res])(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockInvoiceService_DownloadInvoicePDFClient[Res]) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockInvoiceService_DownloadInvoicePDFClientMockRecorder[Res]) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockInvoiceService_DownloadInvoicePDFClient[Res])(nil).Trailer))
+}
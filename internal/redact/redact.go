@@ -0,0 +1,38 @@
+// Package redact masks secrets, such as those in the configuration, before they are logged
+package redact
+
+import "net/url"
+
+const mask = "[REDACTED]"
+
+// Secret masks s, only showing whether it is set
+func Secret(s string) string {
+	if s == "" {
+		return ""
+	}
+	return mask
+}
+
+// URL masks the password of a URL such as a database connection string. Strings that aren't
+// URLs, like key=value connection strings, are masked entirely.
+func URL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" {
+		return Secret(s)
+	}
+
+	if u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), mask)
+		}
+	}
+
+	// postgres also takes the password as a parameter
+	q := u.Query()
+	if q.Has("password") {
+		q.Set("password", mask)
+		u.RawQuery = q.Encode()
+	}
+
+	return u.String()
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3Config configures an S3 store. Endpoint and the static keys are only needed for
+// S3-compatible services such as MinIO; on AWS the default credential chain is used.
+type S3Config struct {
+	Bucket    string
+	Region    string
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+}
+
+// S3 stores blobs as objects in an S3 bucket
+type S3 struct {
+	client *s3.Client
+	bucket string
+}
+
+// NewS3 returns a store for the configured bucket
+func NewS3(ctx context.Context, config S3Config) (*S3, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("a bucket is required for s3 storage")
+	}
+
+	region := config.Region
+	if region == "" {
+		region = "ap-southeast-1"
+	}
+
+	opts := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(region)}
+	if config.AccessKey != "" {
+		opts = append(opts, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(config.AccessKey, config.SecretKey, ""),
+		))
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if config.Endpoint != "" {
+			o.BaseEndpoint = aws.String(config.Endpoint)
+			// S3-compatible services generally don't support virtual-hosted buckets
+			o.UsePathStyle = true
+		}
+	})
+
+	return &S3{client: client, bucket: config.Bucket}, nil
+}
+
+// Put uploads body to key
+func (s *S3) Put(ctx context.Context, key string, body io.Reader, contentType string) error {
+	// the request is signed over the payload, so it must be seekable
+	seeker, ok := body.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		seeker = bytes.NewReader(data)
+	}
+
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        seeker,
+		ContentType: aws.String(contentType),
+	})
+	return err
+}
+
+// Get downloads the object stored under key
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return out.Body, nil
+}
+
+// Delete removes the object stored under key. Deleting a missing object is not an error.
+func (s *S3) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrNotFound is returned when no blob is stored under a key
+var ErrNotFound = errors.New("blob not found")
+
+// Store keeps blobs, such as invoice PDFs and item images, under string keys
+type Store interface {
+	Put(ctx context.Context, key string, body io.Reader, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Config selects and configures a store. Driver is "local" (the default), which keeps blobs
+// under Dir, or "s3".
+type Config struct {
+	Driver string
+	Dir    string
+	S3     S3Config
+}
+
+// New returns the store selected by config
+func New(ctx context.Context, config Config) (Store, error) {
+	switch config.Driver {
+	case "", "local":
+		return NewLocal(config.Dir)
+
+	case "s3":
+		return NewS3(ctx, config.S3)
+
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q: must be local or s3", config.Driver)
+	}
+}
@@ -0,0 +1,189 @@
+package throttle
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+const (
+	ActionLogin              = "login"
+	ActionPasswordReset      = "password-reset"
+	ActionPaymentFailedEmail = "payment-failed-email"
+)
+
+// Policy describes how repeated failures are slowed down and eventually locked out
+type Policy struct {
+	FreeAttempts int           // failures allowed before any delay is applied
+	BaseDelay    time.Duration // delay applied to the first failure over FreeAttempts
+	MaxDelay     time.Duration // delays are capped here; reaching it is a lockout
+	Window       time.Duration // failures older than this are forgotten
+}
+
+// Account is the policy applied per email address
+var Account = Policy{
+	FreeAttempts: 3,
+	BaseDelay:    time.Second,
+	MaxDelay:     15 * time.Minute,
+	Window:       time.Hour,
+}
+
+// IP is the policy applied per client address. It is looser than Account,
+// since many users can share an address behind a NAT.
+var IP = Policy{
+	FreeAttempts: 20,
+	BaseDelay:    time.Second,
+	MaxDelay:     15 * time.Minute,
+	Window:       time.Hour,
+}
+
+// Notification is the policy applied per email address to emails anyone can trigger, such as
+// the payment failure email: one an hour, backing off to one a day.
+var Notification = Policy{
+	FreeAttempts: 0,
+	BaseDelay:    time.Hour,
+	MaxDelay:     24 * time.Hour,
+	Window:       24 * time.Hour,
+}
+
+// Delay returns how long further attempts are blocked after the given number of failures.
+// The delay doubles with each failure over the free attempts, up to MaxDelay.
+func (p Policy) Delay(failures int) time.Duration {
+	over := failures - p.FreeAttempts
+	if over <= 0 {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < over; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+
+	return min(delay, p.MaxDelay)
+}
+
+// IsLockout reports whether a delay is long enough to count as a lockout
+func (p Policy) IsLockout(delay time.Duration) bool {
+	return delay >= p.MaxDelay
+}
+
+// Key is a throttled identity, such as an account or a client address
+type Key struct {
+	Name   string
+	Policy Policy
+}
+
+// AccountKey returns the throttle key for an email address
+func AccountKey(email string) Key {
+	return Key{Name: "account:" + strings.ToLower(strings.TrimSpace(email)), Policy: Account}
+}
+
+// NotificationKey returns the throttle key for the emails sent to an address
+func NotificationKey(email string) Key {
+	return Key{Name: AccountKey(email).Name, Policy: Notification}
+}
+
+// IPKey returns the throttle key for a client address
+func IPKey(ip string) Key {
+	return Key{Name: "ip:" + ip, Policy: IP}
+}
+
+// Keys returns the throttle keys for an attempt against email from ip
+func Keys(email, ip string) []Key {
+	return []Key{AccountKey(email), IPKey(ip)}
+}
+
+// ClientIP returns the address of the client making the request. Behind trusted proxies,
+// RealIP must have run first for this to be the client's rather than the proxy's.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// ParseProxies parses the addresses and networks, in CIDR notation, of trusted proxies
+func ParseProxies(proxies []string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, p := range proxies {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		if !strings.Contains(p, "/") {
+			addr, err := netip.ParseAddr(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", p, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", p, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, nil
+}
+
+// RealIP sets the RemoteAddr of requests made through one of proxies to the address of the
+// client, from X-Forwarded-For. Each proxy appends the address it saw, so the client is the
+// last entry that isn't itself a trusted proxy. Requests from anywhere else keep their
+// RemoteAddr, whatever header they send.
+func RealIP(proxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip, ok := forwardedFor(r, proxies); ok {
+				r.RemoteAddr = ip
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedFor returns the client address forwarded by trusted proxies, if r came through them
+func forwardedFor(r *http.Request, proxies []netip.Prefix) (string, bool) {
+	remote, err := netip.ParseAddr(ClientIP(r))
+	if err != nil || !trusted(remote, proxies) {
+		return "", false
+	}
+
+	entries := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+
+	client := ""
+	for i := len(entries) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(entries[i]))
+		if err != nil {
+			break
+		}
+		client = addr.Unmap().String()
+		if !trusted(addr, proxies) {
+			break
+		}
+	}
+
+	return client, client != ""
+}
+
+// trusted reports whether addr is one of proxies
+func trusted(addr netip.Addr, proxies []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, p := range proxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package throttle
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDelay(t *testing.T) {
+	p := Policy{FreeAttempts: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{3, 0},
+		{4, time.Second},
+		{5, 2 * time.Second},
+		{7, 8 * time.Second},
+		{8, 10 * time.Second},
+		{50, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := p.Delay(tt.failures); got != tt.want {
+			t.Errorf("Delay(%d) = %s, want %s", tt.failures, got, tt.want)
+		}
+	}
+
+	if !p.IsLockout(p.Delay(8)) {
+		t.Error("expected max delay to be a lockout")
+	}
+	if p.IsLockout(p.Delay(7)) {
+		t.Error("expected shorter delay not to be a lockout")
+	}
+}
+
+func TestKeys(t *testing.T) {
+	keys := Keys(" Admin@Example.com ", "10.0.0.1")
+	if keys[0].Name != "account:admin@example.com" {
+		t.Errorf("unexpected account key %q", keys[0].Name)
+	}
+	if keys[1].Name != "ip:10.0.0.1" {
+		t.Errorf("unexpected ip key %q", keys[1].Name)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	r := httptest.NewRequest("POST", "/", nil)
+	r.RemoteAddr = "192.0.2.1:1234"
+	r.Header.Set("X-Forwarded-For", "198.51.100.7")
+	if ip := ClientIP(r); ip != "192.0.2.1" {
+		t.Errorf("expected remote address, got %s", ip)
+	}
+}
+
+func TestRealIP(t *testing.T) {
+	proxies, err := ParseProxies([]string{"10.0.0.0/8", " 192.0.2.1", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		remote string
+		xff    []string
+		want   string
+	}{
+		{"direct", "203.0.113.9:1234", nil, "203.0.113.9"},
+		{"direct with a forged header", "203.0.113.9:1234", []string{"198.51.100.7"}, "203.0.113.9"},
+		{"through a proxy", "10.1.2.3:1234", []string{"203.0.113.9, 198.51.100.7"}, "198.51.100.7"},
+		{"through a chain of proxies", "10.1.2.3:1234", []string{"198.51.100.7, 192.0.2.1", "10.4.5.6"}, "198.51.100.7"},
+		{"through a proxy without a header", "10.1.2.3:1234", nil, "10.1.2.3"},
+		{"through a proxy with a bad header", "10.1.2.3:1234", []string{"unknown"}, "10.1.2.3"},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("POST", "/", nil)
+		r.RemoteAddr = tt.remote
+		for _, v := range tt.xff {
+			r.Header.Add("X-Forwarded-For", v)
+		}
+
+		var got string
+		RealIP(proxies)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = ClientIP(r)
+		})).ServeHTTP(httptest.NewRecorder(), r)
+
+		if got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+
+	if _, err := ParseProxies([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("expected an invalid network to be refused")
+	}
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/i18n"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/mailer"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/server_invoice/layout"
+)
+
+// issueCreditNote records a credit note against inv, generates its PDF and emails it to the
+// customer. It is safe to retry with the same reference: the credit note is only issued and
+// queued once, and is marked sent when the mail outbox delivers it.
+func (server *Server) issueCreditNote(inv models.Invoice, amount int, reason, reference string) (models.CreditNote, error) {
+	cn, err := server.DB.CreateCreditNote(models.CreditNote{
+		InvoiceID: inv.ID,
+		Reference: reference,
+		Amount:    amount,
+		Currency:  inv.Currency,
+		Reason:    reason,
+		IssuedAt:  time.Now(),
+	})
+	if err != nil {
+		return cn, err
+	}
+	cn.InvoiceNumber = inv.Number
+
+	if cn.Status == models.CreditNoteStatusSent {
+		return cn, nil
+	}
+	if queued, err := server.DB.MailOutboxQueued(creditNoteRef(cn.ID)); err != nil {
+		return cn, err
+	} else if queued {
+		return cn, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if cn.PDFLocation == "" {
+		location, err := server.createCreditNotePDF(ctx, inv, cn)
+		if err != nil {
+			return cn, err
+		}
+		if err := server.DB.SetCreditNotePDF(cn.ID, location); err != nil {
+			return cn, err
+		}
+		cn.PDFLocation = location
+	}
+
+	pdf, err := server.creditNotePDF(ctx, cn)
+	if err != nil {
+		return cn, err
+	}
+
+	attachments := []mailer.Attachment{pdf}
+	c := i18n.For(inv.Locale)
+	if err := server.SendMail("info@yoyo.com", inv.Email, c.T("email.credit_note.subject"), "credit-note", inv.Locale, creditNoteRef(cn.ID), attachments, cn); err != nil {
+		return cn, err
+	}
+
+	return cn, nil
+}
+
+// creditNotePDF reads the PDF of a credit note from storage
+func (server *Server) creditNotePDF(ctx context.Context, cn models.CreditNote) (mailer.Attachment, error) {
+	body, err := server.store.Get(ctx, cn.PDFLocation)
+	if err != nil {
+		return mailer.Attachment{}, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return mailer.Attachment{}, err
+	}
+
+	return mailer.Attachment{
+		Name:        fmt.Sprintf("%s.pdf", cn.Number),
+		ContentType: "application/pdf",
+		Data:        data,
+	}, nil
+}
+
+// createCreditNotePDF generates a PDF version of the credit note, and returns its storage key
+func (server *Server) createCreditNotePDF(ctx context.Context, inv models.Invoice, cn models.CreditNote) (string, error) {
+	var buf bytes.Buffer
+	if err := layout.RenderCreditNote(&buf, server.seller, inv, cn); err != nil {
+		return "", err
+	}
+
+	key := fmt.Sprintf("%d/%s.pdf", cn.IssuedAt.Year(), cn.Number)
+	if err := server.store.Put(ctx, key, &buf, "application/pdf"); err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/pb"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateCreditNote(t *testing.T) {
+	inv := models.Invoice{ID: 7, Number: "INV-2026-000012", OrderID: 42, Email: "jane@example.com", Currency: "usd", Total: 2680}
+
+	// created returns the credit note CreateCreditNote issues, numbered 3 with the given status
+	created := func(status string) func(models.CreditNote) (models.CreditNote, error) {
+		return func(cn models.CreditNote) (models.CreditNote, error) {
+			cn.ID = 3
+			cn.Number = "CN-2026-000003"
+			cn.Status = status
+			return cn, nil
+		}
+	}
+
+	tests := []struct {
+		name     string
+		req      *pb.CreateCreditNoteRequest
+		setup    func(db *MockinvoiceStore)
+		wantCode codes.Code
+	}{
+		{
+			name: "by invoice number",
+			req:  &pb.CreateCreditNoteRequest{InvoiceNumber: inv.Number, Amount: 1000, Reason: "refund", Reference: "re_1"},
+			setup: func(db *MockinvoiceStore) {
+				db.EXPECT().GetInvoiceByNumber(inv.Number).Return(inv, nil)
+				db.EXPECT().CreateCreditNote(gomock.Any()).DoAndReturn(created(models.CreditNoteStatusIssued))
+				db.EXPECT().MailOutboxQueued("credit_note:3").Return(false, nil)
+				db.EXPECT().SetCreditNotePDF(3, gomock.Any()).Return(nil)
+				db.EXPECT().InsertMailOutbox("jane@example.com", gomock.Any(), "credit_note:3", gomock.Any()).Return(1, nil)
+			},
+		},
+		{
+			name: "by order id",
+			req:  &pb.CreateCreditNoteRequest{OrderId: 42, Amount: 1000, Reference: "re_1"},
+			setup: func(db *MockinvoiceStore) {
+				db.EXPECT().GetInvoiceByOrderID(42).Return(inv, nil)
+				db.EXPECT().CreateCreditNote(gomock.Any()).DoAndReturn(created(models.CreditNoteStatusIssued))
+				db.EXPECT().MailOutboxQueued("credit_note:3").Return(false, nil)
+				db.EXPECT().SetCreditNotePDF(3, gomock.Any()).Return(nil)
+				db.EXPECT().InsertMailOutbox("jane@example.com", gomock.Any(), "credit_note:3", gomock.Any()).Return(1, nil)
+			},
+		},
+		{
+			// the same reference returns the credit note already issued, without sending it again
+			name: "same reference after it was sent",
+			req:  &pb.CreateCreditNoteRequest{InvoiceNumber: inv.Number, Amount: 1000, Reference: "re_1"},
+			setup: func(db *MockinvoiceStore) {
+				db.EXPECT().GetInvoiceByNumber(inv.Number).Return(inv, nil)
+				db.EXPECT().CreateCreditNote(gomock.Any()).DoAndReturn(created(models.CreditNoteStatusSent))
+			},
+		},
+		{
+			name: "same reference while its email is queued",
+			req:  &pb.CreateCreditNoteRequest{InvoiceNumber: inv.Number, Amount: 1000, Reference: "re_1"},
+			setup: func(db *MockinvoiceStore) {
+				db.EXPECT().GetInvoiceByNumber(inv.Number).Return(inv, nil)
+				db.EXPECT().CreateCreditNote(gomock.Any()).DoAndReturn(created(models.CreditNoteStatusIssued))
+				db.EXPECT().MailOutboxQueued("credit_note:3").Return(true, nil)
+			},
+		},
+		{
+			name: "more than is left on the invoice",
+			req:  &pb.CreateCreditNoteRequest{InvoiceNumber: inv.Number, Amount: 5000, Reference: "re_2"},
+			setup: func(db *MockinvoiceStore) {
+				db.EXPECT().GetInvoiceByNumber(inv.Number).Return(inv, nil)
+				db.EXPECT().CreateCreditNote(gomock.Any()).Return(models.CreditNote{}, models.ErrCreditExceedsInvoice)
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "void invoice",
+			req:  &pb.CreateCreditNoteRequest{InvoiceNumber: inv.Number, Amount: 1000, Reference: "re_1"},
+			setup: func(db *MockinvoiceStore) {
+				void := inv
+				void.Status = models.InvoiceStatusVoid
+				db.EXPECT().GetInvoiceByNumber(inv.Number).Return(void, nil)
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "no invoice for the order",
+			req:  &pb.CreateCreditNoteRequest{OrderId: 43, Amount: 1000, Reference: "re_1"},
+			setup: func(db *MockinvoiceStore) {
+				db.EXPECT().GetInvoiceByOrderID(43).Return(models.Invoice{}, sql.ErrNoRows)
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "issued but not queued",
+			req:  &pb.CreateCreditNoteRequest{InvoiceNumber: inv.Number, Amount: 1000, Reference: "re_1"},
+			setup: func(db *MockinvoiceStore) {
+				db.EXPECT().GetInvoiceByNumber(inv.Number).Return(inv, nil)
+				db.EXPECT().CreateCreditNote(gomock.Any()).DoAndReturn(created(models.CreditNoteStatusIssued))
+				db.EXPECT().MailOutboxQueued("credit_note:3").Return(false, errors.New("db down"))
+			},
+			wantCode: codes.Unavailable,
+		},
+		{
+			name:     "no reference",
+			req:      &pb.CreateCreditNoteRequest{InvoiceNumber: inv.Number, Amount: 1000},
+			setup:    func(db *MockinvoiceStore) {},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "no amount",
+			req:      &pb.CreateCreditNoteRequest{InvoiceNumber: inv.Number, Reference: "re_1"},
+			setup:    func(db *MockinvoiceStore) {},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := NewMockinvoiceStore(ctrl)
+			tt.setup(mockDB)
+
+			g := NewGRPCServer(newTestServer(t, mockDB))
+			cn, err := g.CreateCreditNote(context.Background(), tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("expected %s, got %v", tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if cn.Number != "CN-2026-000003" || cn.InvoiceNumber != inv.Number || cn.Amount != tt.req.Amount || cn.Reference != tt.req.Reference {
+				t.Errorf("unexpected credit note %+v", cn)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/i18n"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/mailer"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/pb"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// emailSample is what an email template is previewed with: the catalog key of its subject,
+// and data shaped like what the template gets when it is sent for real
+type emailSample struct {
+	subject string
+	data    interface{}
+}
+
+var emailSamples = map[string]emailSample{
+	"invoice": {
+		subject: "email.invoice.subject",
+		data: models.Invoice{
+			Number:    "INV-2026-000001",
+			OrderID:   1,
+			FirstName: "Jane",
+			LastName:  "Doe",
+			Email:     "jane@example.com",
+			Product:   "Bronze Plan monthly subscription",
+			Quantity:  1,
+			Currency:  "usd",
+			Subtotal:  2000,
+			Total:     2000,
+			Status:    models.InvoiceStatusSent,
+			IssuedAt:  time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+		},
+	},
+	"credit-note": {
+		subject: "email.credit_note.subject",
+		data: models.CreditNote{
+			Number:        "CN-2026-000001",
+			InvoiceNumber: "INV-2026-000001",
+			Amount:        2000,
+			Currency:      "usd",
+			Reason:        "Damaged in shipping",
+			Status:        models.CreditNoteStatusSent,
+			IssuedAt:      time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC),
+		},
+	},
+}
+
+// previewEmail renders email template name with its sample data, in locale
+func (server *Server) previewEmail(name, locale string) (*pb.EmailPreview, emailSample, error) {
+	sample, ok := emailSamples[name]
+	if !ok || !server.templates.Exists(name) {
+		return nil, sample, status.Errorf(codes.NotFound, "no email template %q", name)
+	}
+
+	c := i18n.For(locale)
+	html, plain, err := server.templates.Render(name, c.Locale(), sample.data)
+	if err != nil {
+		return nil, sample, status.Errorf(codes.FailedPrecondition, "email template %s: %v", name, err)
+	}
+
+	return &pb.EmailPreview{
+		Name:    name,
+		Locale:  c.Locale(),
+		Subject: c.T(sample.subject),
+		Html:    html,
+		Plain:   plain,
+	}, sample, nil
+}
+
+func (g *GRPCServer) ListEmailTemplates(ctx context.Context, req *pb.ListEmailTemplatesRequest) (*pb.ListEmailTemplatesResponse, error) {
+	templates, err := g.templates.List()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListEmailTemplatesResponse{}
+	for _, t := range templates {
+		resp.Templates = append(resp.Templates, &pb.EmailTemplate{Name: t.Name, Locales: t.Locales})
+	}
+
+	return resp, nil
+}
+
+func (g *GRPCServer) PreviewEmailTemplate(ctx context.Context, req *pb.PreviewEmailTemplateRequest) (*pb.EmailPreview, error) {
+	preview, _, err := g.previewEmail(req.Name, req.Locale)
+	return preview, err
+}
+
+// SendTestEmail sends a template rendered with sample data, without attachments
+func (g *GRPCServer) SendTestEmail(ctx context.Context, req *pb.SendTestEmailRequest) (*pb.SendTestEmailResponse, error) {
+	if req.Email == "" {
+		return nil, status.Error(codes.InvalidArgument, "an email is required")
+	}
+
+	preview, sample, err := g.previewEmail(req.Name, req.Locale)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := "[Test] " + preview.Subject
+	if err := g.SendMail("info@yoyo.com", req.Email, subject, req.Name, preview.Locale, "", nil, sample.data); err != nil {
+		log.Error().Err(err).Str("template", req.Name).Msg("SendTestEmail")
+		return nil, status.Error(codes.Unavailable, "could not send the test email")
+	}
+
+	return &pb.SendTestEmailResponse{
+		Message: fmt.Sprintf("Test email %s sent to %s", req.Name, req.Email),
+	}, nil
+}
+
+func (g *GRPCServer) GetEmailTemplateSource(ctx context.Context, req *pb.EmailTemplateRequest) (*pb.EmailTemplateSource, error) {
+	src, err := g.templates.Source(req.Name, req.Locale)
+	if err != nil {
+		return nil, templateStatus(err)
+	}
+
+	return &pb.EmailTemplateSource{
+		Name:    src.Name,
+		Locale:  src.Locale,
+		Version: int32(src.Version),
+		Html:    src.HTML,
+		Plain:   src.Plain,
+	}, nil
+}
+
+func (g *GRPCServer) ListEmailTemplateVersions(ctx context.Context, req *pb.EmailTemplateRequest) (*pb.ListEmailTemplateVersionsResponse, error) {
+	versions, err := g.templates.Versions(req.Name, req.Locale)
+	if err != nil {
+		return nil, templateStatus(err)
+	}
+
+	resp := &pb.ListEmailTemplateVersionsResponse{}
+	for _, v := range versions {
+		resp.Versions = append(resp.Versions, emailTemplateVersionToPB(v))
+	}
+
+	return resp, nil
+}
+
+func (g *GRPCServer) SaveEmailTemplate(ctx context.Context, req *pb.SaveEmailTemplateRequest) (*pb.EmailTemplateVersion, error) {
+	v, err := g.templates.Save(req.Name, req.Locale, req.Html, req.Plain, req.Author, emailSamples[req.Name].data)
+	if err != nil {
+		return nil, templateStatus(err)
+	}
+
+	log.Info().Str("template", v.Name).Str("locale", v.Locale).Int("version", v.Version).Str("author", v.CreatedBy).Msg("email template saved")
+	return emailTemplateVersionToPB(v), nil
+}
+
+func (g *GRPCServer) RestoreEmailTemplate(ctx context.Context, req *pb.RestoreEmailTemplateRequest) (*pb.EmailTemplateVersion, error) {
+	v, err := g.templates.Restore(req.Name, req.Locale, int(req.Version), req.Author)
+	if err != nil {
+		return nil, templateStatus(err)
+	}
+
+	log.Info().Str("template", v.Name).Str("locale", v.Locale).Int("version", v.Version).Str("author", v.CreatedBy).Msg("email template restored")
+	return emailTemplateVersionToPB(v), nil
+}
+
+// templateStatus maps an error editing an email template to a gRPC status
+func templateStatus(err error) error {
+	switch {
+	case errors.Is(err, mailer.ErrTemplateNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, mailer.ErrInvalidTemplate):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		log.Error().Err(err).Msg("templateStatus")
+		return status.Error(codes.Internal, "could not read or save the email template")
+	}
+}
+
+func emailTemplateVersionToPB(v models.EmailTemplateVersion) *pb.EmailTemplateVersion {
+	return &pb.EmailTemplateVersion{
+		Id:          int32(v.ID),
+		Name:        v.Name,
+		Locale:      v.Locale,
+		Version:     int32(v.Version),
+		Html:        v.HTML,
+		Plain:       v.Plain,
+		UsesDefault: v.UsesDefault,
+		CreatedBy:   v.CreatedBy,
+		CreatedAt:   timestamppb.New(v.CreatedAt),
+	}
+}
@@ -2,9 +2,17 @@ package api
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"io"
 
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
 	"github.com/LamThanhNguyen/yoyo-store-backend/internal/pb"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // GRPCServer implements pb.InvoiceServiceServer
@@ -18,26 +26,303 @@ func NewGRPCServer(s *Server) *GRPCServer {
 }
 
 func (g *GRPCServer) CreateAndSendInvoice(ctx context.Context, req *pb.CreateInvoiceRequest) (*pb.CreateInvoiceResponse, error) {
+	if req.Id <= 0 || req.Quantity <= 0 || req.Email == "" {
+		return nil, status.Error(codes.InvalidArgument, "an order id, quantity and email are required")
+	}
+
 	order := Order{
-		ID:        int(req.Id),
-		Quantity:  int(req.Quantity),
-		Amount:    int(req.Amount),
-		Product:   req.Product,
-		FirstName: req.FirstName,
-		LastName:  req.LastName,
-		Email:     req.Email,
-		CreatedAt: req.CreatedAt.AsTime(),
+		ID:            int(req.Id),
+		Quantity:      int(req.Quantity),
+		Amount:        int(req.Amount),
+		Product:       req.Product,
+		Currency:      req.Currency,
+		Discount:      int(req.Discount),
+		DiscountLabel: req.DiscountLabel,
+		Shipping:      int(req.Shipping),
+		TaxRateBps:    int(req.TaxRateBps),
+		CardBrand:     req.CardBrand,
+		LastFour:      req.LastFour,
+		FirstName:     req.FirstName,
+		LastName:      req.LastName,
+		Email:         req.Email,
+		BuyerCountry:  req.BuyerCountry,
+		Locale:        req.Locale,
+		CreatedAt:     req.CreatedAt.AsTime(),
+	}
+	for _, line := range req.LineItems {
+		order.LineItems = append(order.LineItems, models.InvoiceLineItem{
+			Description: line.Description,
+			Quantity:    int(line.Quantity),
+			UnitPrice:   int(line.UnitPrice),
+			Amount:      int(line.Amount),
+		})
+	}
+
+	inv, err := g.issueInvoice(order)
+	if err != nil && inv.Status == models.InvoiceStatusVoid {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+
+	msg := fmt.Sprintf("Invoice %s created and queued for %s", inv.Number, inv.Email)
+	return &pb.CreateInvoiceResponse{Message: msg, InvoiceNumber: inv.Number}, nil
+}
+
+// pdfChunkSize is the size of the chunks DownloadInvoicePDF streams
+const pdfChunkSize = 64 * 1024
+
+func (g *GRPCServer) GetInvoice(ctx context.Context, req *pb.GetInvoiceRequest) (*pb.Invoice, error) {
+	inv, err := g.getInvoice(req.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	return invoiceToPB(inv), nil
+}
+
+func (g *GRPCServer) ListInvoices(ctx context.Context, req *pb.ListInvoicesRequest) (*pb.ListInvoicesResponse, error) {
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+	page := int(req.Page)
+	if page <= 0 {
+		page = 1
+	}
+
+	invoices, lastPage, totalRecords, err := g.DB.GetInvoicesPaginated(req.Email, int(req.OrderId), pageSize, page)
+	if err != nil {
+		log.Error().Err(err).Msg("ListInvoices")
+		return nil, status.Error(codes.Internal, "could not list invoices")
+	}
+
+	resp := &pb.ListInvoicesResponse{
+		CurrentPage:  int32(page),
+		PageSize:     int32(pageSize),
+		LastPage:     int32(lastPage),
+		TotalRecords: int32(totalRecords),
+	}
+	for _, inv := range invoices {
+		resp.Invoices = append(resp.Invoices, invoiceToPB(*inv))
+	}
+
+	return resp, nil
+}
+
+func (g *GRPCServer) ResendInvoice(ctx context.Context, req *pb.ResendInvoiceRequest) (*pb.ResendInvoiceResponse, error) {
+	inv, err := g.getInvoice(req.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	if inv.Status == models.InvoiceStatusVoid {
+		return nil, status.Errorf(codes.FailedPrecondition, "invoice %s is void", inv.Number)
+	}
+
+	to := req.Email
+	if to == "" {
+		to = inv.Email
+	}
+
+	if _, err := g.sendInvoice(inv, to); err != nil {
+		log.Error().Err(err).Str("number", inv.Number).Msg("ResendInvoice")
+		return nil, status.Error(codes.Unavailable, "could not send the invoice")
+	}
+
+	return &pb.ResendInvoiceResponse{
+		Message: fmt.Sprintf("Invoice %s queued for %s", inv.Number, to),
+	}, nil
+}
+
+func (g *GRPCServer) VoidInvoice(ctx context.Context, req *pb.VoidInvoiceRequest) (*pb.Invoice, error) {
+	inv, err := g.getInvoice(req.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := inv.LineItems
+	inv, err = g.DB.VoidInvoice(inv.ID, req.Reason)
+	inv.LineItems = lines
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.FailedPrecondition, "invoice %s is already void", req.Number)
+	} else if err != nil {
+		log.Error().Err(err).Msg("VoidInvoice")
+		return nil, status.Error(codes.Internal, "could not void the invoice")
+	}
+
+	return invoiceToPB(inv), nil
+}
+
+func (g *GRPCServer) DownloadInvoicePDF(req *pb.DownloadInvoicePDFRequest, stream pb.InvoiceService_DownloadInvoicePDFServer) error {
+	inv, err := g.getInvoice(req.Number)
+	if err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+
+	if err := g.ensureInvoicePDF(ctx, &inv); err != nil {
+		log.Error().Err(err).Msg("DownloadInvoicePDF")
+		return status.Error(codes.Internal, "could not generate the invoice pdf")
 	}
 
-	if err := g.createInvoicePDF(order); err != nil {
+	body, err := g.store.Get(ctx, inv.PDFLocation)
+	if err != nil {
+		log.Error().Err(err).Msg("DownloadInvoicePDF")
+		return status.Error(codes.Internal, "could not read the invoice pdf")
+	}
+	defer body.Close()
+
+	buf := make([]byte, pdfChunkSize)
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&pb.InvoicePDFChunk{Data: buf[:n]}); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			log.Error().Err(err).Msg("DownloadInvoicePDF")
+			return status.Error(codes.Internal, "could not read the invoice pdf")
+		}
+	}
+}
+
+func (g *GRPCServer) CreateCreditNote(ctx context.Context, req *pb.CreateCreditNoteRequest) (*pb.CreditNote, error) {
+	if req.Amount <= 0 || req.Reference == "" || (req.InvoiceNumber == "" && req.OrderId <= 0) {
+		return nil, status.Error(codes.InvalidArgument, "an invoice number or order id, a positive amount and a reference are required")
+	}
+
+	var inv models.Invoice
+	var err error
+	if req.InvoiceNumber != "" {
+		inv, err = g.getInvoice(req.InvoiceNumber)
+	} else {
+		inv, err = g.DB.GetInvoiceByOrderID(int(req.OrderId))
+		if errors.Is(err, sql.ErrNoRows) {
+			err = status.Errorf(codes.NotFound, "no invoice for order %d", req.OrderId)
+		} else if err != nil {
+			log.Error().Err(err).Int32("order_id", req.OrderId).Msg("CreateCreditNote")
+			err = status.Error(codes.Internal, "could not get the invoice")
+		}
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	attachments := []string{fmt.Sprintf("./invoices/%d.pdf", order.ID)}
-	if err := g.SendMail("info@yoyo.com", order.Email, "Your invoice", "invoice", attachments, nil); err != nil {
+	if inv.Status == models.InvoiceStatusVoid {
+		return nil, status.Errorf(codes.FailedPrecondition, "invoice %s is void", inv.Number)
+	}
+
+	cn, err := g.issueCreditNote(inv, int(req.Amount), req.Reason, req.Reference)
+	if errors.Is(err, models.ErrCreditExceedsInvoice) {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s for invoice %s", err, inv.Number)
+	} else if err != nil && cn.ID == 0 {
+		log.Error().Err(err).Str("number", inv.Number).Msg("CreateCreditNote")
+		return nil, status.Error(codes.Internal, "could not issue the credit note")
+	} else if err != nil {
+		// the credit note exists, so retrying the request will only try to send it again
+		log.Error().Err(err).Str("number", cn.Number).Msg("CreateCreditNote")
+		return nil, status.Errorf(codes.Unavailable, "credit note %s was issued but could not be sent", cn.Number)
+	}
+
+	return creditNoteToPB(cn), nil
+}
+
+func (g *GRPCServer) GetInvoiceXML(ctx context.Context, req *pb.GetInvoiceRequest) (*pb.InvoiceXML, error) {
+	inv, err := g.getInvoice(req.Number)
+	if err != nil {
 		return nil, err
 	}
 
-	msg := fmt.Sprintf("Invoice %d.pdf created and sent to %s", order.ID, order.Email)
-	return &pb.CreateInvoiceResponse{Message: msg}, nil
+	if inv.Status == models.InvoiceStatusVoid {
+		return nil, status.Errorf(codes.FailedPrecondition, "invoice %s is void", inv.Number)
+	}
+
+	xml, err := g.invoiceXML(inv)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	return &pb.InvoiceXML{Filename: xml.Name, Data: xml.Data}, nil
+}
+
+// getInvoice gets an invoice by number, mapping errors to gRPC status errors
+func (g *GRPCServer) getInvoice(number string) (models.Invoice, error) {
+	inv, err := g.DB.GetInvoiceByNumber(number)
+	if errors.Is(err, sql.ErrNoRows) {
+		return inv, status.Errorf(codes.NotFound, "invoice %s not found", number)
+	} else if err != nil {
+		log.Error().Err(err).Str("number", number).Msg("getInvoice")
+		return inv, status.Error(codes.Internal, "could not get the invoice")
+	}
+
+	return inv, nil
+}
+
+func invoiceToPB(inv models.Invoice) *pb.Invoice {
+	out := &pb.Invoice{
+		Id:           int32(inv.ID),
+		Number:       inv.Number,
+		OrderId:      int32(inv.OrderID),
+		FirstName:    inv.FirstName,
+		LastName:     inv.LastName,
+		Email:        inv.Email,
+		Product:      inv.Product,
+		Quantity:     int32(inv.Quantity),
+		Subtotal:     int32(inv.Subtotal),
+		Tax:          int32(inv.Tax),
+		Total:        int32(inv.Total),
+		Status:       inv.Status,
+		IssuedAt:     timestamppb.New(inv.IssuedAt),
+		DueAt:        timestamppb.New(inv.DueAt),
+		VoidReason:   inv.VoidReason,
+		Currency:     inv.Currency,
+		Discount:     int32(inv.Discount),
+		Shipping:     int32(inv.Shipping),
+		TaxRateBps:   int32(inv.TaxRateBps),
+		BuyerCountry: inv.BuyerCountry,
+		Locale:       inv.Locale,
+	}
+
+	for _, line := range inv.LineItems {
+		out.LineItems = append(out.LineItems, &pb.LineItem{
+			Description: line.Description,
+			Quantity:    int32(line.Quantity),
+			UnitPrice:   int32(line.UnitPrice),
+			Amount:      int32(line.Amount),
+		})
+	}
+
+	if inv.SentAt != nil {
+		out.SentAt = timestamppb.New(*inv.SentAt)
+	}
+	if inv.VoidedAt != nil {
+		out.VoidedAt = timestamppb.New(*inv.VoidedAt)
+	}
+
+	return out
+}
+
+func creditNoteToPB(cn models.CreditNote) *pb.CreditNote {
+	out := &pb.CreditNote{
+		Id:            int32(cn.ID),
+		Number:        cn.Number,
+		InvoiceNumber: cn.InvoiceNumber,
+		Amount:        int32(cn.Amount),
+		Currency:      cn.Currency,
+		Reason:        cn.Reason,
+		Status:        cn.Status,
+		Reference:     cn.Reference,
+		IssuedAt:      timestamppb.New(cn.IssuedAt),
+	}
+
+	if cn.SentAt != nil {
+		out.SentAt = timestamppb.New(*cn.SentAt)
+	}
+
+	return out
 }
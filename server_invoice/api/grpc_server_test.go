@@ -0,0 +1,272 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/pb"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// pdfStream collects the chunks DownloadInvoicePDF sends
+type pdfStream struct {
+	grpc.ServerStream
+	chunks [][]byte
+}
+
+func (s *pdfStream) Context() context.Context { return context.Background() }
+
+func (s *pdfStream) Send(chunk *pb.InvoicePDFChunk) error {
+	s.chunks = append(s.chunks, bytes.Clone(chunk.Data))
+	return nil
+}
+
+func TestGetInvoice(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode codes.Code
+	}{
+		{name: "found", wantCode: codes.OK},
+		{name: "not found", err: sql.ErrNoRows, wantCode: codes.NotFound},
+		{name: "database error", err: errors.New("db down"), wantCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := NewMockinvoiceStore(ctrl)
+			mockDB.EXPECT().GetInvoiceByNumber("INV-2026-000012").Return(models.Invoice{
+				ID:        7,
+				Number:    "INV-2026-000012",
+				Total:     2680,
+				LineItems: []models.InvoiceLineItem{{Description: "Yoyo", Quantity: 1, UnitPrice: 1500, Amount: 1500}},
+			}, tt.err)
+
+			g := NewGRPCServer(newTestServer(t, mockDB))
+			inv, err := g.GetInvoice(context.Background(), &pb.GetInvoiceRequest{Number: "INV-2026-000012"})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("expected %s, got %v", tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if inv.Number != "INV-2026-000012" || inv.Total != 2680 || len(inv.LineItems) != 1 {
+				t.Errorf("unexpected invoice %+v", inv)
+			}
+		})
+	}
+}
+
+func TestListInvoices(t *testing.T) {
+	tests := []struct {
+		name         string
+		req          *pb.ListInvoicesRequest
+		wantPageSize int
+		wantPage     int
+	}{
+		{name: "defaults", req: &pb.ListInvoicesRequest{Email: "jane@example.com"}, wantPageSize: 20, wantPage: 1},
+		{name: "page", req: &pb.ListInvoicesRequest{OrderId: 42, PageSize: 5, Page: 3}, wantPageSize: 5, wantPage: 3},
+		{name: "page size too large", req: &pb.ListInvoicesRequest{PageSize: 500}, wantPageSize: 20, wantPage: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := NewMockinvoiceStore(ctrl)
+			mockDB.EXPECT().GetInvoicesPaginated(tt.req.Email, int(tt.req.OrderId), tt.wantPageSize, tt.wantPage).
+				Return([]*models.Invoice{{Number: "INV-2026-000012"}}, 4, 61, nil)
+
+			g := NewGRPCServer(newTestServer(t, mockDB))
+			resp, err := g.ListInvoices(context.Background(), tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.CurrentPage != int32(tt.wantPage) || resp.PageSize != int32(tt.wantPageSize) ||
+				resp.LastPage != 4 || resp.TotalRecords != 61 || len(resp.Invoices) != 1 {
+				t.Errorf("unexpected response %+v", resp)
+			}
+		})
+	}
+}
+
+func TestListInvoicesError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockinvoiceStore(ctrl)
+	mockDB.EXPECT().GetInvoicesPaginated("", 0, 20, 1).Return(nil, 0, 0, errors.New("db down"))
+
+	g := NewGRPCServer(newTestServer(t, mockDB))
+	if _, err := g.ListInvoices(context.Background(), &pb.ListInvoicesRequest{}); status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal, got %v", err)
+	}
+}
+
+func TestResendInvoice(t *testing.T) {
+	tests := []struct {
+		name     string
+		inv      models.Invoice
+		to       string
+		setup    func(db *MockinvoiceStore)
+		wantCode codes.Code
+	}{
+		{
+			name: "to the customer",
+			inv:  models.Invoice{ID: 7, Number: "INV-2026-000012", Email: "jane@example.com", Status: models.InvoiceStatusSent},
+			setup: func(db *MockinvoiceStore) {
+				db.EXPECT().SetInvoicePDF(7, gomock.Any()).Return(nil)
+				db.EXPECT().InsertMailOutbox("jane@example.com", gomock.Any(), "invoice:7", gomock.Any()).Return(1, nil)
+			},
+		},
+		{
+			// a copy for someone else is not the invoice reaching the customer
+			name: "to another address",
+			inv:  models.Invoice{ID: 7, Number: "INV-2026-000012", Email: "jane@example.com", Status: models.InvoiceStatusIssued},
+			to:   "accounts@example.com",
+			setup: func(db *MockinvoiceStore) {
+				db.EXPECT().SetInvoicePDF(7, gomock.Any()).Return(nil)
+				db.EXPECT().InsertMailOutbox("accounts@example.com", gomock.Any(), "", gomock.Any()).Return(1, nil)
+			},
+		},
+		{
+			name:     "void",
+			inv:      models.Invoice{ID: 7, Number: "INV-2026-000012", Email: "jane@example.com", Status: models.InvoiceStatusVoid},
+			setup:    func(db *MockinvoiceStore) {},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "outbox unavailable",
+			inv:  models.Invoice{ID: 7, Number: "INV-2026-000012", Email: "jane@example.com", Status: models.InvoiceStatusIssued},
+			setup: func(db *MockinvoiceStore) {
+				db.EXPECT().SetInvoicePDF(7, gomock.Any()).Return(nil)
+				db.EXPECT().InsertMailOutbox(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(0, errors.New("db down"))
+			},
+			wantCode: codes.Unavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := NewMockinvoiceStore(ctrl)
+			mockDB.EXPECT().GetInvoiceByNumber(tt.inv.Number).Return(tt.inv, nil)
+			tt.setup(mockDB)
+
+			g := NewGRPCServer(newTestServer(t, mockDB))
+			_, err := g.ResendInvoice(context.Background(), &pb.ResendInvoiceRequest{Number: tt.inv.Number, Email: tt.to})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("expected %s, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestVoidInvoice(t *testing.T) {
+	lines := []models.InvoiceLineItem{{Description: "Yoyo", Quantity: 1, UnitPrice: 1500, Amount: 1500}}
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode codes.Code
+	}{
+		{name: "issued", wantCode: codes.OK},
+		{name: "already void", err: sql.ErrNoRows, wantCode: codes.FailedPrecondition},
+		{name: "database error", err: errors.New("db down"), wantCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := NewMockinvoiceStore(ctrl)
+			mockDB.EXPECT().GetInvoiceByNumber("INV-2026-000012").
+				Return(models.Invoice{ID: 7, Number: "INV-2026-000012", LineItems: lines}, nil)
+			mockDB.EXPECT().VoidInvoice(7, "duplicate").
+				Return(models.Invoice{ID: 7, Number: "INV-2026-000012", Status: models.InvoiceStatusVoid, VoidReason: "duplicate"}, tt.err)
+
+			g := NewGRPCServer(newTestServer(t, mockDB))
+			inv, err := g.VoidInvoice(context.Background(), &pb.VoidInvoiceRequest{Number: "INV-2026-000012", Reason: "duplicate"})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("expected %s, got %v", tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+
+			// the voided invoice keeps its lines
+			if inv.Status != models.InvoiceStatusVoid || inv.VoidReason != "duplicate" || len(inv.LineItems) != 1 {
+				t.Errorf("unexpected invoice %+v", inv)
+			}
+		})
+	}
+}
+
+func TestDownloadInvoicePDF(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockinvoiceStore(ctrl)
+	server := newTestServer(t, mockDB)
+
+	// larger than two chunks
+	pdf := bytes.Repeat([]byte("%PDF"), (2*pdfChunkSize+100)/4)
+	if err := server.store.Put(context.Background(), "2026/INV-2026-000012.pdf", bytes.NewReader(pdf), "application/pdf"); err != nil {
+		t.Fatal(err)
+	}
+
+	mockDB.EXPECT().GetInvoiceByNumber("INV-2026-000012").
+		Return(models.Invoice{ID: 7, Number: "INV-2026-000012", PDFLocation: "2026/INV-2026-000012.pdf"}, nil)
+
+	stream := &pdfStream{}
+	if err := NewGRPCServer(server).DownloadInvoicePDF(&pb.DownloadInvoicePDFRequest{Number: "INV-2026-000012"}, stream); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stream.chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(stream.chunks))
+	}
+	for _, chunk := range stream.chunks {
+		if len(chunk) > pdfChunkSize {
+			t.Errorf("expected chunks of at most %d bytes, got %d", pdfChunkSize, len(chunk))
+		}
+	}
+	if got := bytes.Join(stream.chunks, nil); !bytes.Equal(got, pdf) {
+		t.Errorf("expected the streamed pdf to match the stored one, got %d bytes", len(got))
+	}
+}
+
+func TestDownloadInvoicePDFGeneratesMissingPDF(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockinvoiceStore(ctrl)
+	mockDB.EXPECT().GetInvoiceByNumber("INV-2026-000012").
+		Return(models.Invoice{ID: 7, Number: "INV-2026-000012", Currency: "usd"}, nil)
+	mockDB.EXPECT().SetInvoicePDF(7, gomock.Any()).Return(nil)
+
+	stream := &pdfStream{}
+	if err := NewGRPCServer(newTestServer(t, mockDB)).DownloadInvoicePDF(&pb.DownloadInvoicePDFRequest{Number: "INV-2026-000012"}, stream); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := bytes.Join(stream.chunks, nil); !bytes.HasPrefix(got, []byte("%PDF-")) {
+		t.Errorf("expected a pdf, got %.20q", got)
+	}
+}
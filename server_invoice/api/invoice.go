@@ -1,26 +1,71 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/phpdave11/gofpdf"
-	"github.com/phpdave11/gofpdf/contrib/gofpdi"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/i18n"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/mailer"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/server_invoice/layout"
+	"github.com/LamThanhNguyen/yoyo-store-backend/server_invoice/ubl"
+	"github.com/rs/zerolog/log"
 )
 
-// Order describes the json payload received by this microservice
+// Order describes the json payload received by this microservice. When LineItems is empty,
+// the order is invoiced as a single line of Quantity x Product, for Amount in total.
 type Order struct {
-	ID        int       `json:"id"`
-	Quantity  int       `json:"quantity"`
-	Amount    int       `json:"amount"`
-	Product   string    `json:"product"`
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
+	ID            int                      `json:"id"`
+	Quantity      int                      `json:"quantity"`
+	Amount        int                      `json:"amount"`
+	Product       string                   `json:"product"`
+	LineItems     []models.InvoiceLineItem `json:"line_items"`
+	Currency      string                   `json:"currency"`
+	Discount      int                      `json:"discount"`
+	DiscountLabel string                   `json:"discount_label"`
+	Shipping      int                      `json:"shipping"`
+	TaxRateBps    int                      `json:"tax_rate_bps"`
+	CardBrand     string                   `json:"card_brand"`
+	LastFour      string                   `json:"last_four"`
+	FirstName     string                   `json:"first_name"`
+	LastName      string                   `json:"last_name"`
+	Email         string                   `json:"email"`
+	BuyerCountry  string                   `json:"buyer_country"`
+	Locale        string                   `json:"locale"`
+	CreatedAt     time.Time                `json:"created_at"`
 }
 
+// lineItems returns the lines to invoice for order
+func (order Order) lineItems() []models.InvoiceLineItem {
+	if len(order.LineItems) == 0 {
+		quantity := max(order.Quantity, 1)
+		return []models.InvoiceLineItem{{
+			Description: order.Product,
+			Quantity:    quantity,
+			UnitPrice:   order.Amount / quantity,
+			Amount:      order.Amount,
+		}}
+	}
+
+	lines := make([]models.InvoiceLineItem, len(order.LineItems))
+	for i, line := range order.LineItems {
+		if line.Amount == 0 {
+			line.Amount = line.Quantity * line.UnitPrice
+		}
+		lines[i] = line
+	}
+	return lines
+}
+
+// invoiceDueAfter is the payment term of new invoices
+const invoiceDueAfter = 30 * 24 * time.Hour
+
 // CreateAndSendInvoice creates an invoice as a PDF, and emails it to recipient
 func (server *Server) CreateAndSendInvoice(w http.ResponseWriter, r *http.Request) {
 	// receive json
@@ -32,72 +77,199 @@ func (server *Server) CreateAndSendInvoice(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// generate a pdf invoice
-	err = server.createInvoicePDF(order)
+	inv, err := server.issueInvoice(order)
 	if err != nil {
 		_ = server.badRequest(w, r, err)
 		return
 	}
 
-	// create mail attachment
-	attachments := []string{
-		fmt.Sprintf("./invoices/%d.pdf", order.ID),
+	// send reponse
+	var resp struct {
+		Error         bool   `json:"error"`
+		Message       string `json:"message"`
+		InvoiceNumber string `json:"invoice_number"`
+	}
+	resp.Error = false
+	resp.Message = fmt.Sprintf("Invoice %s created and sent to %s", inv.Number, inv.Email)
+	resp.InvoiceNumber = inv.Number
+	_ = server.writeJSON(w, http.StatusCreated, resp)
+}
+
+// issueInvoice records an invoice for order, generates its PDF and emails it to the customer.
+// It is safe to retry: an order only ever gets one invoice, and it is only sent once.
+func (server *Server) issueInvoice(order Order) (models.Invoice, error) {
+	issuedAt := time.Now()
+
+	inv := models.Invoice{
+		OrderID:       order.ID,
+		FirstName:     order.FirstName,
+		LastName:      order.LastName,
+		Email:         order.Email,
+		BuyerCountry:  strings.ToUpper(order.BuyerCountry),
+		Locale:        i18n.Match(order.Locale),
+		LineItems:     order.lineItems(),
+		Currency:      strings.ToLower(order.Currency),
+		Discount:      order.Discount,
+		DiscountLabel: order.DiscountLabel,
+		Shipping:      order.Shipping,
+		TaxRateBps:    order.TaxRateBps,
+		CardBrand:     order.CardBrand,
+		LastFour:      order.LastFour,
+		IssuedAt:      issuedAt,
+		DueAt:         issuedAt.Add(invoiceDueAfter),
+	}
+	if inv.Currency == "" {
+		inv.Currency = "usd"
 	}
+	inv.Product = inv.LineItems[0].Description
+	if len(inv.LineItems) > 1 {
+		inv.Product = fmt.Sprintf("%s and %d more", inv.Product, len(inv.LineItems)-1)
+	}
+	inv.CalculateTotals()
 
-	// send mail with attachment
-	err = server.SendMail("info@yoyo.com", order.Email, "Your invoice", "invoice", attachments, nil)
+	inv, err := server.DB.CreateInvoice(inv)
 	if err != nil {
-		_ = server.badRequest(w, r, err)
-		return
+		return inv, err
 	}
 
-	// send reponse
-	var resp struct {
-		Error   bool   `json:"error"`
-		Message string `json:"message"`
+	switch inv.Status {
+	case models.InvoiceStatusSent:
+		return inv, nil
+	case models.InvoiceStatusVoid:
+		return inv, fmt.Errorf("invoice %s is void", inv.Number)
 	}
-	resp.Error = false
-	resp.Message = fmt.Sprintf("Invoice %d.pdf created and sent to %s", order.ID, order.Email)
-	_ = server.writeJSON(w, http.StatusCreated, resp)
+
+	// a retry while the first email is still queued mustn't send a second one
+	if queued, err := server.DB.MailOutboxQueued(invoiceRef(inv.ID)); err != nil {
+		return inv, err
+	} else if queued {
+		return inv, nil
+	}
+
+	return server.sendInvoice(inv, inv.Email)
 }
 
-// createInvoicePDF generates a PDF version of the invoice
-func (server *Server) createInvoicePDF(order Order) error {
-	pdf := gofpdf.New("P", "mm", "Letter", "")
-	pdf.SetMargins(10, 13, 10)
-	pdf.SetAutoPageBreak(true, 0)
+// sendInvoice queues an invoice for the given address, generating its PDF first if needed.
+// The invoice is marked sent once the mail outbox delivers it to the customer; copies sent
+// to other addresses leave it as it is.
+func (server *Server) sendInvoice(inv models.Invoice, to string) (models.Invoice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-	importer := gofpdi.NewImporter()
+	if err := server.ensureInvoicePDF(ctx, &inv); err != nil {
+		return inv, err
+	}
 
-	t := importer.ImportPage(pdf, "./pdf-templates/invoice.pdf", 1, "/MediaBox")
+	// send mail with the pdf from storage attached
+	pdf, err := server.invoicePDF(ctx, inv)
+	if err != nil {
+		return inv, err
+	}
+
+	attachments := []mailer.Attachment{pdf}
+	if attach, _ := strconv.ParseBool(server.config.InvoiceUBLAttach); attach {
+		// customers who need the XML can still fetch it later, so don't hold up the PDF for it
+		if xml, err := server.invoiceXML(inv); err != nil {
+			log.Warn().Err(err).Str("number", inv.Number).Msg("sendInvoice")
+		} else {
+			attachments = append(attachments, xml)
+		}
+	}
 
-	pdf.AddPage()
-	importer.UseImportedTemplate(pdf, t, 0, 0, 215.9, 0)
+	var ref string
+	if strings.EqualFold(to, inv.Email) {
+		ref = invoiceRef(inv.ID)
+	}
 
-	// write info
-	pdf.SetY(50)
-	pdf.SetX(10)
-	pdf.SetFont("Times", "", 11)
-	pdf.CellFormat(97, 8, fmt.Sprintf("Attention: %s %s", order.FirstName, order.LastName), "", 0, "L", false, 0, "")
-	pdf.Ln(5)
-	pdf.CellFormat(97, 8, order.Email, "", 0, "L", false, 0, "")
-	pdf.Ln(5)
-	pdf.CellFormat(97, 8, order.CreatedAt.Format("2006-01-02"), "", 0, "L", false, 0, "")
+	c := i18n.For(inv.Locale)
+	if err := server.SendMail("info@yoyo.com", to, c.T("email.invoice.subject"), "invoice", inv.Locale, ref, attachments, inv); err != nil {
+		return inv, err
+	}
 
-	pdf.SetX(58)
-	pdf.SetY(93)
-	pdf.CellFormat(155, 8, order.Product, "", 0, "L", false, 0, "")
-	pdf.SetX(166)
-	pdf.CellFormat(20, 8, fmt.Sprintf("%d", order.Quantity), "", 0, "C", false, 0, "")
+	return inv, nil
+}
 
-	pdf.SetX(185)
-	pdf.CellFormat(20, 8, fmt.Sprintf("$%.2f", float32(order.Amount/100.0)), "", 0, "R", false, 0, "")
+// ensureInvoicePDF generates and stores the PDF of an invoice if it doesn't have one yet
+func (server *Server) ensureInvoicePDF(ctx context.Context, inv *models.Invoice) error {
+	if inv.PDFLocation != "" {
+		return nil
+	}
 
-	invoicePath := fmt.Sprintf("./invoices/%d.pdf", order.ID)
-	err := pdf.OutputFileAndClose(invoicePath)
+	location, err := server.createInvoicePDF(ctx, *inv)
 	if err != nil {
 		return err
 	}
 
+	if err := server.DB.SetInvoicePDF(inv.ID, location); err != nil {
+		return err
+	}
+	inv.PDFLocation = location
+
 	return nil
 }
+
+// invoicePDF reads the PDF of an invoice from storage
+func (server *Server) invoicePDF(ctx context.Context, inv models.Invoice) (mailer.Attachment, error) {
+	body, err := server.store.Get(ctx, inv.PDFLocation)
+	if err != nil {
+		return mailer.Attachment{}, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return mailer.Attachment{}, err
+	}
+
+	return mailer.Attachment{
+		Name:        fmt.Sprintf("%s.pdf", inv.Number),
+		ContentType: "application/pdf",
+		Data:        data,
+	}, nil
+}
+
+// invoiceXML returns the UBL XML version of an invoice. It fails if the invoice lacks
+// details EN 16931 requires, such as the customer's country.
+func (server *Server) invoiceXML(inv models.Invoice) (mailer.Attachment, error) {
+	data, err := ubl.Marshal(server.seller, inv)
+	if err != nil {
+		return mailer.Attachment{}, err
+	}
+
+	return mailer.Attachment{
+		Name:        fmt.Sprintf("%s.xml", inv.Number),
+		ContentType: "application/xml",
+		Data:        data,
+	}, nil
+}
+
+// createInvoicePDF generates a PDF version of the invoice, and returns its storage key.
+// With INVOICE_UBL_EMBED, it is a PDF/A-3 hybrid with the XML version embedded, when that
+// can be produced.
+func (server *Server) createInvoicePDF(ctx context.Context, inv models.Invoice) (string, error) {
+	var xml mailer.Attachment
+	if embed, _ := strconv.ParseBool(server.config.InvoiceUBLEmbed); embed {
+		var err error
+		if xml, err = server.invoiceXML(inv); err != nil {
+			log.Warn().Err(err).Str("number", inv.Number).Msg("createInvoicePDF")
+		}
+	}
+
+	var buf bytes.Buffer
+	var err error
+	if xml.Data != nil {
+		err = layout.RenderWithXML(&buf, server.seller, inv, xml.Name, xml.Data)
+	} else {
+		err = layout.Render(&buf, server.seller, inv)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	key := fmt.Sprintf("%d/%s.pdf", inv.IssuedAt.Year(), inv.Number)
+	if err := server.store.Put(ctx, key, &buf, "application/pdf"); err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/mailer"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/storage"
+	"github.com/LamThanhNguyen/yoyo-store-backend/server_invoice/layout"
+	"go.uber.org/mock/gomock"
+)
+
+// newTestServer returns a server with db, a local store in a temporary directory, and the
+// mail outbox queuing in db
+func newTestServer(t *testing.T, db invoiceStore) *Server {
+	t.Helper()
+
+	store, err := storage.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Server{
+		DB:        db,
+		store:     store,
+		seller:    layout.Seller{Name: "Yoyo Store", Country: "DE"},
+		mail:      mailer.NewOutbox(db, nil),
+		templates: mailer.NewTemplates(emailTemplateFS, "email-templates", nil),
+	}
+}
+
+func TestOrderLineItems(t *testing.T) {
+	tests := []struct {
+		name  string
+		order Order
+		want  []models.InvoiceLineItem
+	}{
+		{
+			name:  "single product",
+			order: Order{Product: "Yoyo", Quantity: 2, Amount: 3000},
+			want:  []models.InvoiceLineItem{{Description: "Yoyo", Quantity: 2, UnitPrice: 1500, Amount: 3000}},
+		},
+		{
+			name:  "no quantity",
+			order: Order{Product: "Bronze Plan", Amount: 2000},
+			want:  []models.InvoiceLineItem{{Description: "Bronze Plan", Quantity: 1, UnitPrice: 2000, Amount: 2000}},
+		},
+		{
+			name: "line items",
+			order: Order{Product: "ignored", Amount: 1, LineItems: []models.InvoiceLineItem{
+				{Description: "Yoyo", Quantity: 3, UnitPrice: 1000},
+				{Description: "Strings", Quantity: 1, UnitPrice: 500, Amount: 450},
+			}},
+			want: []models.InvoiceLineItem{
+				{Description: "Yoyo", Quantity: 3, UnitPrice: 1000, Amount: 3000},
+				{Description: "Strings", Quantity: 1, UnitPrice: 500, Amount: 450},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.order.lineItems()
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d lines, got %+v", len(tt.want), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("line %d: expected %+v, got %+v", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIssueInvoice(t *testing.T) {
+	order := Order{ID: 42, Product: "Yoyo", Quantity: 1, Amount: 1500, Email: "jane@example.com", Locale: "en"}
+
+	issued := func(status string) func(models.Invoice) (models.Invoice, error) {
+		return func(inv models.Invoice) (models.Invoice, error) {
+			inv.ID = 7
+			inv.Number = models.InvoiceNumber(inv.IssuedAt.Year(), 12)
+			inv.Status = status
+			return inv, nil
+		}
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(db *MockinvoiceStore)
+		wantErr bool
+		wantPDF bool
+	}{
+		{
+			name: "new invoice",
+			setup: func(db *MockinvoiceStore) {
+				db.EXPECT().CreateInvoice(gomock.Any()).DoAndReturn(issued(models.InvoiceStatusIssued))
+				db.EXPECT().MailOutboxQueued("invoice:7").Return(false, nil)
+				db.EXPECT().SetInvoicePDF(7, gomock.Any()).Return(nil)
+				db.EXPECT().InsertMailOutbox("jane@example.com", gomock.Any(), "invoice:7", gomock.Any()).Return(1, nil)
+			},
+			wantPDF: true,
+		},
+		{
+			name: "retry after it was sent",
+			setup: func(db *MockinvoiceStore) {
+				db.EXPECT().CreateInvoice(gomock.Any()).DoAndReturn(issued(models.InvoiceStatusSent))
+			},
+		},
+		{
+			name: "retry while its email is queued",
+			setup: func(db *MockinvoiceStore) {
+				db.EXPECT().CreateInvoice(gomock.Any()).DoAndReturn(issued(models.InvoiceStatusIssued))
+				db.EXPECT().MailOutboxQueued("invoice:7").Return(true, nil)
+			},
+		},
+		{
+			name: "void",
+			setup: func(db *MockinvoiceStore) {
+				db.EXPECT().CreateInvoice(gomock.Any()).DoAndReturn(issued(models.InvoiceStatusVoid))
+			},
+			wantErr: true,
+		},
+		{
+			name: "database error",
+			setup: func(db *MockinvoiceStore) {
+				db.EXPECT().CreateInvoice(gomock.Any()).Return(models.Invoice{}, errors.New("db down"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := NewMockinvoiceStore(ctrl)
+			tt.setup(mockDB)
+			server := newTestServer(t, mockDB)
+
+			inv, err := server.issueInvoice(order)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			year := time.Now().Year()
+			if want := models.InvoiceNumber(year, 12); inv.Number != want {
+				t.Errorf("expected invoice number %s, got %s", want, inv.Number)
+			}
+			if want := fmt.Sprintf("%d/%s.pdf", year, inv.Number); tt.wantPDF && inv.PDFLocation != want {
+				t.Errorf("expected the pdf to be stored as %s, got %q", want, inv.PDFLocation)
+			}
+		})
+	}
+}
+
+func TestIssueInvoiceRecordsTotals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockinvoiceStore(ctrl)
+	server := newTestServer(t, mockDB)
+
+	order := Order{
+		ID: 42,
+		LineItems: []models.InvoiceLineItem{
+			{Description: "Yoyo", Quantity: 2, UnitPrice: 1000},
+			{Description: "Strings", Quantity: 1, UnitPrice: 500},
+		},
+		Discount:     500,
+		Shipping:     300,
+		TaxRateBps:   1900,
+		Email:        "jane@example.com",
+		BuyerCountry: "de",
+		Locale:       "de-AT",
+	}
+
+	var created models.Invoice
+	mockDB.EXPECT().CreateInvoice(gomock.Any()).DoAndReturn(func(inv models.Invoice) (models.Invoice, error) {
+		created = inv
+		inv.Status = models.InvoiceStatusSent
+		return inv, nil
+	})
+
+	if _, err := server.issueInvoice(order); err != nil {
+		t.Fatal(err)
+	}
+
+	// 2500 less 500 discount, 19% tax on 2000, plus 300 shipping
+	if created.Subtotal != 2500 || created.Tax != 380 || created.Total != 2680 || created.Quantity != 3 {
+		t.Errorf("unexpected totals: subtotal %d, tax %d, total %d, quantity %d",
+			created.Subtotal, created.Tax, created.Total, created.Quantity)
+	}
+	if created.Product != "Yoyo and 1 more" {
+		t.Errorf("expected the product to summarise the lines, got %q", created.Product)
+	}
+	if created.Currency != "usd" || created.BuyerCountry != "DE" || created.Locale != "de" {
+		t.Errorf("expected usd, DE and de, got %s, %s and %s", created.Currency, created.BuyerCountry, created.Locale)
+	}
+	if got := created.DueAt.Sub(created.IssuedAt); got != invoiceDueAfter {
+		t.Errorf("expected the invoice to be due after %s, got %s", invoiceDueAfter, got)
+	}
+}
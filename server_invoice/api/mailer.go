@@ -1,99 +1,80 @@
 package api
 
 import (
-	"bytes"
+	"context"
 	"embed"
 	"fmt"
-	"html/template"
 	"strconv"
-	"time"
+	"strings"
 
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/mailer"
 	"github.com/rs/zerolog/log"
-
-	mail "github.com/xhit/go-simple-mail/v2"
 )
 
 //go:embed email-templates
 var emailTemplateFS embed.FS
 
+// SendMail renders tmpl and queues it. ref says what the email is about, for markDelivered;
+// it may be empty.
 func (server *Server) SendMail(
 	from,
 	to,
 	subject,
-	tmpl string,
-	attachments []string,
+	tmpl,
+	locale,
+	ref string,
+	attachments []mailer.Attachment,
 	data interface{},
 ) error {
-	templateToRender := fmt.Sprintf("email-templates/%s.html.tmpl", tmpl)
-
-	t, err := template.New("email-html").ParseFS(emailTemplateFS, templateToRender)
+	formattedMessage, plainMessage, err := server.templates.Render(tmpl, locale, data)
 	if err != nil {
 		log.Error().Err(err).Msg("SendMail")
 		return err
 	}
 
-	var tpl bytes.Buffer
-	if err = t.ExecuteTemplate(&tpl, "body", data); err != nil {
-		log.Error().Err(err).Msg("SendMail")
-		return err
+	// queue the mail; the dispatcher sends it, retrying while the transport is down
+	msg := mailer.Message{
+		From:        from,
+		To:          to,
+		Subject:     subject,
+		HTML:        formattedMessage,
+		Plain:       plainMessage,
+		Attachments: attachments,
+		Ref:         ref,
 	}
 
-	formattedMessage := tpl.String()
-
-	templateToRender = fmt.Sprintf("email-templates/%s.plain.tmpl", tmpl)
-	t, err = template.New("email-plain").ParseFS(emailTemplateFS, templateToRender)
-	if err != nil {
+	if err := server.mail.Send(context.Background(), msg); err != nil {
 		log.Error().Err(err).Msg("SendMail")
 		return err
 	}
 
-	if err = t.ExecuteTemplate(&tpl, "body", data); err != nil {
-		log.Error().Err(err).Msg("SendMail")
-		return err
-	}
+	return nil
+}
 
-	plainMessage := tpl.String()
+// RunMailDispatcher sends queued mail until ctx is done
+func (server *Server) RunMailDispatcher(ctx context.Context) error {
+	return server.mail.Run(ctx)
+}
 
-	// send the mail
-	mail_server := mail.NewSMTPClient()
-	mail_server.Host = server.config.SmtpHost
-	mail_server.Port, err = strconv.Atoi(server.config.SmtpPort)
-	if err != nil {
-		return err
-	}
-	mail_server.Username = server.config.SmtpUsername
-	mail_server.Password = server.config.SmtpPassword
-	mail_server.Encryption = mail.EncryptionTLS
-	mail_server.KeepAlive = false
-	mail_server.ConnectTimeout = 30 * time.Second
-	mail_server.SendTimeout = 30 * time.Second
+// invoiceRef and creditNoteRef are the refs of the emails sending an invoice and a credit note
+func invoiceRef(id int) string    { return fmt.Sprintf("invoice:%d", id) }
+func creditNoteRef(id int) string { return fmt.Sprintf("credit_note:%d", id) }
 
-	smtpClient, err := mail_server.Connect()
+// markDelivered marks the invoice or credit note an email was about as sent, once the mail
+// outbox has delivered it
+func (server *Server) markDelivered(ref string) error {
+	kind, v, _ := strings.Cut(ref, ":")
+	id, err := strconv.Atoi(v)
 	if err != nil {
-		return err
-	}
-
-	email := mail.NewMSG()
-	email.SetFrom(from).
-		AddTo(to).
-		SetSubject(subject)
-
-	email.SetBody(mail.TextHTML, formattedMessage)
-	email.AddAlternative(mail.TextPlain, plainMessage)
-
-	if len(attachments) > 0 {
-		for _, x := range attachments {
-			email.AddAttachment(x)
-		}
+		return fmt.Errorf("bad mail ref %q", ref)
 	}
 
-	err = email.Send(smtpClient)
-	if err != nil {
-		log.Error().Err(err).Msg("SendMail")
-		return err
+	switch kind {
+	case "invoice":
+		return server.DB.MarkInvoiceSent(id)
+	case "credit_note":
+		return server.DB.MarkCreditNoteSent(id)
 	}
 
-	log.Info().Msg("send email")
-
-	return nil
+	return fmt.Errorf("unknown mail ref %q", ref)
 }
@@ -0,0 +1,292 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/LamThanhNguyen/yoyo-store-backend/server_invoice/api (interfaces: invoiceStore)
+//
+// Generated by this command:
+//
+//	mockgen -package api -destination server_invoice/api/mock_interfaces_test.go github.com/LamThanhNguyen/yoyo-store-backend/server_invoice/api invoiceStore
+//
+
+// Package api is a generated GoMock package.
+package api
+
+import (
+	reflect "reflect"
+	time "time"
+
+	models "github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockinvoiceStore is a mock of invoiceStore interface.
+type MockinvoiceStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockinvoiceStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockinvoiceStoreMockRecorder is the mock recorder for MockinvoiceStore.
+type MockinvoiceStoreMockRecorder struct {
+	mock *MockinvoiceStore
+}
+
+// NewMockinvoiceStore creates a new mock instance.
+func NewMockinvoiceStore(ctrl *gomock.Controller) *MockinvoiceStore {
+	mock := &MockinvoiceStore{ctrl: ctrl}
+	mock.recorder = &MockinvoiceStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockinvoiceStore) EXPECT() *MockinvoiceStoreMockRecorder {
+	return m.recorder
+}
+
+// ClaimMailOutbox mocks base method.
+func (m *MockinvoiceStore) ClaimMailOutbox(limit int, lease time.Duration) ([]*models.MailOutboxEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimMailOutbox", limit, lease)
+	ret0, _ := ret[0].([]*models.MailOutboxEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimMailOutbox indicates an expected call of ClaimMailOutbox.
+func (mr *MockinvoiceStoreMockRecorder) ClaimMailOutbox(limit, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimMailOutbox", reflect.TypeOf((*MockinvoiceStore)(nil).ClaimMailOutbox), limit, lease)
+}
+
+// CreateCreditNote mocks base method.
+func (m *MockinvoiceStore) CreateCreditNote(cn models.CreditNote) (models.CreditNote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCreditNote", cn)
+	ret0, _ := ret[0].(models.CreditNote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCreditNote indicates an expected call of CreateCreditNote.
+func (mr *MockinvoiceStoreMockRecorder) CreateCreditNote(cn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCreditNote", reflect.TypeOf((*MockinvoiceStore)(nil).CreateCreditNote), cn)
+}
+
+// CreateInvoice mocks base method.
+func (m *MockinvoiceStore) CreateInvoice(inv models.Invoice) (models.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInvoice", inv)
+	ret0, _ := ret[0].(models.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInvoice indicates an expected call of CreateInvoice.
+func (mr *MockinvoiceStoreMockRecorder) CreateInvoice(inv any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvoice", reflect.TypeOf((*MockinvoiceStore)(nil).CreateInvoice), inv)
+}
+
+// DeadLetterMailOutbox mocks base method.
+func (m *MockinvoiceStore) DeadLetterMailOutbox(id int, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeadLetterMailOutbox", id, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeadLetterMailOutbox indicates an expected call of DeadLetterMailOutbox.
+func (mr *MockinvoiceStoreMockRecorder) DeadLetterMailOutbox(id, lastError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeadLetterMailOutbox", reflect.TypeOf((*MockinvoiceStore)(nil).DeadLetterMailOutbox), id, lastError)
+}
+
+// GetInvoiceByNumber mocks base method.
+func (m *MockinvoiceStore) GetInvoiceByNumber(number string) (models.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvoiceByNumber", number)
+	ret0, _ := ret[0].(models.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvoiceByNumber indicates an expected call of GetInvoiceByNumber.
+func (mr *MockinvoiceStoreMockRecorder) GetInvoiceByNumber(number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvoiceByNumber", reflect.TypeOf((*MockinvoiceStore)(nil).GetInvoiceByNumber), number)
+}
+
+// GetInvoiceByOrderID mocks base method.
+func (m *MockinvoiceStore) GetInvoiceByOrderID(orderID int) (models.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvoiceByOrderID", orderID)
+	ret0, _ := ret[0].(models.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvoiceByOrderID indicates an expected call of GetInvoiceByOrderID.
+func (mr *MockinvoiceStoreMockRecorder) GetInvoiceByOrderID(orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvoiceByOrderID", reflect.TypeOf((*MockinvoiceStore)(nil).GetInvoiceByOrderID), orderID)
+}
+
+// GetInvoicesPaginated mocks base method.
+func (m *MockinvoiceStore) GetInvoicesPaginated(email string, orderID, pageSize, page int) ([]*models.Invoice, int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvoicesPaginated", email, orderID, pageSize, page)
+	ret0, _ := ret[0].([]*models.Invoice)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(int)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetInvoicesPaginated indicates an expected call of GetInvoicesPaginated.
+func (mr *MockinvoiceStoreMockRecorder) GetInvoicesPaginated(email, orderID, pageSize, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvoicesPaginated", reflect.TypeOf((*MockinvoiceStore)(nil).GetInvoicesPaginated), email, orderID, pageSize, page)
+}
+
+// InsertMailOutbox mocks base method.
+func (m *MockinvoiceStore) InsertMailOutbox(recipient, subject, ref string, payload []byte) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertMailOutbox", recipient, subject, ref, payload)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertMailOutbox indicates an expected call of InsertMailOutbox.
+func (mr *MockinvoiceStoreMockRecorder) InsertMailOutbox(recipient, subject, ref, payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertMailOutbox", reflect.TypeOf((*MockinvoiceStore)(nil).InsertMailOutbox), recipient, subject, ref, payload)
+}
+
+// MailOutboxQueued mocks base method.
+func (m *MockinvoiceStore) MailOutboxQueued(ref string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MailOutboxQueued", ref)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MailOutboxQueued indicates an expected call of MailOutboxQueued.
+func (mr *MockinvoiceStoreMockRecorder) MailOutboxQueued(ref any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MailOutboxQueued", reflect.TypeOf((*MockinvoiceStore)(nil).MailOutboxQueued), ref)
+}
+
+// MarkCreditNoteSent mocks base method.
+func (m *MockinvoiceStore) MarkCreditNoteSent(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkCreditNoteSent", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkCreditNoteSent indicates an expected call of MarkCreditNoteSent.
+func (mr *MockinvoiceStoreMockRecorder) MarkCreditNoteSent(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkCreditNoteSent", reflect.TypeOf((*MockinvoiceStore)(nil).MarkCreditNoteSent), id)
+}
+
+// MarkInvoiceSent mocks base method.
+func (m *MockinvoiceStore) MarkInvoiceSent(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkInvoiceSent", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkInvoiceSent indicates an expected call of MarkInvoiceSent.
+func (mr *MockinvoiceStoreMockRecorder) MarkInvoiceSent(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkInvoiceSent", reflect.TypeOf((*MockinvoiceStore)(nil).MarkInvoiceSent), id)
+}
+
+// MarkMailOutboxDelivered mocks base method.
+func (m *MockinvoiceStore) MarkMailOutboxDelivered(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkMailOutboxDelivered", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkMailOutboxDelivered indicates an expected call of MarkMailOutboxDelivered.
+func (mr *MockinvoiceStoreMockRecorder) MarkMailOutboxDelivered(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkMailOutboxDelivered", reflect.TypeOf((*MockinvoiceStore)(nil).MarkMailOutboxDelivered), id)
+}
+
+// PurgeMailOutbox mocks base method.
+func (m *MockinvoiceStore) PurgeMailOutbox(before time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeMailOutbox", before)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeMailOutbox indicates an expected call of PurgeMailOutbox.
+func (mr *MockinvoiceStoreMockRecorder) PurgeMailOutbox(before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeMailOutbox", reflect.TypeOf((*MockinvoiceStore)(nil).PurgeMailOutbox), before)
+}
+
+// RescheduleMailOutbox mocks base method.
+func (m *MockinvoiceStore) RescheduleMailOutbox(id int, lastError string, nextAttemptAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RescheduleMailOutbox", id, lastError, nextAttemptAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RescheduleMailOutbox indicates an expected call of RescheduleMailOutbox.
+func (mr *MockinvoiceStoreMockRecorder) RescheduleMailOutbox(id, lastError, nextAttemptAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RescheduleMailOutbox", reflect.TypeOf((*MockinvoiceStore)(nil).RescheduleMailOutbox), id, lastError, nextAttemptAt)
+}
+
+// SetCreditNotePDF mocks base method.
+func (m *MockinvoiceStore) SetCreditNotePDF(id int, location string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCreditNotePDF", id, location)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCreditNotePDF indicates an expected call of SetCreditNotePDF.
+func (mr *MockinvoiceStoreMockRecorder) SetCreditNotePDF(id, location any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCreditNotePDF", reflect.TypeOf((*MockinvoiceStore)(nil).SetCreditNotePDF), id, location)
+}
+
+// SetInvoicePDF mocks base method.
+func (m *MockinvoiceStore) SetInvoicePDF(id int, location string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetInvoicePDF", id, location)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetInvoicePDF indicates an expected call of SetInvoicePDF.
+func (mr *MockinvoiceStoreMockRecorder) SetInvoicePDF(id, location any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInvoicePDF", reflect.TypeOf((*MockinvoiceStore)(nil).SetInvoicePDF), id, location)
+}
+
+// VoidInvoice mocks base method.
+func (m *MockinvoiceStore) VoidInvoice(id int, reason string) (models.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoidInvoice", id, reason)
+	ret0, _ := ret[0].(models.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoidInvoice indicates an expected call of VoidInvoice.
+func (mr *MockinvoiceStoreMockRecorder) VoidInvoice(id, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidInvoice", reflect.TypeOf((*MockinvoiceStore)(nil).VoidInvoice), id, reason)
+}
@@ -6,26 +6,96 @@ import (
 	"io"
 	"maps"
 	"net/http"
-	"os"
+	"strings"
 
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/mailer"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/storage"
+	"github.com/LamThanhNguyen/yoyo-store-backend/server_invoice/layout"
 	"github.com/LamThanhNguyen/yoyo-store-backend/server_invoice/util"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
-	"github.com/rs/zerolog/log"
 )
 
+// invoiceStore is the database behind the invoice service, including its mail outbox.
+// *models.DBModel implements it. Having this interface allows the use of gomock in tests.
+type invoiceStore interface {
+	mailer.OutboxStore
+	MailOutboxQueued(ref string) (bool, error)
+	CreateInvoice(inv models.Invoice) (models.Invoice, error)
+	GetInvoiceByNumber(number string) (models.Invoice, error)
+	GetInvoiceByOrderID(orderID int) (models.Invoice, error)
+	GetInvoicesPaginated(email string, orderID, pageSize, page int) ([]*models.Invoice, int, int, error)
+	VoidInvoice(id int, reason string) (models.Invoice, error)
+	SetInvoicePDF(id int, location string) error
+	MarkInvoiceSent(id int) error
+	CreateCreditNote(cn models.CreditNote) (models.CreditNote, error)
+	SetCreditNotePDF(id int, location string) error
+	MarkCreditNoteSent(id int) error
+}
+
 type Server struct {
-	config util.Config
-	router http.Handler
+	config    util.Config
+	DB        invoiceStore
+	store     storage.Store
+	seller    layout.Seller
+	mail      *mailer.Outbox
+	templates *mailer.Templates
+	router    http.Handler
 }
 
 func NewServer(
 	config util.Config,
+	db *models.DBModel,
+	store storage.Store,
 ) (*Server, error) {
+	transport, err := mailer.New(mailer.Config{
+		Driver:       config.MailDriver,
+		Dir:          config.MailDir,
+		SMTPHost:     config.SmtpHost,
+		SMTPPort:     config.SmtpPort,
+		SMTPUsername: config.SmtpUsername,
+		SMTPPassword: config.SmtpPassword,
+		SMTPPoolSize: config.SmtpPoolSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	server := &Server{
+		config:    config,
+		DB:        db,
+		store:     store,
+		seller:    sellerFromConfig(config),
+		mail:      mailer.NewOutbox(db, transport),
+		templates: mailer.NewTemplates(emailTemplateFS, "email-templates", db),
+	}
+	server.mail.OnDelivered(server.markDelivered)
 
-	return &Server{
-		config: config,
-	}, nil
+	return server, nil
+}
+
+// sellerFromConfig returns the seller printed on invoices. INVOICE_SELLER_ADDRESS holds the
+// address lines separated by semicolons.
+func sellerFromConfig(config util.Config) layout.Seller {
+	seller := layout.Seller{
+		Name:    config.InvoiceSellerName,
+		Email:   config.InvoiceSellerEmail,
+		Phone:   config.InvoiceSellerPhone,
+		TaxID:   config.InvoiceSellerTaxID,
+		Country: config.InvoiceSellerCountry,
+	}
+	if seller.Name == "" {
+		seller.Name = "Yoyo Store"
+	}
+
+	for _, line := range strings.Split(config.InvoiceSellerAddress, ";") {
+		if line = strings.TrimSpace(line); line != "" {
+			seller.Address = append(seller.Address, line)
+		}
+	}
+
+	return seller
 }
 
 func (server *Server) SetupRouter() {
@@ -126,18 +196,6 @@ func (server *Server) badRequest(
 	return nil
 }
 
-func (server *Server) CreateDirIfNotExist(path string) error {
-	const mode = 0755
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		err := os.Mkdir(path, mode)
-		if err != nil {
-			log.Error().Err(err)
-			return err
-		}
-	}
-	return nil
-}
-
 func (server *Server) handleHealthCheck(w http.ResponseWriter, r *http.Request) {
 	var data = map[string]string{"status": "ok"}
 	_ = server.writeJSON(w, http.StatusOK, data)
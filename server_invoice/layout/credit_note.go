@@ -0,0 +1,76 @@
+package layout
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/i18n"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/phpdave11/gofpdf"
+)
+
+// RenderCreditNote writes cn, a credit against inv, as a PDF document to w
+func RenderCreditNote(w io.Writer, seller Seller, inv models.Invoice, cn models.CreditNote) error {
+	pdf := renderCreditNote(seller, inv, cn)
+	return pdf.Output(w)
+}
+
+func renderCreditNote(seller Seller, inv models.Invoice, cn models.CreditNote) *gofpdf.Fpdf {
+	c := i18n.For(inv.Locale)
+	d := newDocument(seller, inv, "credit_note", cn.Number, [][2]string{
+		{c.T("credit_note.number"), cn.Number},
+		{c.T("invoice.issued"), c.Date(cn.IssuedAt)},
+		{c.T("credit_note.original_invoice"), inv.Number},
+		{c.T("invoice.order"), strconv.Itoa(inv.OrderID)},
+	})
+
+	d.pdf.AddPage()
+	d.billTo()
+	d.credit(cn)
+
+	return d.pdf
+}
+
+// credit prints the credited amount, the reason for it, and where the money was refunded to
+func (d *document) credit(cn models.CreditNote) {
+	pdf := d.pdf
+
+	description := d.c.T("credit_note.description", d.inv.Number, d.c.Date(d.inv.IssuedAt))
+
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(235, 235, 235)
+	pdf.CellFormat(d.width-amountWidth, 7, d.tr(d.c.T("invoice.description")), "", 0, "L", true, 0, "")
+	pdf.CellFormat(amountWidth, 7, d.tr(d.c.T("invoice.amount")), "", 1, "R", true, 0, "")
+
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.Ln(rowPadding)
+	pdf.CellFormat(d.width-amountWidth, lineHeight, d.tr(description), "", 0, "L", false, 0, "")
+	pdf.CellFormat(amountWidth, lineHeight, d.tr(d.c.Money(-cn.Amount, cn.Currency)), "", 1, "R", false, 0, "")
+	if cn.Reason != "" {
+		pdf.SetTextColor(90, 90, 90)
+		pdf.MultiCell(d.width-amountWidth, lineHeight, d.tr(d.c.T("credit_note.reason", cn.Reason)), "", "L", false)
+		pdf.SetTextColor(0, 0, 0)
+	}
+	pdf.Ln(rowPadding)
+
+	labelWidth := priceWidth + qtyWidth
+	left := marginLeft + d.width - labelWidth - amountWidth
+
+	pdf.Ln(3)
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.SetX(left)
+	pdf.CellFormat(labelWidth, 6, d.tr(d.c.T("credit_note.original_total")), "", 0, "R", false, 0, "")
+	pdf.CellFormat(amountWidth, 6, d.tr(d.c.Money(d.inv.Total, d.inv.Currency)), "", 1, "R", false, 0, "")
+
+	pdf.SetX(left)
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(labelWidth, 8, d.tr(d.c.T("credit_note.total")), "T", 0, "R", false, 0, "")
+	pdf.CellFormat(amountWidth, 8, d.tr(d.c.Money(cn.Amount, cn.Currency)), "T", 1, "R", false, 0, "")
+
+	if d.inv.LastFour != "" {
+		pdf.Ln(6)
+		pdf.SetFont("Helvetica", "", 9)
+		refunded := d.c.T("credit_note.refunded_to", d.cardBrand(), d.inv.LastFour)
+		pdf.CellFormat(d.width, 5, d.tr(refunded), "", 1, "L", false, 0, "")
+	}
+}
@@ -0,0 +1,347 @@
+// Package layout renders invoices and credit notes as PDF documents. Line items flow over as
+// many pages as they need, with the seller, document number and table header repeated on
+// every page.
+package layout
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/i18n"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/phpdave11/gofpdf"
+)
+
+// Seller is the business issuing the invoices
+type Seller struct {
+	Name    string
+	Address []string
+	Email   string
+	Phone   string
+	TaxID   string
+	Country string // ISO 3166-1 alpha-2 code, for e-invoices
+}
+
+// page geometry, in mm on US Letter paper
+const (
+	marginLeft   = 15.0
+	marginTop    = 15.0
+	marginRight  = 15.0
+	marginBottom = 20.0
+	lineHeight   = 5.0
+	rowPadding   = 1.5
+
+	qtyWidth    = 18.0
+	priceWidth  = 32.0
+	amountWidth = 32.0
+)
+
+// Render writes inv as a PDF document to w, in the language of inv.Locale
+func Render(w io.Writer, seller Seller, inv models.Invoice) error {
+	pdf := render(seller, inv, false)
+	return pdf.Output(w)
+}
+
+type document struct {
+	pdf    *gofpdf.Fpdf
+	tr     func(string) string
+	c      *i18n.Catalog
+	seller Seller
+	inv    models.Invoice
+
+	kind    string      // the catalog prefix of the document, e.g. "invoice"
+	number  string      // the number of the document being rendered
+	details [][2]string // label and value pairs printed under the title
+
+	width        float64 // printable width
+	descWidth    float64
+	inLineItems  bool // whether a page break should repeat the line item header
+	pageBreakAtY float64
+}
+
+// render lays out inv; with embedFonts, in fonts embedded in the document
+func render(seller Seller, inv models.Invoice, embedFonts bool) *gofpdf.Fpdf {
+	c := i18n.For(inv.Locale)
+	d := newDocument(seller, inv, "invoice", inv.Number, [][2]string{
+		{c.T("invoice.number"), inv.Number},
+		{c.T("invoice.issued"), c.Date(inv.IssuedAt)},
+		{c.T("invoice.due"), c.Date(inv.DueAt)},
+		{c.T("invoice.order"), strconv.Itoa(inv.OrderID)},
+	})
+	if embedFonts {
+		d.embedFonts()
+	}
+
+	d.pdf.AddPage()
+	d.billTo()
+	d.lineItems()
+	d.totals()
+
+	return d.pdf
+}
+
+// newDocument sets up a document of the given kind for the customer of inv, in their language
+func newDocument(seller Seller, inv models.Invoice, kind, number string, details [][2]string) *document {
+	c := i18n.For(inv.Locale)
+
+	pdf := gofpdf.New("P", "mm", "Letter", "")
+	pdf.SetMargins(marginLeft, marginTop, marginRight)
+	pdf.SetAutoPageBreak(true, marginBottom)
+	pdf.AliasNbPages("")
+	pdf.SetTitle(fmt.Sprintf("%s %s", c.T(kind+".title"), number), true)
+	pdf.SetAuthor(seller.Name, true)
+
+	pageWidth, pageHeight := pdf.GetPageSize()
+
+	d := &document{
+		pdf:          pdf,
+		tr:           pdf.UnicodeTranslatorFromDescriptor(""),
+		c:            c,
+		seller:       seller,
+		inv:          inv,
+		kind:         kind,
+		number:       number,
+		details:      details,
+		width:        pageWidth - marginLeft - marginRight,
+		pageBreakAtY: pageHeight - marginBottom,
+	}
+	d.descWidth = d.width - qtyWidth - priceWidth - amountWidth
+
+	pdf.SetHeaderFuncMode(d.header, true)
+	pdf.SetFooterFunc(d.footer)
+
+	return d
+}
+
+// header prints the seller and document details on the first page, and a short reminder of
+// them on the following pages
+func (d *document) header() {
+	pdf := d.pdf
+
+	if pdf.PageNo() == 1 {
+		pdf.SetFont("Helvetica", "B", 16)
+		pdf.CellFormat(d.width/2, 8, d.tr(d.seller.Name), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "B", 20)
+		pdf.CellFormat(d.width/2, 8, d.tr(strings.ToUpper(d.c.T(d.kind+".title"))), "", 1, "R", false, 0, "")
+
+		top := pdf.GetY() + 1
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.SetY(top)
+		for _, line := range d.sellerLines() {
+			pdf.CellFormat(d.width/2, 4.5, d.tr(line), "", 2, "L", false, 0, "")
+		}
+		sellerBottom := pdf.GetY()
+
+		pdf.SetXY(marginLeft+d.width/2, top)
+		for _, row := range d.details {
+			pdf.SetX(marginLeft + d.width/2)
+			pdf.SetFont("Helvetica", "", 9)
+			pdf.CellFormat(d.width/4, 4.5, d.tr(row[0]), "", 0, "R", false, 0, "")
+			pdf.SetFont("Helvetica", "B", 9)
+			pdf.CellFormat(d.width/4, 4.5, d.tr(row[1]), "", 1, "R", false, 0, "")
+		}
+
+		if d.kind == "invoice" && d.inv.Status == models.InvoiceStatusVoid {
+			pdf.SetX(marginLeft + d.width/2)
+			pdf.SetFont("Helvetica", "B", 12)
+			pdf.SetTextColor(200, 0, 0)
+			pdf.CellFormat(d.width/2, 7, d.tr(d.c.T("invoice.void")), "", 1, "R", false, 0, "")
+			pdf.SetTextColor(0, 0, 0)
+		}
+
+		pdf.SetY(max(pdf.GetY(), sellerBottom) + 4)
+	} else {
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.CellFormat(d.width/2, 5, d.tr(d.seller.Name), "", 0, "L", false, 0, "")
+		pdf.CellFormat(d.width/2, 5, d.tr(d.c.T("invoice.continued", d.c.T(d.kind+".title"), d.number)), "", 1, "R", false, 0, "")
+		pdf.Ln(3)
+	}
+
+	// SetHeaderFuncMode moves back to the top margin after the header, so shift the margin
+	// down to where the header ended
+	top := pdf.GetY()
+	if d.inLineItems {
+		d.lineItemHeader()
+		top = pdf.GetY()
+	}
+	pdf.SetTopMargin(top)
+}
+
+func (d *document) footer() {
+	pdf := d.pdf
+	pdf.SetTopMargin(marginTop)
+	pdf.SetY(-marginBottom + 6)
+	pdf.SetFont("Helvetica", "", 8)
+	pdf.SetTextColor(110, 110, 110)
+	pdf.CellFormat(d.width, 4, d.tr(d.c.T("invoice.page", pdf.PageNo(), "{nb}")), "", 0, "C", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+}
+
+func (d *document) sellerLines() []string {
+	lines := append([]string{}, d.seller.Address...)
+	if d.seller.Email != "" {
+		lines = append(lines, d.seller.Email)
+	}
+	if d.seller.Phone != "" {
+		lines = append(lines, d.seller.Phone)
+	}
+	if d.seller.TaxID != "" {
+		lines = append(lines, d.c.T("invoice.tax_id", d.seller.TaxID))
+	}
+	return lines
+}
+
+func (d *document) billTo() {
+	pdf := d.pdf
+
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.CellFormat(d.width, 5, d.tr(d.c.T("invoice.bill_to")), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(d.width, 5, d.tr(strings.TrimSpace(d.inv.FirstName+" "+d.inv.LastName)), "", 1, "L", false, 0, "")
+	pdf.CellFormat(d.width, 5, d.tr(d.inv.Email), "", 1, "L", false, 0, "")
+	pdf.Ln(6)
+}
+
+func (d *document) lineItemHeader() {
+	pdf := d.pdf
+
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(235, 235, 235)
+	pdf.CellFormat(d.descWidth, 7, d.tr(d.c.T("invoice.description")), "", 0, "L", true, 0, "")
+	pdf.CellFormat(qtyWidth, 7, d.tr(d.c.T("invoice.quantity")), "", 0, "C", true, 0, "")
+	pdf.CellFormat(priceWidth, 7, d.tr(d.c.T("invoice.unit_price")), "", 0, "R", true, 0, "")
+	pdf.CellFormat(amountWidth, 7, d.tr(d.c.T("invoice.amount")), "", 1, "R", true, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+}
+
+// lineItems prints one row per line item. Long descriptions wrap, and a row that doesn't fit
+// on the page is moved to the next one as a whole.
+func (d *document) lineItems() {
+	pdf := d.pdf
+
+	d.lineItemHeader()
+	d.inLineItems = true
+
+	for _, line := range d.inv.LineItems {
+		wrapped := pdf.SplitLines([]byte(d.tr(line.Description)), d.descWidth)
+		if len(wrapped) == 0 {
+			wrapped = [][]byte{nil}
+		}
+		height := float64(len(wrapped))*lineHeight + 2*rowPadding
+
+		if pdf.GetY()+height > d.pageBreakAtY {
+			pdf.AddPage()
+		}
+
+		top := pdf.GetY()
+		pdf.SetY(top + rowPadding)
+		for _, text := range wrapped {
+			pdf.CellFormat(d.descWidth, lineHeight, string(text), "", 2, "L", false, 0, "")
+		}
+
+		pdf.SetXY(marginLeft+d.descWidth, top+rowPadding)
+		pdf.CellFormat(qtyWidth, lineHeight, strconv.Itoa(line.Quantity), "", 0, "C", false, 0, "")
+		pdf.CellFormat(priceWidth, lineHeight, d.tr(d.c.Money(line.UnitPrice, d.inv.Currency)), "", 0, "R", false, 0, "")
+		pdf.CellFormat(amountWidth, lineHeight, d.tr(d.c.Money(line.Amount, d.inv.Currency)), "", 0, "R", false, 0, "")
+
+		pdf.SetDrawColor(220, 220, 220)
+		pdf.Line(marginLeft, top+height, marginLeft+d.width, top+height)
+		pdf.SetDrawColor(0, 0, 0)
+		pdf.SetXY(marginLeft, top+height)
+	}
+
+	d.inLineItems = false
+}
+
+// totals prints the subtotal, discount, shipping, tax and total rows, then how the invoice
+// was paid. The block is kept together on one page.
+func (d *document) totals() {
+	pdf := d.pdf
+	inv := d.inv
+
+	type row struct {
+		label  string
+		amount int
+	}
+	rows := []row{{d.c.T("invoice.subtotal"), inv.Subtotal}}
+	if inv.Discount != 0 {
+		label := d.c.T("invoice.discount")
+		if inv.DiscountLabel != "" {
+			label = d.c.T("invoice.discount_code", inv.DiscountLabel)
+		}
+		rows = append(rows, row{label, -inv.Discount})
+	}
+	if inv.Shipping != 0 {
+		rows = append(rows, row{d.c.T("invoice.shipping"), inv.Shipping})
+	}
+	if inv.TaxRateBps != 0 || inv.Tax != 0 {
+		rows = append(rows, row{d.c.T("invoice.tax", d.c.Percent(inv.TaxRateBps)), inv.Tax})
+	}
+
+	height := float64(len(rows)+1)*6 + 20
+	if pdf.GetY()+height > d.pageBreakAtY {
+		pdf.AddPage()
+	}
+
+	labelWidth := priceWidth + qtyWidth
+	left := marginLeft + d.width - labelWidth - amountWidth
+
+	pdf.Ln(3)
+	pdf.SetFont("Helvetica", "", 9)
+	for _, r := range rows {
+		pdf.SetX(left)
+		pdf.CellFormat(labelWidth, 6, d.tr(r.label), "", 0, "R", false, 0, "")
+		pdf.CellFormat(amountWidth, 6, d.tr(d.c.Money(r.amount, inv.Currency)), "", 1, "R", false, 0, "")
+	}
+
+	pdf.SetX(left)
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(labelWidth, 8, d.tr(d.c.T("invoice.total")), "T", 0, "R", false, 0, "")
+	pdf.CellFormat(amountWidth, 8, d.tr(d.c.Money(inv.Total, inv.Currency)), "T", 1, "R", false, 0, "")
+
+	if inv.LastFour != "" {
+		pdf.Ln(6)
+		pdf.SetFont("Helvetica", "", 9)
+		paid := d.c.T("invoice.paid_by", d.cardBrand(), inv.LastFour)
+		pdf.CellFormat(d.width, 5, d.tr(paid), "", 1, "L", false, 0, "")
+	}
+}
+
+var cardBrands = map[string]string{
+	"amex":       "American Express",
+	"diners":     "Diners Club",
+	"discover":   "Discover",
+	"jcb":        "JCB",
+	"mastercard": "Mastercard",
+	"unionpay":   "UnionPay",
+	"visa":       "Visa",
+}
+
+// CardBrand returns the display name of a card brand as reported by Stripe
+func CardBrand(brand string) string {
+	if name, ok := cardBrand(brand); ok {
+		return name
+	}
+	return "card"
+}
+
+func cardBrand(brand string) (string, bool) {
+	if name, ok := cardBrands[strings.ToLower(brand)]; ok {
+		return name, true
+	}
+	if brand == "" || brand == "unknown" {
+		return "", false
+	}
+	return brand, true
+}
+
+// cardBrand returns the display name of the brand of card the invoice was paid with, in the
+// language of the document if the brand isn't known
+func (d *document) cardBrand() string {
+	if name, ok := cardBrand(d.inv.CardBrand); ok {
+		return name
+	}
+	return d.c.T("invoice.card")
+}
@@ -0,0 +1,192 @@
+package layout
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+)
+
+func testInvoice(lines int) models.Invoice {
+	inv := models.Invoice{
+		Number:        "INV-2026-000042",
+		OrderID:       42,
+		FirstName:     "Jane",
+		LastName:      "Doe",
+		Email:         "jane@example.com",
+		Currency:      "usd",
+		Discount:      500,
+		DiscountLabel: "WELCOME5",
+		Shipping:      799,
+		TaxRateBps:    825,
+		CardBrand:     "visa",
+		LastFour:      "4242",
+		IssuedAt:      time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		DueAt:         time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+	}
+	for i := 0; i < lines; i++ {
+		inv.LineItems = append(inv.LineItems, models.InvoiceLineItem{
+			Description: "Professional aluminium yoyo with a ball bearing axle and a very long product name that wraps",
+			Quantity:    2,
+			UnitPrice:   1250,
+			Amount:      2500,
+		})
+	}
+	inv.CalculateTotals()
+	return inv
+}
+
+func TestRenderSinglePage(t *testing.T) {
+	pdf := render(Seller{Name: "Yoyo Store", Address: []string{"1 Main St", "Springfield"}}, testInvoice(3), false)
+	if err := pdf.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if pdf.PageNo() != 1 {
+		t.Fatalf("expected 1 page, got %d", pdf.PageNo())
+	}
+}
+
+func TestRenderPaginates(t *testing.T) {
+	pdf := render(Seller{Name: "Yoyo Store"}, testInvoice(80), false)
+	if err := pdf.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if pdf.PageNo() < 3 {
+		t.Fatalf("expected 80 wrapped lines to take at least 3 pages, got %d", pdf.PageNo())
+	}
+
+	var buf bytes.Buffer
+	if err := Render(&buf, Seller{Name: "Yoyo Store"}, testInvoice(80)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "%PDF-") {
+		t.Fatal("expected a PDF document")
+	}
+}
+
+func TestCalculateTotals(t *testing.T) {
+	inv := testInvoice(2)
+
+	// 5000 subtotal - 500 discount = 4500 taxable; 8.25% tax = 371.25, rounded to 371
+	if inv.Subtotal != 5000 || inv.Tax != 371 || inv.Total != 4500+799+371 {
+		t.Fatalf("got subtotal %d, tax %d, total %d", inv.Subtotal, inv.Tax, inv.Total)
+	}
+	if inv.Quantity != 4 {
+		t.Fatalf("expected quantity 4, got %d", inv.Quantity)
+	}
+}
+
+func TestRenderLocalized(t *testing.T) {
+	inv := testInvoice(3)
+	inv.Locale = "de"
+
+	pdf := render(Seller{Name: "Yoyo Store", TaxID: "DE123456789"}, inv, false)
+	pdf.SetCompression(false)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"Rechnungsnummer", "USt-IdNr.: DE123456789", "Seite 1 von 1", "Zwischensumme"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected the invoice to contain %q", want)
+		}
+	}
+	if strings.Contains(buf.String(), "Subtotal") {
+		t.Error("expected no English labels")
+	}
+}
+
+func TestRenderCreditNote(t *testing.T) {
+	inv := testInvoice(2)
+	cn := models.CreditNote{
+		Number:   "CN-2026-000001",
+		Amount:   1000,
+		Currency: inv.Currency,
+		Reason:   "Damaged in transit",
+		IssuedAt: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC),
+	}
+
+	pdf := renderCreditNote(Seller{Name: "Yoyo Store"}, inv, cn)
+	if err := pdf.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if pdf.PageNo() != 1 {
+		t.Fatalf("expected 1 page, got %d", pdf.PageNo())
+	}
+}
+
+func TestRenderWithXML(t *testing.T) {
+	var buf bytes.Buffer
+	err := RenderWithXML(&buf, Seller{Name: "Yoyo Store"}, testInvoice(1), "INV-2026-000042.xml", []byte("<Invoice/>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf := buf.String()
+
+	if !strings.HasPrefix(pdf, "%PDF-1.7\n%\xe2\xe3\xcf\xd3\n") {
+		t.Error("expected a PDF 1.7 header followed by a binary comment")
+	}
+	for _, want := range []string{
+		"<pdfaid:part>3</pdfaid:part>",
+		"/OutputIntents [",
+		"/S /GTS_PDFA1",
+		"/AF [",
+		"/AFRelationship /Alternative",
+		"/Type /EmbeddedFile /Subtype /text#2Fxml",
+		"/FontFile2",
+		"/ID [<",
+	} {
+		if !strings.Contains(pdf, want) {
+			t.Errorf("expected the document to contain %q", want)
+		}
+	}
+	if strings.Contains(pdf, "/BaseFont /Helvetica") {
+		t.Error("expected no font to be left to the reader")
+	}
+
+	// every object must be where the cross-reference table says it is
+	xref := pdf[strings.LastIndex(pdf, "\nxref\n")+1:]
+	entries := strings.Split(xref, "\n")[3:]
+	objects := 0
+	for i, entry := range entries {
+		if !strings.HasSuffix(entry, " n ") {
+			break
+		}
+		var off int
+		if _, err := fmt.Sscanf(entry, "%d", &off); err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !strings.HasPrefix(pdf[off:], want) {
+			t.Errorf("expected object %d at offset %d", i+1, off)
+		}
+		objects++
+	}
+	if !strings.Contains(xref, fmt.Sprintf("/Size %d ", objects+1)) {
+		t.Errorf("expected the trailer to count %d objects", objects+1)
+	}
+}
+
+func TestSRGBProfile(t *testing.T) {
+	profile := sRGBProfile()
+	if size := binary.BigEndian.Uint32(profile); int(size) != len(profile) {
+		t.Fatalf("expected the profile size %d, got %d", len(profile), size)
+	}
+	if string(profile[36:40]) != "acsp" || string(profile[16:20]) != "RGB " {
+		t.Fatal("expected an RGB ICC profile")
+	}
+
+	count := binary.BigEndian.Uint32(profile[128:])
+	for i := 0; i < int(count); i++ {
+		entry := profile[132+12*i:]
+		off, size := binary.BigEndian.Uint32(entry[4:]), binary.BigEndian.Uint32(entry[8:])
+		if off%4 != 0 || int(off+size) > len(profile) {
+			t.Errorf("tag %s is out of place", entry[:4])
+		}
+	}
+}
@@ -0,0 +1,368 @@
+package layout
+
+import (
+	"bytes"
+	"crypto/md5"
+	_ "embed"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/i18n"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+)
+
+// PDF/A forbids relying on the reader's fonts, so hybrid invoices are set in DejaVu Sans,
+// embedded in the document
+var (
+	//go:embed fonts/DejaVuSansCondensed.ttf
+	dejaVuSans []byte
+	//go:embed fonts/DejaVuSansCondensed-Bold.ttf
+	dejaVuSansBold []byte
+)
+
+// RenderWithXML writes inv as a PDF/A-3b document to w, with its structured (XML) version
+// embedded as the invoice's alternative representation, in the style of ZUGFeRD and
+// Factur-X hybrid invoices
+func RenderWithXML(w io.Writer, seller Seller, inv models.Invoice, name string, xml []byte) error {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	pdf := render(seller, inv, true)
+	pdf.SetCreationDate(now)
+	pdf.SetModificationDate(now)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return err
+	}
+
+	c := i18n.For(inv.Locale)
+	data, err := pdfA3(buf.Bytes(), pdfAInfo{
+		Title:    fmt.Sprintf("%s %s", c.T("invoice.title"), inv.Number),
+		Author:   seller.Name,
+		Date:     now,
+		Name:     name,
+		Desc:     fmt.Sprintf("Invoice %s as a UBL 2.1 e-invoice", inv.Number),
+		Data:     xml,
+		MIMEType: "text/xml",
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+// embedFonts sets the document in embedded fonts, which are Unicode, so text no longer needs
+// translating to a code page. They are registered as Helvetica, the family the layout uses.
+func (d *document) embedFonts() {
+	d.pdf.AddUTF8FontFromBytes("Helvetica", "", dejaVuSans)
+	d.pdf.AddUTF8FontFromBytes("Helvetica", "B", dejaVuSansBold)
+	d.tr = func(s string) string { return s }
+}
+
+// pdfAInfo describes a PDF/A-3 document and the file associated with it
+type pdfAInfo struct {
+	Title  string
+	Author string
+	Date   time.Time
+
+	Name     string // file name of the associated file
+	Desc     string
+	Data     []byte
+	MIMEType string
+}
+
+const pdfAProducer = "Yoyo Store invoice service"
+
+var (
+	xrefEntry    = regexp.MustCompile(`^(\d{10}) (\d{5}) ([nf])\s*$`)
+	trailerEntry = regexp.MustCompile(`/(Size|Root|Info) (\d+)`)
+)
+
+// pdfA3 rewrites a single-revision PDF written by gofpdf, whose fonts are all embedded, as a
+// PDF/A-3b document: it adds the binary header comment, the XMP metadata identifying it as
+// PDF/A-3b, an sRGB output intent, and the associated file, and replaces the document
+// information to match the metadata. Objects are copied as they are, only renumbered where
+// they are added.
+func pdfA3(src []byte, info pdfAInfo) ([]byte, error) {
+	start := bytes.LastIndex(src, []byte("startxref"))
+	if start < 0 {
+		return nil, errors.New("pdfa: no startxref")
+	}
+	fields := strings.Fields(string(src[start+len("startxref"):]))
+	if len(fields) == 0 {
+		return nil, errors.New("pdfa: no xref offset")
+	}
+	xrefAt, err := strconv.Atoi(fields[0])
+	if err != nil || xrefAt >= len(src) {
+		return nil, errors.New("pdfa: bad xref offset")
+	}
+
+	// the cross-reference table of a single revision, and its trailer
+	lines := strings.Split(string(src[xrefAt:start]), "\n")
+	if len(lines) < 3 || strings.TrimSpace(lines[0]) != "xref" {
+		return nil, errors.New("pdfa: no xref table")
+	}
+	offsets := map[int]int{}
+	n := 1
+	for _, line := range lines[3:] {
+		m := xrefEntry.FindStringSubmatch(line)
+		if m == nil {
+			break
+		}
+		if m[3] == "n" {
+			offsets[n], _ = strconv.Atoi(m[1])
+		}
+		n++
+	}
+	trailer := map[string]int{}
+	for _, m := range trailerEntry.FindAllStringSubmatch(string(src[xrefAt:start]), -1) {
+		trailer[m[1]], _ = strconv.Atoi(m[2])
+	}
+	size, root, infoObj := trailer["Size"], trailer["Root"], trailer["Info"]
+	if size == 0 || root == 0 || infoObj == 0 || len(offsets) != size-1 {
+		return nil, errors.New("pdfa: unexpected trailer")
+	}
+
+	// objects end where the next one, or the xref table, starts
+	starts := []int{xrefAt}
+	for _, off := range offsets {
+		starts = append(starts, off)
+	}
+	sort.Ints(starts)
+	object := func(num int) []byte {
+		off := offsets[num]
+		end := starts[sort.SearchInts(starts, off)+1]
+		return src[off:end]
+	}
+
+	// the catalog is kept apart from its name dictionary, which only lists attachments
+	catalog := string(object(root))
+	body := catalog[strings.Index(catalog, "<<")+2:]
+	if i := strings.Index(body, "/Names <<"); i >= 0 {
+		body = body[:i]
+	} else if i := strings.LastIndex(body, ">>"); i >= 0 {
+		body = body[:i]
+	}
+
+	var out bytes.Buffer
+	newOffsets := make([]int, size, size+5)
+	out.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+
+	for num := 1; num < size; num++ {
+		if num == root || num == infoObj {
+			continue
+		}
+		newOffsets[num] = out.Len()
+		out.Write(object(num))
+	}
+
+	put := func(num int, dict string, stream []byte) {
+		for len(newOffsets) <= num {
+			newOffsets = append(newOffsets, 0)
+		}
+		newOffsets[num] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\n", num, dict)
+		if stream != nil {
+			out.WriteString("stream\n")
+			out.Write(stream)
+			out.WriteString("\nendstream\n")
+		}
+		out.WriteString("endobj\n")
+	}
+
+	metadata, icc, intent, file, spec := size, size+1, size+2, size+3, size+4
+
+	put(infoObj, fmt.Sprintf("<< /Title %s /Author %s /Producer %s /CreationDate %s /ModDate %s >>",
+		pdfText(info.Title), pdfText(info.Author), pdfText(pdfAProducer), pdfDate(info.Date), pdfDate(info.Date)), nil)
+
+	xmp := xmpMetadata(info)
+	put(metadata, fmt.Sprintf("<< /Type /Metadata /Subtype /XML /Length %d >>", len(xmp)), xmp)
+
+	profile := sRGBProfile()
+	put(icc, fmt.Sprintf("<< /N 3 /Length %d >>", len(profile)), profile)
+	put(intent, fmt.Sprintf("<< /Type /OutputIntent /S /GTS_PDFA1 /OutputConditionIdentifier (sRGB IEC61966-2.1) /Info (sRGB IEC61966-2.1) /DestOutputProfile %d 0 R >>", icc), nil)
+
+	sum := md5.Sum(info.Data)
+	put(file, fmt.Sprintf("<< /Type /EmbeddedFile /Subtype /%s /Params << /ModDate %s /Size %d /CheckSum <%s> >> /Length %d >>",
+		pdfName(info.MIMEType), pdfDate(info.Date), len(info.Data), hex.EncodeToString(sum[:]), len(info.Data)), info.Data)
+	put(spec, fmt.Sprintf("<< /Type /Filespec /F %s /UF %s /EF << /F %d 0 R /UF %d 0 R >> /Desc %s /AFRelationship /Alternative >>",
+		pdfText(info.Name), pdfText(info.Name), file, file, pdfText(info.Desc)), nil)
+
+	put(root, fmt.Sprintf("<<%s/Metadata %d 0 R\n/OutputIntents [%d 0 R]\n/AF [%d 0 R]\n/Names << /EmbeddedFiles << /Names [%s %d 0 R] >> >>\n>>",
+		body, metadata, intent, spec, pdfText(info.Name), spec), nil)
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(newOffsets))
+	for _, off := range newOffsets[1:] {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+
+	id := md5.Sum(out.Bytes())
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R /ID [<%x> <%x>] >>\nstartxref\n%d\n%%%%EOF\n",
+		len(newOffsets), root, infoObj, id, id, xref)
+
+	return out.Bytes(), nil
+}
+
+// xmpMetadata returns the XMP packet of a PDF/A-3b document, matching its document information
+func xmpMetadata(info pdfAInfo) []byte {
+	date := info.Date.Format("2006-01-02T15:04:05Z07:00")
+
+	var b strings.Builder
+	b.WriteString("<?xpacket begin=\"\xef\xbb\xbf\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	b.WriteString(`<x:xmpmeta xmlns:x="adobe:ns:meta/">
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about=""
+  xmlns:dc="http://purl.org/dc/elements/1.1/"
+  xmlns:xmp="http://ns.adobe.com/xap/1.0/"
+  xmlns:pdf="http://ns.adobe.com/pdf/1.3/"
+  xmlns:pdfaid="http://www.aiim.org/pdfa/ns/id/">
+`)
+	fmt.Fprintf(&b, "<dc:format>application/pdf</dc:format>\n")
+	fmt.Fprintf(&b, "<dc:title><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:title>\n", xmlEscape(info.Title))
+	fmt.Fprintf(&b, "<dc:creator><rdf:Seq><rdf:li>%s</rdf:li></rdf:Seq></dc:creator>\n", xmlEscape(info.Author))
+	fmt.Fprintf(&b, "<xmp:CreateDate>%s</xmp:CreateDate>\n<xmp:ModifyDate>%s</xmp:ModifyDate>\n<xmp:MetadataDate>%s</xmp:MetadataDate>\n", date, date, date)
+	fmt.Fprintf(&b, "<pdf:Producer>%s</pdf:Producer>\n", xmlEscape(pdfAProducer))
+	b.WriteString("<pdfaid:part>3</pdfaid:part>\n<pdfaid:conformance>B</pdfaid:conformance>\n")
+	b.WriteString("</rdf:Description>\n</rdf:RDF>\n</x:xmpmeta>\n<?xpacket end=\"w\"?>")
+
+	return []byte(b.String())
+}
+
+// pdfText encodes s as a PDF text string: literal if it is ASCII, UTF-16BE otherwise
+func pdfText(s string) string {
+	ascii := true
+	for _, r := range s {
+		if r > 126 || r < 32 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return "(" + strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(s) + ")"
+	}
+
+	buf := []byte{0xfe, 0xff}
+	for _, u := range utf16.Encode([]rune(s)) {
+		buf = binary.BigEndian.AppendUint16(buf, u)
+	}
+	return "<" + hex.EncodeToString(buf) + ">"
+}
+
+// pdfName encodes s as a PDF name, without the leading slash
+func pdfName(s string) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		if c < '!' || c > '~' || strings.IndexByte("#/()<>[]{}%", c) >= 0 {
+			fmt.Fprintf(&b, "#%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// pdfDate formats t as a PDF date string
+func pdfDate(t time.Time) string {
+	return "(D:" + t.UTC().Format("20060102150405") + "+00'00')"
+}
+
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// sRGBProfile builds an ICC version 2 display profile for the sRGB colour space, the output
+// intent of hybrid invoices
+func sRGBProfile() []byte {
+	s15 := func(v float64) []byte {
+		return binary.BigEndian.AppendUint32(nil, uint32(int32(math.Round(v*65536))))
+	}
+	xyz := func(x, y, z float64) []byte {
+		b := append([]byte("XYZ "), 0, 0, 0, 0)
+		return append(append(append(b, s15(x)...), s15(y)...), s15(z)...)
+	}
+
+	desc := []byte("sRGB IEC61966-2.1")
+	descTag := append([]byte("desc"), 0, 0, 0, 0)
+	descTag = binary.BigEndian.AppendUint32(descTag, uint32(len(desc)+1))
+	descTag = append(append(descTag, desc...), 0)
+	descTag = append(descTag, make([]byte, 4+4+2+1+67)...) // no Unicode or ScriptCode description
+
+	cprt := append(append([]byte("text"), 0, 0, 0, 0), []byte("No copyright, use freely\x00")...)
+
+	// the sRGB transfer function, sampled
+	trc := append([]byte("curv"), 0, 0, 0, 0)
+	trc = binary.BigEndian.AppendUint32(trc, 1024)
+	for i := 0; i < 1024; i++ {
+		v := float64(i) / 1023
+		if v <= 0.04045 {
+			v /= 12.92
+		} else {
+			v = math.Pow((v+0.055)/1.055, 2.4)
+		}
+		trc = binary.BigEndian.AppendUint16(trc, uint16(math.Round(v*65535)))
+	}
+
+	tags := []struct {
+		sig  string
+		data []byte
+	}{
+		{"desc", descTag},
+		{"cprt", cprt},
+		{"wtpt", xyz(0.9642, 1.0, 0.8249)},
+		{"rXYZ", xyz(0.4361, 0.2225, 0.0139)},
+		{"gXYZ", xyz(0.3851, 0.7169, 0.0971)},
+		{"bXYZ", xyz(0.1431, 0.0606, 0.7141)},
+		{"rTRC", trc},
+		{"gTRC", trc},
+		{"bTRC", trc},
+	}
+
+	// tag data follows the header and tag table, 4-byte aligned; the curves share theirs
+	table := binary.BigEndian.AppendUint32(nil, uint32(len(tags)))
+	var data []byte
+	at := 128 + 4 + 12*len(tags)
+	placed := map[*byte]int{}
+	for _, t := range tags {
+		off, ok := placed[&t.data[0]]
+		if !ok {
+			off = at + len(data)
+			placed[&t.data[0]] = off
+			data = append(data, t.data...)
+			for len(data)%4 != 0 {
+				data = append(data, 0)
+			}
+		}
+		table = append(table, t.sig...)
+		table = binary.BigEndian.AppendUint32(table, uint32(off))
+		table = binary.BigEndian.AppendUint32(table, uint32(len(t.data)))
+	}
+
+	header := make([]byte, 128)
+	binary.BigEndian.PutUint32(header[0:], uint32(128+len(table)+len(data)))
+	binary.BigEndian.PutUint32(header[8:], 0x02100000) // version 2.1
+	copy(header[12:], "mntr")
+	copy(header[16:], "RGB ")
+	copy(header[20:], "XYZ ")
+	binary.BigEndian.PutUint16(header[24:], 2026) // creation date
+	binary.BigEndian.PutUint16(header[26:], 1)
+	binary.BigEndian.PutUint16(header[28:], 1)
+	copy(header[36:], "acsp")
+	copy(header[68:], s15(0.9642)) // the D50 illuminant of the profile connection space
+	copy(header[72:], s15(1.0))
+	copy(header[76:], s15(0.8249))
+
+	return append(append(header, table...), data...)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"net"
 	"net/http"
@@ -10,9 +11,16 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/grpcauth"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
 	"github.com/LamThanhNguyen/yoyo-store-backend/internal/pb"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/storage"
 	"github.com/LamThanhNguyen/yoyo-store-backend/server_invoice/api"
 	"github.com/LamThanhNguyen/yoyo-store-backend/server_invoice/util"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/rs/zerolog/log"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
@@ -33,11 +41,21 @@ func main() {
 		log.Fatal().Err(err).Msg("cannot load config")
 	}
 
-	log.Info().Interface("config", config).Msg("loaded config")
+	log.Info().Interface("config", config.Redacted()).Msg("loaded config")
+
+	connPool, err := sql.Open("pgx", config.InvoiceDBSource)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot connect to db")
+	}
+
+	// Run migration to database
+	runDBMigration(config.InvoiceMigrationURL, connPool)
+
+	db_model := models.DBModel{DB: connPool}
 
 	waitGroup, ctx := errgroup.WithContext(ctx)
 
-	runServer(ctx, waitGroup, config)
+	runServer(ctx, waitGroup, config, &db_model, connPool)
 
 	if err = waitGroup.Wait(); err != nil {
 		log.Fatal().Err(err).Msg("err from wait group")
@@ -46,21 +64,74 @@ func main() {
 	log.Info().Msg("application shutdown complete")
 }
 
+// runDBMigration migrates the invoice tables. They keep their own migrations table,
+// so the invoice service can share a database with server_main or have its own.
+func runDBMigration(migrationURL string, db *sql.DB) {
+	driver, err := postgres.WithInstance(db, &postgres.Config{
+		MigrationsTable: "invoice_schema_migrations",
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create migrate driver")
+	}
+
+	migration, err := migrate.NewWithDatabaseInstance(migrationURL, "postgres", driver)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create new migrate instance")
+	}
+
+	if err = migration.Up(); err != nil && err != migrate.ErrNoChange {
+		log.Fatal().Err(err).Msg("failed to run migrate up")
+	}
+
+	log.Info().Msg("db migrated successfully")
+}
+
 func runServer(
 	ctx context.Context,
 	waitGroup *errgroup.Group,
 	config util.Config,
+	db *models.DBModel,
+	dbConn *sql.DB,
 ) {
-	server, err := api.NewServer(config)
+	dir := config.InvoiceStorageDir
+	if dir == "" {
+		dir = "./invoices"
+	}
+
+	store, err := storage.New(ctx, storage.Config{
+		Driver: config.InvoiceStorage,
+		Dir:    dir,
+		S3: storage.S3Config{
+			Bucket:    config.InvoiceS3Bucket,
+			Region:    config.InvoiceS3Region,
+			Endpoint:  config.InvoiceS3Endpoint,
+			AccessKey: config.InvoiceS3AccessKey,
+			SecretKey: config.InvoiceS3SecretKey,
+		},
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create invoice storage")
+	}
+
+	server, err := api.NewServer(config, db, store)
 	if err != nil {
 		log.Fatal().Err(err).Msg("cannot create server")
 	}
 
-	if err := server.CreateDirIfNotExist("./invoices"); err != nil {
-		log.Fatal().Err(err).Msg("failed to create invoices directory")
+	grpcOpts, err := grpcauth.ServerOptions(
+		config.Environment,
+		grpcauth.TLSFiles{
+			CAFile:   config.InvoiceTlsClientCaFile,
+			CertFile: config.InvoiceTlsCertFile,
+			KeyFile:  config.InvoiceTlsKeyFile,
+		},
+		config.InvoiceServiceSecret,
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot secure gRPC server")
 	}
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(grpcOpts...)
 	pb.RegisterInvoiceServiceServer(grpcServer, api.NewGRPCServer(server))
 
 	lis, err := net.Listen("tcp", config.InvoiceGrpcPort)
@@ -80,6 +151,12 @@ func runServer(
 		WriteTimeout:      30 * time.Second,
 	}
 
+	// Send queued mail in the background
+	waitGroup.Go(func() error {
+		log.Info().Msg("start mail dispatcher")
+		return server.RunMailDispatcher(ctx)
+	})
+
 	// Start gRPC server in goroutine
 	waitGroup.Go(func() error {
 		log.Info().Msgf("start gRPC server at %s", lis.Addr())
@@ -137,4 +214,14 @@ func runServer(
 		log.Info().Msg("HTTP server is stopped")
 		return nil
 	})
+
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+		log.Info().Msg("Closing DB connection")
+		if err := dbConn.Close(); err != nil {
+			log.Error().Err(err).Msg("failed to close DB connection")
+			return err
+		}
+		return nil
+	})
 }
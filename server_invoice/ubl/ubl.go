@@ -0,0 +1,387 @@
+// Package ubl produces invoices as UBL 2.1 XML documents carrying the core fields of the
+// European e-invoicing standard EN 16931, for customers who need machine-readable invoices.
+package ubl
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/server_invoice/layout"
+)
+
+// UBL 2.1 namespaces
+const (
+	NamespaceInvoice = "urn:oasis:names:specification:ubl:schema:xsd:Invoice-2"
+	NamespaceCAC     = "urn:oasis:names:specification:ubl:schema:xsd:CommonAggregateComponents-2"
+	NamespaceCBC     = "urn:oasis:names:specification:ubl:schema:xsd:CommonBasicComponents-2"
+)
+
+// CustomizationID identifies documents that follow EN 16931 without further restrictions
+const CustomizationID = "urn:cen.eu:en16931:2017"
+
+// codes from the UNTDID 1001, 4461 and 5305 code lists, and UN/ECE recommendation 20
+const (
+	invoiceTypeCommercial = "380"
+	paymentMeansCard      = "48"
+	unitPiece             = "C62"
+
+	categoryStandard     = "S"
+	categoryZeroRated    = "Z"
+	categoryNotSubjectTo = "O"
+)
+
+const dateLayout = "2006-01-02"
+
+// Invoice is a UBL 2.1 invoice. Elements are declared in the order the schema requires.
+type Invoice struct {
+	XMLName  xml.Name `xml:"Invoice"`
+	XMLNS    string   `xml:"xmlns,attr"`
+	XMLNSCAC string   `xml:"xmlns:cac,attr"`
+	XMLNSCBC string   `xml:"xmlns:cbc,attr"`
+
+	CustomizationID      string            `xml:"cbc:CustomizationID"`
+	ID                   string            `xml:"cbc:ID"`
+	IssueDate            string            `xml:"cbc:IssueDate"`
+	DueDate              string            `xml:"cbc:DueDate,omitempty"`
+	InvoiceTypeCode      string            `xml:"cbc:InvoiceTypeCode"`
+	DocumentCurrencyCode string            `xml:"cbc:DocumentCurrencyCode"`
+	OrderReference       *Reference        `xml:"cac:OrderReference,omitempty"`
+	Supplier             PartyRole         `xml:"cac:AccountingSupplierParty"`
+	Customer             PartyRole         `xml:"cac:AccountingCustomerParty"`
+	PaymentMeans         *PaymentMeans     `xml:"cac:PaymentMeans,omitempty"`
+	AllowanceCharges     []AllowanceCharge `xml:"cac:AllowanceCharge"`
+	TaxTotal             TaxTotal          `xml:"cac:TaxTotal"`
+	LegalMonetaryTotal   MonetaryTotal     `xml:"cac:LegalMonetaryTotal"`
+	Lines                []Line            `xml:"cac:InvoiceLine"`
+}
+
+type Reference struct {
+	ID string `xml:"cbc:ID"`
+}
+
+type PartyRole struct {
+	Party Party `xml:"cac:Party"`
+}
+
+type Party struct {
+	Name        *PartyName  `xml:"cac:PartyName,omitempty"`
+	Address     Address     `xml:"cac:PostalAddress"`
+	TaxScheme   *PartyTax   `xml:"cac:PartyTaxScheme,omitempty"`
+	LegalEntity LegalEntity `xml:"cac:PartyLegalEntity"`
+	Contact     *Contact    `xml:"cac:Contact,omitempty"`
+}
+
+type PartyName struct {
+	Name string `xml:"cbc:Name"`
+}
+
+type Address struct {
+	StreetName           string  `xml:"cbc:StreetName,omitempty"`
+	AdditionalStreetName string  `xml:"cbc:AdditionalStreetName,omitempty"`
+	CityName             string  `xml:"cbc:CityName,omitempty"`
+	Country              Country `xml:"cac:Country"`
+}
+
+type Country struct {
+	IdentificationCode string `xml:"cbc:IdentificationCode"`
+}
+
+type PartyTax struct {
+	CompanyID string    `xml:"cbc:CompanyID"`
+	TaxScheme TaxScheme `xml:"cac:TaxScheme"`
+}
+
+type TaxScheme struct {
+	ID string `xml:"cbc:ID"`
+}
+
+type LegalEntity struct {
+	RegistrationName string `xml:"cbc:RegistrationName"`
+}
+
+type Contact struct {
+	Telephone      string `xml:"cbc:Telephone,omitempty"`
+	ElectronicMail string `xml:"cbc:ElectronicMail,omitempty"`
+}
+
+type PaymentMeans struct {
+	PaymentMeansCode string       `xml:"cbc:PaymentMeansCode"`
+	CardAccount      *CardAccount `xml:"cac:CardAccount,omitempty"`
+}
+
+// CardAccount identifies the card paid with. EN 16931 only allows the last digits of the
+// card number.
+type CardAccount struct {
+	PrimaryAccountNumberID string `xml:"cbc:PrimaryAccountNumberID"`
+	NetworkID              string `xml:"cbc:NetworkID"`
+}
+
+// AllowanceCharge is a document level discount (ChargeIndicator false) or charge
+type AllowanceCharge struct {
+	ChargeIndicator bool        `xml:"cbc:ChargeIndicator"`
+	Reason          string      `xml:"cbc:AllowanceChargeReason,omitempty"`
+	Amount          Amount      `xml:"cbc:Amount"`
+	TaxCategory     TaxCategory `xml:"cac:TaxCategory"`
+}
+
+type TaxCategory struct {
+	ID                 string    `xml:"cbc:ID"`
+	Percent            *Percent  `xml:"cbc:Percent,omitempty"`
+	TaxExemptionReason string    `xml:"cbc:TaxExemptionReason,omitempty"`
+	TaxScheme          TaxScheme `xml:"cac:TaxScheme"`
+}
+
+type TaxTotal struct {
+	TaxAmount    Amount        `xml:"cbc:TaxAmount"`
+	TaxSubtotals []TaxSubtotal `xml:"cac:TaxSubtotal"`
+}
+
+type TaxSubtotal struct {
+	TaxableAmount Amount      `xml:"cbc:TaxableAmount"`
+	TaxAmount     Amount      `xml:"cbc:TaxAmount"`
+	TaxCategory   TaxCategory `xml:"cac:TaxCategory"`
+}
+
+type MonetaryTotal struct {
+	LineExtensionAmount  Amount `xml:"cbc:LineExtensionAmount"`
+	TaxExclusiveAmount   Amount `xml:"cbc:TaxExclusiveAmount"`
+	TaxInclusiveAmount   Amount `xml:"cbc:TaxInclusiveAmount"`
+	AllowanceTotalAmount Amount `xml:"cbc:AllowanceTotalAmount"`
+	ChargeTotalAmount    Amount `xml:"cbc:ChargeTotalAmount"`
+	PrepaidAmount        Amount `xml:"cbc:PrepaidAmount"`
+	PayableAmount        Amount `xml:"cbc:PayableAmount"`
+}
+
+type Line struct {
+	ID                  string   `xml:"cbc:ID"`
+	InvoicedQuantity    Quantity `xml:"cbc:InvoicedQuantity"`
+	LineExtensionAmount Amount   `xml:"cbc:LineExtensionAmount"`
+	Item                Item     `xml:"cac:Item"`
+	Price               Price    `xml:"cac:Price"`
+}
+
+type Item struct {
+	Name                  string      `xml:"cbc:Name"`
+	ClassifiedTaxCategory TaxCategory `xml:"cac:ClassifiedTaxCategory"`
+}
+
+type Price struct {
+	PriceAmount Amount `xml:"cbc:PriceAmount"`
+}
+
+// Amount is an amount in the smallest unit of Currency, written as a decimal number,
+// e.g. 123456 eur as 1234.56
+type Amount struct {
+	Currency string
+	Value    int
+}
+
+func (a Amount) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "currencyID"}, Value: strings.ToUpper(a.Currency)})
+	return e.EncodeElement(decimal(a.Value, models.CurrencyDecimals(a.Currency)), start)
+}
+
+// Quantity is a number of units of UnitCode
+type Quantity struct {
+	UnitCode string `xml:"unitCode,attr"`
+	Value    int    `xml:",chardata"`
+}
+
+// Percent is a rate in basis points, written as a percentage, e.g. 825 as 8.25
+type Percent int
+
+func (p Percent) MarshalText() ([]byte, error) {
+	return []byte(strings.TrimSuffix(strings.TrimRight(decimal(int(p), 2), "0"), ".")), nil
+}
+
+// decimal formats n with the given number of implied decimals, e.g. 1234 with 2 as 12.34
+func decimal(n, decimals int) string {
+	if decimals == 0 {
+		return strconv.Itoa(n)
+	}
+
+	sign := ""
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+
+	unit := 1
+	for i := 0; i < decimals; i++ {
+		unit *= 10
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, n/unit, decimals, n%unit)
+}
+
+// Build maps inv, issued by seller, to a UBL invoice. Without a tax rate, the invoice is
+// "not subject to VAT"; otherwise the goods are standard rated and shipping, which is not
+// taxed, is zero rated.
+func Build(seller layout.Seller, inv models.Invoice) *Invoice {
+	currency := strings.ToUpper(inv.Currency)
+	if currency == "" {
+		currency = "USD"
+	}
+	amount := func(v int) Amount { return Amount{Currency: currency, Value: v} }
+	vat := TaxScheme{ID: "VAT"}
+
+	goods := TaxCategory{ID: categoryNotSubjectTo, TaxScheme: vat}
+	shipping := goods
+	if inv.TaxRateBps > 0 {
+		rate, zero := Percent(inv.TaxRateBps), Percent(0)
+		goods = TaxCategory{ID: categoryStandard, Percent: &rate, TaxScheme: vat}
+		shipping = TaxCategory{ID: categoryZeroRated, Percent: &zero, TaxScheme: vat}
+	}
+
+	doc := &Invoice{
+		XMLNS:                NamespaceInvoice,
+		XMLNSCAC:             NamespaceCAC,
+		XMLNSCBC:             NamespaceCBC,
+		CustomizationID:      CustomizationID,
+		ID:                   inv.Number,
+		IssueDate:            inv.IssuedAt.Format(dateLayout),
+		InvoiceTypeCode:      invoiceTypeCommercial,
+		DocumentCurrencyCode: currency,
+		OrderReference:       &Reference{ID: strconv.Itoa(inv.OrderID)},
+		Supplier:             PartyRole{Party: sellerParty(seller)},
+		Customer: PartyRole{Party: Party{
+			Address:     Address{Country: Country{IdentificationCode: strings.ToUpper(inv.BuyerCountry)}},
+			LegalEntity: LegalEntity{RegistrationName: strings.TrimSpace(inv.FirstName + " " + inv.LastName)},
+			Contact:     &Contact{ElectronicMail: inv.Email},
+		}},
+	}
+	if !inv.DueAt.IsZero() {
+		doc.DueDate = inv.DueAt.Format(dateLayout)
+	}
+	if seller.TaxID != "" && goods.ID != categoryNotSubjectTo {
+		doc.Supplier.Party.TaxScheme = &PartyTax{CompanyID: seller.TaxID, TaxScheme: vat}
+	}
+
+	lineTotal := 0
+	for i, line := range inv.LineItems {
+		lineTotal += line.Amount
+		doc.Lines = append(doc.Lines, Line{
+			ID:                  strconv.Itoa(i + 1),
+			InvoicedQuantity:    Quantity{UnitCode: unitPiece, Value: line.Quantity},
+			LineExtensionAmount: amount(line.Amount),
+			Item:                Item{Name: line.Description, ClassifiedTaxCategory: goods},
+			Price:               Price{PriceAmount: amount(line.UnitPrice)},
+		})
+	}
+
+	// a discount can't take the goods below zero
+	discount := min(inv.Discount, lineTotal)
+	if discount > 0 {
+		reason := "Discount"
+		if inv.DiscountLabel != "" {
+			reason = fmt.Sprintf("Discount (%s)", inv.DiscountLabel)
+		}
+		doc.AllowanceCharges = append(doc.AllowanceCharges, AllowanceCharge{
+			ChargeIndicator: false,
+			Reason:          reason,
+			Amount:          amount(discount),
+			TaxCategory:     goods,
+		})
+	}
+	if inv.Shipping > 0 {
+		doc.AllowanceCharges = append(doc.AllowanceCharges, AllowanceCharge{
+			ChargeIndicator: true,
+			Reason:          "Shipping",
+			Amount:          amount(inv.Shipping),
+			TaxCategory:     shipping,
+		})
+	}
+
+	if goods.ID == categoryNotSubjectTo {
+		goods.TaxExemptionReason = "Not subject to VAT"
+		doc.TaxTotal = TaxTotal{
+			TaxAmount: amount(0),
+			TaxSubtotals: []TaxSubtotal{
+				{TaxableAmount: amount(lineTotal - discount + inv.Shipping), TaxAmount: amount(0), TaxCategory: goods},
+			},
+		}
+	} else {
+		doc.TaxTotal = TaxTotal{
+			TaxAmount: amount(inv.Tax),
+			TaxSubtotals: []TaxSubtotal{
+				{TaxableAmount: amount(lineTotal - discount), TaxAmount: amount(inv.Tax), TaxCategory: goods},
+			},
+		}
+		if inv.Shipping > 0 {
+			doc.TaxTotal.TaxSubtotals = append(doc.TaxTotal.TaxSubtotals,
+				TaxSubtotal{TaxableAmount: amount(inv.Shipping), TaxAmount: amount(0), TaxCategory: shipping})
+		}
+	}
+
+	taxExclusive := lineTotal - discount + inv.Shipping
+	taxInclusive := taxExclusive + inv.Tax
+	prepaid := 0
+	if inv.LastFour != "" {
+		// paid by card at checkout
+		prepaid = taxInclusive
+		doc.PaymentMeans = &PaymentMeans{
+			PaymentMeansCode: paymentMeansCard,
+			CardAccount: &CardAccount{
+				PrimaryAccountNumberID: inv.LastFour,
+				NetworkID:              layout.CardBrand(inv.CardBrand),
+			},
+		}
+	}
+
+	doc.LegalMonetaryTotal = MonetaryTotal{
+		LineExtensionAmount:  amount(lineTotal),
+		TaxExclusiveAmount:   amount(taxExclusive),
+		TaxInclusiveAmount:   amount(taxInclusive),
+		AllowanceTotalAmount: amount(discount),
+		ChargeTotalAmount:    amount(inv.Shipping),
+		PrepaidAmount:        amount(prepaid),
+		PayableAmount:        amount(taxInclusive - prepaid),
+	}
+
+	return doc
+}
+
+// sellerParty maps the seller's address lines to a street, a second street line and a city
+func sellerParty(seller layout.Seller) Party {
+	party := Party{
+		Name:        &PartyName{Name: seller.Name},
+		Address:     Address{Country: Country{IdentificationCode: strings.ToUpper(seller.Country)}},
+		LegalEntity: LegalEntity{RegistrationName: seller.Name},
+	}
+
+	lines := seller.Address
+	if len(lines) > 1 {
+		party.Address.CityName = lines[len(lines)-1]
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > 0 {
+		party.Address.StreetName = lines[0]
+	}
+	if len(lines) > 1 {
+		party.Address.AdditionalStreetName = strings.Join(lines[1:], ", ")
+	}
+
+	if seller.Phone != "" || seller.Email != "" {
+		party.Contact = &Contact{Telephone: seller.Phone, ElectronicMail: seller.Email}
+	}
+
+	return party
+}
+
+// Marshal returns inv as a UBL XML document, after checking it against the EN 16931 rules
+// in Validate
+func Marshal(seller layout.Seller, inv models.Invoice) ([]byte, error) {
+	doc := Build(seller, inv)
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), out...), nil
+}
@@ -0,0 +1,187 @@
+package ubl
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/server_invoice/layout"
+)
+
+var testSeller = layout.Seller{
+	Name:    "Yoyo Store GmbH",
+	Address: []string{"Hauptstrasse 1", "10115 Berlin"},
+	Email:   "billing@example.com",
+	TaxID:   "DE123456789",
+	Country: "de",
+}
+
+func testInvoice() models.Invoice {
+	inv := models.Invoice{
+		Number:       "INV-2026-000042",
+		OrderID:      42,
+		FirstName:    "Jane",
+		LastName:     "Doe",
+		Email:        "jane@example.com",
+		BuyerCountry: "fr",
+		Currency:     "eur",
+		LineItems: []models.InvoiceLineItem{
+			{Description: "Aluminium yoyo", Quantity: 2, UnitPrice: 1250, Amount: 2500},
+			{Description: "Spare strings", Quantity: 1, UnitPrice: 499, Amount: 499},
+		},
+		Discount:      500,
+		DiscountLabel: "WELCOME5",
+		Shipping:      799,
+		TaxRateBps:    1900,
+		CardBrand:     "visa",
+		LastFour:      "4242",
+		IssuedAt:      time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		DueAt:         time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+	}
+	inv.CalculateTotals()
+	return inv
+}
+
+func TestMarshal(t *testing.T) {
+	out, err := Marshal(testSeller, testInvoice())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every element must resolve to one of the UBL namespaces
+	dec := xml.NewDecoder(bytes.NewReader(out))
+	root := ""
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatalf("output is not well-formed: %v", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if root == "" {
+			root = start.Name.Space + " " + start.Name.Local
+		}
+		switch start.Name.Space {
+		case NamespaceInvoice, NamespaceCAC, NamespaceCBC:
+		default:
+			t.Fatalf("element %s is in namespace %q", start.Name.Local, start.Name.Space)
+		}
+	}
+	if root != NamespaceInvoice+" Invoice" {
+		t.Fatalf("unexpected root element %q", root)
+	}
+
+	// 29.99 of lines - 5.00 discount = 24.99 at 19% = 4.75 tax, plus 7.99 untaxed shipping
+	for _, want := range []string{
+		`<cbc:CustomizationID>urn:cen.eu:en16931:2017</cbc:CustomizationID>`,
+		`<cbc:DocumentCurrencyCode>EUR</cbc:DocumentCurrencyCode>`,
+		`<cbc:LineExtensionAmount currencyID="EUR">29.99</cbc:LineExtensionAmount>`,
+		`<cbc:TaxAmount currencyID="EUR">4.75</cbc:TaxAmount>`,
+		`<cbc:Percent>19</cbc:Percent>`,
+		`<cbc:TaxInclusiveAmount currencyID="EUR">37.73</cbc:TaxInclusiveAmount>`,
+		`<cbc:PayableAmount currencyID="EUR">0.00</cbc:PayableAmount>`,
+		`<cbc:PrimaryAccountNumberID>4242</cbc:PrimaryAccountNumberID>`,
+		`<cbc:IdentificationCode>FR</cbc:IdentificationCode>`,
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected output to contain %s", want)
+		}
+	}
+}
+
+func TestBuildNotSubjectToVAT(t *testing.T) {
+	inv := testInvoice()
+	inv.TaxRateBps = 0
+	inv.CalculateTotals()
+
+	doc := Build(testSeller, inv)
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doc.TaxTotal.TaxSubtotals) != 1 || doc.TaxTotal.TaxSubtotals[0].TaxCategory.ID != categoryNotSubjectTo {
+		t.Fatalf("expected a single not subject to VAT breakdown, got %+v", doc.TaxTotal.TaxSubtotals)
+	}
+	if doc.Supplier.Party.TaxScheme != nil {
+		t.Fatal("expected no seller VAT identifier")
+	}
+}
+
+func TestAmountDecimals(t *testing.T) {
+	out, err := xml.Marshal(struct {
+		XMLName xml.Name `xml:"x"`
+		Amount  Amount   `xml:"a"`
+	}{Amount: Amount{Currency: "jpy", Value: 1500}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<x><a currencyID="JPY">1500</a></x>`; string(out) != want {
+		t.Fatalf("got %s, want %s", out, want)
+	}
+
+	if got, _ := Percent(825).MarshalText(); string(got) != "8.25" {
+		t.Fatalf("got %s, want 8.25", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		invoice func(inv *models.Invoice, seller *layout.Seller)
+		doc     func(doc *Invoice)
+	}{
+		{name: "no buyer country", rule: "BR-11", invoice: func(inv *models.Invoice, _ *layout.Seller) { inv.BuyerCountry = "" }},
+		{name: "bad buyer country", rule: "BR-CL-14", invoice: func(inv *models.Invoice, _ *layout.Seller) { inv.BuyerCountry = "FRA" }},
+		{name: "no seller country", rule: "BR-09", invoice: func(_ *models.Invoice, s *layout.Seller) { s.Country = "" }},
+		{name: "bad currency", rule: "BR-CL-04", invoice: func(inv *models.Invoice, _ *layout.Seller) { inv.Currency = "euro" }},
+		{name: "no lines", rule: "BR-16", invoice: func(inv *models.Invoice, _ *layout.Seller) { inv.LineItems = nil }},
+		{name: "no item name", rule: "BR-25", invoice: func(inv *models.Invoice, _ *layout.Seller) { inv.LineItems[0].Description = "" }},
+		{name: "no seller VAT id", rule: "BR-S-02", invoice: func(_ *models.Invoice, s *layout.Seller) { s.TaxID = "" }},
+		{name: "lines total", rule: "BR-CO-10", doc: func(doc *Invoice) { doc.Lines[0].LineExtensionAmount.Value++ }},
+		{name: "tax rounding", rule: "BR-CO-17", doc: func(doc *Invoice) {
+			doc.TaxTotal.TaxSubtotals[0].TaxAmount.Value++
+			doc.TaxTotal.TaxAmount.Value++
+		}},
+		{name: "total with VAT", rule: "BR-CO-15", doc: func(doc *Invoice) { doc.LegalMonetaryTotal.TaxInclusiveAmount.Value++ }},
+		{name: "amount due", rule: "BR-CO-16", doc: func(doc *Invoice) { doc.LegalMonetaryTotal.PrepaidAmount.Value = 0 }},
+		{name: "VAT id without VAT", rule: "BR-O-02", doc: func(doc *Invoice) {
+			for i := range doc.Lines {
+				doc.Lines[i].Item.ClassifiedTaxCategory = TaxCategory{ID: categoryNotSubjectTo}
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv, seller := testInvoice(), testSeller
+			if tt.invoice != nil {
+				tt.invoice(&inv, &seller)
+				inv.CalculateTotals()
+			}
+
+			doc := Build(seller, inv)
+			if tt.doc != nil {
+				tt.doc(doc)
+			}
+
+			err := doc.Validate()
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected a validation error, got %v", err)
+			}
+			if !verr.Has(tt.rule) {
+				t.Fatalf("expected %s to be broken, got %v", tt.rule, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package ubl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Violation is a broken EN 16931 business rule
+type Violation struct {
+	Rule    string
+	Message string
+}
+
+// ValidationError lists the business rules an invoice breaks
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	rules := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		rules[i] = fmt.Sprintf("%s: %s", v.Rule, v.Message)
+	}
+	return "invoice is not a valid EN 16931 e-invoice (" + strings.Join(rules, "; ") + ")"
+}
+
+// Has reports whether rule is one of the broken rules
+func (e *ValidationError) Has(rule string) bool {
+	for _, v := range e.Violations {
+		if v.Rule == rule {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+	countryCode  = regexp.MustCompile(`^[A-Z]{2}$`)
+)
+
+// Validate checks the invoice against the EN 16931 business rules that can be checked
+// offline: mandatory fields, code formats, the VAT category rules for the categories Build
+// uses, and the calculation of the totals. It returns a *ValidationError listing every
+// broken rule.
+func (doc *Invoice) Validate() error {
+	var violations []Violation
+	check := func(ok bool, rule, message string) {
+		if !ok {
+			violations = append(violations, Violation{Rule: rule, Message: message})
+		}
+	}
+
+	check(doc.CustomizationID != "", "BR-01", "a specification identifier is required")
+	check(doc.ID != "", "BR-02", "an invoice number is required")
+	check(isDate(doc.IssueDate), "BR-03", "an issue date is required")
+	check(doc.InvoiceTypeCode != "", "BR-04", "an invoice type code is required")
+	check(doc.DocumentCurrencyCode != "", "BR-05", "an invoice currency code is required")
+	check(doc.DocumentCurrencyCode == "" || currencyCode.MatchString(doc.DocumentCurrencyCode), "BR-CL-04", "the currency must be an ISO 4217 code")
+
+	seller, buyer := doc.Supplier.Party, doc.Customer.Party
+	check(seller.LegalEntity.RegistrationName != "", "BR-06", "the seller's name is required")
+	check(buyer.LegalEntity.RegistrationName != "", "BR-07", "the buyer's name is required")
+	check(seller.Address.Country.IdentificationCode != "", "BR-09", "the seller's country is required")
+	check(buyer.Address.Country.IdentificationCode != "", "BR-11", "the buyer's country is required")
+	for _, code := range []string{seller.Address.Country.IdentificationCode, buyer.Address.Country.IdentificationCode} {
+		check(code == "" || countryCode.MatchString(code), "BR-CL-14", fmt.Sprintf("%q is not an ISO 3166-1 alpha-2 country code", code))
+	}
+
+	check(len(doc.Lines) > 0, "BR-16", "at least one invoice line is required")
+	lineTotal := 0
+	categories := map[string]bool{}
+	for _, line := range doc.Lines {
+		lineTotal += line.LineExtensionAmount.Value
+		categories[line.Item.ClassifiedTaxCategory.ID] = true
+		check(line.ID != "", "BR-21", "each line needs an identifier")
+		check(line.Item.Name != "", "BR-25", fmt.Sprintf("line %s needs an item name", line.ID))
+		check(line.Price.PriceAmount.Value >= 0, "BR-27", fmt.Sprintf("the price of line %s can't be negative", line.ID))
+		check(line.Item.ClassifiedTaxCategory.ID != "", "BR-CO-04", fmt.Sprintf("line %s needs a VAT category", line.ID))
+
+		rate := line.Item.ClassifiedTaxCategory.Percent
+		switch line.Item.ClassifiedTaxCategory.ID {
+		case categoryStandard:
+			check(rate != nil && *rate > 0, "BR-S-05", fmt.Sprintf("standard rated line %s needs a VAT rate above 0", line.ID))
+		case categoryZeroRated:
+			check(rate != nil && *rate == 0, "BR-Z-05", fmt.Sprintf("zero rated line %s needs a VAT rate of 0", line.ID))
+		case categoryNotSubjectTo:
+			check(rate == nil, "BR-O-05", fmt.Sprintf("line %s is not subject to VAT, so it can't have a VAT rate", line.ID))
+		}
+	}
+
+	allowances, charges := 0, 0
+	for _, ac := range doc.AllowanceCharges {
+		categories[ac.TaxCategory.ID] = true
+		if ac.ChargeIndicator {
+			charges += ac.Amount.Value
+			check(ac.Reason != "", "BR-38", "each document level charge needs a reason")
+		} else {
+			allowances += ac.Amount.Value
+			check(ac.Reason != "", "BR-33", "each document level allowance needs a reason")
+		}
+	}
+
+	totals := doc.LegalMonetaryTotal
+	check(totals.LineExtensionAmount.Value == lineTotal, "BR-CO-10", "the sum of line net amounts must equal the lines total")
+	check(totals.AllowanceTotalAmount.Value == allowances, "BR-CO-11", "the allowances total must equal the sum of the allowances")
+	check(totals.ChargeTotalAmount.Value == charges, "BR-CO-12", "the charges total must equal the sum of the charges")
+	check(totals.TaxExclusiveAmount.Value == lineTotal-allowances+charges, "BR-CO-13", "the total without VAT must be the lines total less allowances plus charges")
+
+	taxTotal := 0
+	for _, sub := range doc.TaxTotal.TaxSubtotals {
+		taxTotal += sub.TaxAmount.Value
+		cat := sub.TaxCategory
+
+		if cat.ID == categoryNotSubjectTo {
+			check(sub.TaxAmount.Value == 0, "BR-O-09", "the VAT of the not subject to VAT category must be 0")
+			check(cat.TaxExemptionReason != "", "BR-O-10", "the not subject to VAT category needs an exemption reason")
+			continue
+		}
+
+		if cat.Percent == nil {
+			check(false, "BR-48", fmt.Sprintf("the VAT breakdown of category %s needs a rate", cat.ID))
+			continue
+		}
+		want := (sub.TaxableAmount.Value*int(*cat.Percent) + 5000) / 10000
+		check(sub.TaxAmount.Value == want, "BR-CO-17", fmt.Sprintf("the VAT of category %s must be its taxable amount times its rate", cat.ID))
+	}
+	check(doc.TaxTotal.TaxAmount.Value == taxTotal, "BR-CO-14", "the VAT total must equal the sum of the VAT breakdown")
+	check(totals.TaxInclusiveAmount.Value == totals.TaxExclusiveAmount.Value+doc.TaxTotal.TaxAmount.Value, "BR-CO-15", "the total with VAT must be the total without VAT plus the VAT total")
+	check(totals.PayableAmount.Value == totals.TaxInclusiveAmount.Value-totals.PrepaidAmount.Value, "BR-CO-16", "the amount due must be the total with VAT less the paid amount")
+	check(totals.PayableAmount.Value <= 0 || doc.DueDate != "", "BR-CO-25", "a due date is required when an amount is due")
+
+	hasVATID := seller.TaxScheme != nil && seller.TaxScheme.CompanyID != ""
+	if categories[categoryStandard] {
+		check(hasVATID, "BR-S-02", "the seller's VAT identifier is required for standard rated items")
+	}
+	if categories[categoryZeroRated] {
+		check(hasVATID, "BR-Z-02", "the seller's VAT identifier is required for zero rated items")
+	}
+	if categories[categoryNotSubjectTo] {
+		check(!hasVATID, "BR-O-02", "the seller's VAT identifier can't be given for items not subject to VAT")
+		check(len(categories) == 1, "BR-O-11", "items not subject to VAT can't be combined with other VAT categories")
+	}
+
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+func isDate(s string) bool {
+	_, err := time.Parse(dateLayout, s)
+	return err == nil
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/redact"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
@@ -15,14 +16,47 @@ import (
 )
 
 type Config struct {
-	Environment     string   `mapstructure:"ENVIRONMENT" json:"ENVIRONMENT"`
-	AllowedOrigins  []string `mapstructure:"ALLOWED_ORIGINS" json:"ALLOWED_ORIGINS"`
-	InvoiceGrpcPort string   `mapstructure:"INVOICE_GRPC_PORT" json:"INVOICE_GRPC_PORT"`
-	InvoiceHttpPort string   `mapstructure:"INVOICE_HTTP_PORT" json:"INVOICE_HTTP_PORT"`
-	SmtpHost        string   `mapstructure:"SMTP_HOST" json:"SMTP_HOST"`
-	SmtpPort        string   `mapstructure:"SMTP_PORT" json:"SMTP_PORT"`
-	SmtpUsername    string   `mapstructure:"SMTP_USERNAME" json:"SMTP_USERNAME"`
-	SmtpPassword    string   `mapstructure:"SMTP_PASSWORD" json:"SMTP_PASSWORD"`
+	Environment            string   `mapstructure:"ENVIRONMENT" json:"ENVIRONMENT"`
+	AllowedOrigins         []string `mapstructure:"ALLOWED_ORIGINS" json:"ALLOWED_ORIGINS"`
+	InvoiceGrpcPort        string   `mapstructure:"INVOICE_GRPC_PORT" json:"INVOICE_GRPC_PORT"`
+	InvoiceHttpPort        string   `mapstructure:"INVOICE_HTTP_PORT" json:"INVOICE_HTTP_PORT"`
+	InvoiceDBSource        string   `mapstructure:"INVOICE_DB_SOURCE" json:"INVOICE_DB_SOURCE"`
+	InvoiceMigrationURL    string   `mapstructure:"INVOICE_MIGRATION_URL" json:"INVOICE_MIGRATION_URL"`
+	InvoiceStorage         string   `mapstructure:"INVOICE_STORAGE" json:"INVOICE_STORAGE"`
+	InvoiceStorageDir      string   `mapstructure:"INVOICE_STORAGE_DIR" json:"INVOICE_STORAGE_DIR"`
+	InvoiceS3Bucket        string   `mapstructure:"INVOICE_S3_BUCKET" json:"INVOICE_S3_BUCKET"`
+	InvoiceS3Region        string   `mapstructure:"INVOICE_S3_REGION" json:"INVOICE_S3_REGION"`
+	InvoiceS3Endpoint      string   `mapstructure:"INVOICE_S3_ENDPOINT" json:"INVOICE_S3_ENDPOINT"`
+	InvoiceS3AccessKey     string   `mapstructure:"INVOICE_S3_ACCESS_KEY" json:"INVOICE_S3_ACCESS_KEY"`
+	InvoiceS3SecretKey     string   `mapstructure:"INVOICE_S3_SECRET_KEY" json:"INVOICE_S3_SECRET_KEY"`
+	InvoiceTlsCertFile     string   `mapstructure:"INVOICE_TLS_CERT_FILE" json:"INVOICE_TLS_CERT_FILE"`
+	InvoiceTlsKeyFile      string   `mapstructure:"INVOICE_TLS_KEY_FILE" json:"INVOICE_TLS_KEY_FILE"`
+	InvoiceTlsClientCaFile string   `mapstructure:"INVOICE_TLS_CLIENT_CA_FILE" json:"INVOICE_TLS_CLIENT_CA_FILE"`
+	InvoiceServiceSecret   string   `mapstructure:"INVOICE_SERVICE_SECRET" json:"INVOICE_SERVICE_SECRET"`
+	InvoiceSellerName      string   `mapstructure:"INVOICE_SELLER_NAME" json:"INVOICE_SELLER_NAME"`
+	InvoiceSellerAddress   string   `mapstructure:"INVOICE_SELLER_ADDRESS" json:"INVOICE_SELLER_ADDRESS"`
+	InvoiceSellerEmail     string   `mapstructure:"INVOICE_SELLER_EMAIL" json:"INVOICE_SELLER_EMAIL"`
+	InvoiceSellerPhone     string   `mapstructure:"INVOICE_SELLER_PHONE" json:"INVOICE_SELLER_PHONE"`
+	InvoiceSellerTaxID     string   `mapstructure:"INVOICE_SELLER_TAX_ID" json:"INVOICE_SELLER_TAX_ID"`
+	InvoiceSellerCountry   string   `mapstructure:"INVOICE_SELLER_COUNTRY" json:"INVOICE_SELLER_COUNTRY"`
+	InvoiceUBLAttach       string   `mapstructure:"INVOICE_UBL_ATTACH" json:"INVOICE_UBL_ATTACH"`
+	InvoiceUBLEmbed        string   `mapstructure:"INVOICE_UBL_EMBED" json:"INVOICE_UBL_EMBED"`
+	SmtpHost               string   `mapstructure:"SMTP_HOST" json:"SMTP_HOST"`
+	SmtpPort               string   `mapstructure:"SMTP_PORT" json:"SMTP_PORT"`
+	SmtpUsername           string   `mapstructure:"SMTP_USERNAME" json:"SMTP_USERNAME"`
+	SmtpPassword           string   `mapstructure:"SMTP_PASSWORD" json:"SMTP_PASSWORD"`
+	SmtpPoolSize           string   `mapstructure:"SMTP_POOL_SIZE" json:"SMTP_POOL_SIZE"`
+	MailDriver             string   `mapstructure:"MAIL_DRIVER" json:"MAIL_DRIVER"`
+	MailDir                string   `mapstructure:"MAIL_DIR" json:"MAIL_DIR"`
+}
+
+// Redacted returns config with its secrets masked, so it can be logged
+func (config Config) Redacted() Config {
+	config.InvoiceDBSource = redact.URL(config.InvoiceDBSource)
+	config.InvoiceS3SecretKey = redact.Secret(config.InvoiceS3SecretKey)
+	config.InvoiceServiceSecret = redact.Secret(config.InvoiceServiceSecret)
+	config.SmtpPassword = redact.Secret(config.SmtpPassword)
+	return config
 }
 
 // LoadConfig reads configuration from file or environment variables.
@@ -46,6 +80,7 @@ func LoadConfig(ctx context.Context, path string) (Config, error) {
 		if err != nil {
 			return config, fmt.Errorf("viper unmarshal error: %w", err)
 		}
+		config.Environment = environment
 		return config, nil
 
 	case "staging", "production":
@@ -66,6 +101,7 @@ func LoadConfig(ctx context.Context, path string) (Config, error) {
 		if err != nil {
 			return config, fmt.Errorf("unmarshal secret: %w", err)
 		}
+		config.Environment = environment
 		return config, nil
 
 	default:
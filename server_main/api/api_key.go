@@ -0,0 +1,111 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/validator"
+	"github.com/go-chi/chi/v5"
+)
+
+// AllAPIKeys returns all API keys as JSON. Plain text keys are not included.
+func (server *Server) AllAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := server.DB.GetAllAPIKeys()
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	var resp struct {
+		Keys   []*models.APIKey `json:"api_keys"`
+		Scopes []string         `json:"scopes"`
+	}
+
+	resp.Keys = keys
+	resp.Scopes = models.APIKeyScopes
+
+	_ = server.writeJSON(w, http.StatusOK, resp)
+}
+
+// CreateAPIKey creates an API key and returns it. This is the only time the plain text key is available.
+func (server *Server) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Name          string   `json:"name"`
+		Scopes        []string `json:"scopes"`
+		ExpiresInDays int      `json:"expires_in_days"`
+	}
+
+	err := server.readJSON(w, r, &payload)
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	v.Check(payload.Name != "", "name", "must be provided")
+	v.Check(len(payload.Scopes) > 0, "scopes", "at least one scope is required")
+	v.Check(payload.ExpiresInDays >= 0, "expires_in_days", "must not be negative")
+	for _, scope := range payload.Scopes {
+		v.Check(slices.Contains(models.APIKeyScopes, scope), "scopes", fmt.Sprintf("unknown scope %q", scope))
+	}
+
+	if !v.Valid() {
+		server.failedValidation(w, r, v.Errors)
+		return
+	}
+
+	key, err := models.GenerateAPIKey(payload.Name, payload.Scopes, time.Duration(payload.ExpiresInDays)*24*time.Hour)
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	user := server.authenticatedUser(r)
+
+	key.ID, err = server.DB.InsertAPIKey(key, user.ID)
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	key.CreatedByUserID = user.ID
+	key.CreatedAt = time.Now()
+
+	audited := *key
+	audited.PlainText = ""
+	server.audit(r, "api_key.create", "api_key", key.ID, nil, audited)
+
+	_ = server.writeJSON(w, http.StatusCreated, key)
+}
+
+// RevokeAPIKey revokes an API key by id (from the url)
+func (server *Server) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	keyID, _ := strconv.Atoi(id)
+
+	err := server.DB.RevokeAPIKey(keyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = server.badRequest(w, r, errors.New("no active api key with that id"))
+		return
+	} else if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	server.audit(r, "api_key.revoke", "api_key", keyID, nil, nil)
+
+	var resp struct {
+		Error   bool   `json:"error"`
+		Message string `json:"message"`
+	}
+
+	resp.Error = false
+	resp.Message = "api key revoked"
+	_ = server.writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/throttle"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog/log"
+)
+
+// auditEventInserter appends events to the audit log.
+// Having this interface allows the use of gomock in tests.
+type auditEventInserter interface {
+	InsertAuditEvent(models.AuditEvent) error
+}
+
+// newAuditEvent builds an audit event for an admin mutation made by request r, authenticated
+// as actor or with key. before and after are marshalled to JSON; nil means there is no such state.
+func newAuditEvent(r *http.Request, actor *models.User, key *models.APIKey, action, targetType, targetID string, before, after interface{}) (models.AuditEvent, error) {
+	e := models.AuditEvent{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		IP:         throttle.ClientIP(r),
+		RequestID:  middleware.GetReqID(r.Context()),
+	}
+
+	if actor != nil {
+		e.ActorUserID = actor.ID
+	}
+	if key != nil {
+		e.ActorAPIKeyID = key.ID
+	}
+
+	var err error
+	if before != nil {
+		if e.Before, err = json.Marshal(before); err != nil {
+			return e, err
+		}
+	}
+	if after != nil {
+		if e.After, err = json.Marshal(after); err != nil {
+			return e, err
+		}
+	}
+
+	return e, nil
+}
+
+// recordAuditEvent builds and stores an audit event through the provided interface
+func recordAuditEvent(db auditEventInserter, r *http.Request, actor *models.User, key *models.APIKey, action, targetType, targetID string, before, after interface{}) error {
+	e, err := newAuditEvent(r, actor, key, action, targetType, targetID, before, after)
+	if err != nil {
+		return err
+	}
+	return db.InsertAuditEvent(e)
+}
+
+// audit records an admin mutation made by the authenticated user or API key. The mutation has
+// already happened by the time this is called, so failures are not returned; they are logged
+// and counted, and the count is reported by the health check.
+func (server *Server) audit(r *http.Request, action, targetType string, targetID int, before, after interface{}) {
+	err := recordAuditEvent(server.DB, r, server.authenticatedUser(r), server.authenticatedAPIKey(r), action, targetType, strconv.Itoa(targetID), before, after)
+	if err != nil {
+		failures := server.auditFailures.Add(1)
+		log.Error().Err(err).Str("action", action).Int64("audit_failures", failures).Msg("audit")
+	}
+}
+
+// AuditEvents returns a paginated, filtered listing of the audit log
+func (server *Server) AuditEvents(w http.ResponseWriter, r *http.Request) {
+	pageSize := 10   // default
+	currentPage := 1 // default
+
+	// Parse query params
+	query := r.URL.Query()
+	if val := query.Get("page_size"); val != "" {
+		if ps, err := strconv.Atoi(val); err == nil && ps > 0 {
+			pageSize = ps
+		} else {
+			_ = server.badRequest(w, r, errors.New("invalid page_size"))
+			return
+		}
+	}
+	if val := query.Get("page"); val != "" {
+		if cp, err := strconv.Atoi(val); err == nil && cp > 0 {
+			currentPage = cp
+		} else {
+			_ = server.badRequest(w, r, errors.New("invalid page"))
+			return
+		}
+	}
+
+	filter := models.AuditFilter{
+		Action:     query.Get("action"),
+		TargetType: query.Get("target_type"),
+		TargetID:   query.Get("target_id"),
+	}
+
+	if val := query.Get("actor_user_id"); val != "" {
+		id, err := strconv.Atoi(val)
+		if err != nil {
+			_ = server.badRequest(w, r, errors.New("invalid actor_user_id"))
+			return
+		}
+		filter.ActorUserID = id
+	}
+	if val := query.Get("actor_api_key_id"); val != "" {
+		id, err := strconv.Atoi(val)
+		if err != nil {
+			_ = server.badRequest(w, r, errors.New("invalid actor_api_key_id"))
+			return
+		}
+		filter.ActorAPIKeyID = id
+	}
+	if val := query.Get("from"); val != "" {
+		from, err := time.Parse(time.RFC3339, val)
+		if err != nil {
+			_ = server.badRequest(w, r, errors.New("invalid from, expected RFC 3339"))
+			return
+		}
+		filter.From = from
+	}
+	if val := query.Get("to"); val != "" {
+		to, err := time.Parse(time.RFC3339, val)
+		if err != nil {
+			_ = server.badRequest(w, r, errors.New("invalid to, expected RFC 3339"))
+			return
+		}
+		filter.To = to
+	}
+
+	events, lastPage, totalRecords, err := server.DB.GetAuditEventsPaginated(filter, pageSize, currentPage)
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	var resp struct {
+		CurrentPage  int                  `json:"current_page"`
+		PageSize     int                  `json:"page_size"`
+		LastPage     int                  `json:"last_page"`
+		TotalRecords int                  `json:"total_records"`
+		Events       []*models.AuditEvent `json:"events"`
+	}
+
+	resp.CurrentPage = currentPage
+	resp.PageSize = pageSize
+	resp.LastPage = lastPage
+	resp.TotalRecords = totalRecords
+	resp.Events = events
+
+	_ = server.writeJSON(w, http.StatusOK, resp)
+}
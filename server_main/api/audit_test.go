@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRecordAuditEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockauditEventInserter(ctrl)
+
+	r := httptest.NewRequest("DELETE", "/api/v1/admin/all-users/delete/7", nil)
+	r.RemoteAddr = "192.0.2.10:5555"
+	r = r.WithContext(context.WithValue(r.Context(), middleware.RequestIDKey, "req-1"))
+
+	actor := &models.User{ID: 3}
+	before := models.User{ID: 7, FirstName: "Jane"}
+
+	mockDB.EXPECT().InsertAuditEvent(models.AuditEvent{
+		ActorUserID: 3,
+		Action:      "user.delete",
+		TargetType:  "user",
+		TargetID:    "7",
+		Before:      []byte(`{"id":7,"first_name":"Jane","last_name":"","email":"","password":"","role":"","email_verified":false}`),
+		IP:          "192.0.2.10",
+		RequestID:   "req-1",
+	}).Return(nil)
+
+	err := recordAuditEvent(mockDB, r, actor, nil, "user.delete", "user", "7", before, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestRecordAuditEventAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockauditEventInserter(ctrl)
+
+	r := httptest.NewRequest("POST", "/api/v1/admin/refund", nil)
+	r.RemoteAddr = "192.0.2.20:5555"
+
+	// requests made with an API key have no user, so the key is recorded instead
+	mockDB.EXPECT().InsertAuditEvent(models.AuditEvent{
+		ActorAPIKeyID: 5,
+		Action:        "order.refund",
+		TargetType:    "order",
+		TargetID:      "1",
+		IP:            "192.0.2.20",
+	}).Return(nil)
+
+	err := recordAuditEvent(mockDB, r, nil, &models.APIKey{ID: 5}, "order.refund", "order", "1", nil, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestRecordAuditEventError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockauditEventInserter(ctrl)
+	mockErr := errors.New("insert failed")
+	mockDB.EXPECT().InsertAuditEvent(gomock.Any()).Return(mockErr)
+
+	r := httptest.NewRequest("POST", "/api/v1/admin/refund", nil)
+
+	err := recordAuditEvent(mockDB, r, nil, nil, "order.refund", "order", "1", nil, nil)
+	if !errors.Is(err, mockErr) {
+		t.Fatalf("expected insert error, got %v", err)
+	}
+}
+
+func TestAuditFailuresAreReported(t *testing.T) {
+	db, err := sql.Open("pgx", "postgres://localhost/yoyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = db.Close()
+
+	server := &Server{DB: &models.DBModel{DB: db}}
+	server.audit(httptest.NewRequest("POST", "/api/v1/admin/refund", nil), "order.refund", "order", 1, nil, nil)
+
+	w := httptest.NewRecorder()
+	server.handleHealthCheck(w, httptest.NewRequest("GET", "/api/v1/health", nil))
+
+	var health struct {
+		AuditFailures int `json:"audit_failures"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &health); err != nil {
+		t.Fatal(err)
+	}
+	if health.AuditFailures != 1 {
+		t.Fatalf("expected 1 audit failure to be reported, got %d", health.AuditFailures)
+	}
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/pb"
+	"github.com/go-chi/chi/v5"
+	"google.golang.org/grpc/status"
+)
+
+// creditRefund asks the invoicing microservice to issue and email a credit note for a refund
+// of an order, returning the credit note number
+func (server *Server) creditRefund(ctx context.Context, orderID, amount int, reason, refundID string) (string, error) {
+	clientConn, err := server.dialInvoiceMicro()
+	if err != nil {
+		return "", err
+	}
+	defer clientConn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	return requestCreditNote(ctx, pb.NewInvoiceServiceClient(clientConn), orderID, amount, reason, refundID)
+}
+
+// requestCreditNote asks for a credit note against the invoice of an order. The refund id is
+// the reference, so retrying for the same refund never issues a second credit note.
+func requestCreditNote(ctx context.Context, client pb.InvoiceServiceClient, orderID, amount int, reason, refundID string) (string, error) {
+	if reason == "" {
+		reason = "Refund"
+	}
+
+	cn, err := client.CreateCreditNote(ctx, &pb.CreateCreditNoteRequest{
+		OrderId:   int32(orderID),
+		Amount:    int32(amount),
+		Reason:    reason,
+		Reference: refundID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return cn.GetNumber(), nil
+}
+
+// CreateCreditNote issues a credit note against an invoice, for refunds made outside of the
+// refund endpoint or whose credit note could not be issued at the time
+func (server *Server) CreateCreditNote(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Amount    int    `json:"amount"`
+		Reason    string `json:"reason"`
+		Reference string `json:"reference"`
+	}
+
+	if err := server.readJSON(w, r, &payload); err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	if payload.Amount <= 0 || payload.Reason == "" || payload.Reference == "" {
+		_ = server.badRequest(w, r, errors.New("an amount, reason and reference (such as the refund id) are required"))
+		return
+	}
+
+	clientConn, err := server.dialInvoiceMicro()
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+	defer clientConn.Close()
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	cn, err := pb.NewInvoiceServiceClient(clientConn).CreateCreditNote(ctx, &pb.CreateCreditNoteRequest{
+		InvoiceNumber: chi.URLParam(r, "number"),
+		Amount:        int32(payload.Amount),
+		Reason:        payload.Reason,
+		Reference:     payload.Reference,
+	})
+	if err != nil {
+		_ = server.badRequest(w, r, errors.New(status.Convert(err).Message()))
+		return
+	}
+
+	server.audit(r, "credit_note.create", "credit_note", int(cn.GetId()), nil, cn)
+
+	_ = server.writeJSON(w, http.StatusCreated, cn)
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/LamThanhNguyen/yoyo-store-backend/internal/pb"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRequestCreditNote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := pb.NewMockInvoiceServiceClient(ctrl)
+	mockClient.EXPECT().CreateCreditNote(gomock.Any(), &pb.CreateCreditNoteRequest{
+		OrderId:   7,
+		Amount:    1500,
+		Reason:    "Refund",
+		Reference: "re_123",
+	}).Return(&pb.CreditNote{Number: "CN-2026-000001"}, nil)
+
+	number, err := requestCreditNote(context.Background(), mockClient, 7, 1500, "", "re_123")
+	if err != nil {
+		t.Fatalf("requestCreditNote returned error: %v", err)
+	}
+	if number != "CN-2026-000001" {
+		t.Fatalf("expected credit note number CN-2026-000001, got %q", number)
+	}
+}
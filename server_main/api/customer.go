@@ -10,11 +10,12 @@ type customerInserter interface {
 
 // saveCustomer inserts a new customer using the provided database interface.
 // This helper exists so that we can easily mock database interactions in tests.
-func saveCustomer(db customerInserter, firstName, lastName, email string) (int, error) {
+func saveCustomer(db customerInserter, firstName, lastName, email, locale string) (int, error) {
 	customer := models.Customer{
 		FirstName: firstName,
 		LastName:  lastName,
 		Email:     email,
+		Locale:    locale,
 	}
 
 	return db.InsertCustomer(customer)
@@ -23,7 +24,8 @@ func saveCustomer(db customerInserter, firstName, lastName, email string) (int,
 func (server *Server) SaveCustomer(
 	firstName,
 	lastName,
-	email string,
+	email,
+	locale string,
 ) (int, error) {
-	return saveCustomer(server.DB, firstName, lastName, email)
+	return saveCustomer(server.DB, firstName, lastName, email, locale)
 }
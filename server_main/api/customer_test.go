@@ -14,12 +14,12 @@ func TestSaveCustomer(t *testing.T) {
 
 	mockDB := NewMockcustomerInserter(ctrl)
 
-	fname, lname, email := "John", "Doe", "john@example.com"
-	expected := models.Customer{FirstName: fname, LastName: lname, Email: email}
+	fname, lname, email, locale := "John", "Doe", "john@example.com", "de"
+	expected := models.Customer{FirstName: fname, LastName: lname, Email: email, Locale: locale}
 
 	mockDB.EXPECT().InsertCustomer(expected).Return(1, nil)
 
-	id, err := saveCustomer(mockDB, fname, lname, email)
+	id, err := saveCustomer(mockDB, fname, lname, email, locale)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -34,12 +34,12 @@ func TestSaveCustomerError(t *testing.T) {
 
 	mockDB := NewMockcustomerInserter(ctrl)
 
-	fname, lname, email := "Jane", "Smith", "jane@example.com"
-	expected := models.Customer{FirstName: fname, LastName: lname, Email: email}
+	fname, lname, email, locale := "Jane", "Smith", "jane@example.com", "en"
+	expected := models.Customer{FirstName: fname, LastName: lname, Email: email, Locale: locale}
 	mockErr := errors.New("insert failed")
 	mockDB.EXPECT().InsertCustomer(expected).Return(0, mockErr)
 
-	id, err := saveCustomer(mockDB, fname, lname, email)
+	id, err := saveCustomer(mockDB, fname, lname, email, locale)
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -0,0 +1,534 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/i18n"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/mailer"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/pb"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/status"
+)
+
+// Email templates live in the service that sends them: the main server has its own, and the
+// invoice service has the invoice and credit note emails, which are reached over gRPC.
+const (
+	emailServiceMain    = "main"
+	emailServiceInvoice = "invoice"
+)
+
+// emailSample is what an email template is previewed with: the catalog key of its subject,
+// and data shaped like what the template gets when it is sent for real
+type emailSample struct {
+	subject string
+	data    interface{}
+}
+
+var emailSamples = map[string]emailSample{
+	"email-verification": {
+		subject: "email.verification.subject",
+		data: struct {
+			FirstName string
+			Link      string
+		}{"Jane", "https://example.com/verify-email?id=1&email=jane%40example.com"},
+	},
+	"password-reset": {
+		subject: "email.password_reset.subject",
+		data: struct {
+			Link string
+		}{"https://example.com/reset-password?token=sample"},
+	},
+	"password-changed": {
+		subject: "email.password_changed.subject",
+		data: struct {
+			FirstName string
+			Link      string
+		}{"Jane", "https://example.com/forgot-password"},
+	},
+	"order-confirmation": {
+		subject: "email.order_confirmation.subject",
+		data:    notification{FirstName: "Jane", OrderID: 1, Product: "Bronze Plan monthly subscription", Amount: 2000, Currency: "usd"},
+	},
+	"refund-issued": {
+		subject: "email.refund_issued.subject",
+		data:    notification{FirstName: "Jane", OrderID: 1, Amount: 2000, Currency: "usd", LastFour: "4242"},
+	},
+	"subscription-cancelled": {
+		subject: "email.subscription_cancelled.subject",
+		data:    notification{FirstName: "Jane", OrderID: 1, Product: "Bronze Plan", EndsAt: time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)},
+	},
+	"payment-failed": {
+		subject: "email.payment_failed.subject",
+		data: notification{
+			FirstName: "Jane",
+			Product:   "Bronze Plan monthly subscription",
+			Amount:    2000,
+			Currency:  "usd",
+			Reason:    "Your card was declined",
+			Link:      "https://example.com/plans/bronze",
+		},
+	},
+}
+
+// emailTemplate is one email in the list of templates
+type emailTemplate struct {
+	Service string   `json:"service"`
+	Name    string   `json:"name"`
+	Locales []string `json:"locales"`
+}
+
+// previewEmail renders one of the main server's email templates with its sample data
+func (server *Server) previewEmail(name, locale string) (*pb.EmailPreview, emailSample, error) {
+	sample, ok := emailSamples[name]
+	if !ok || !server.templates.Exists(name) {
+		return nil, sample, fmt.Errorf("no email template %q", name)
+	}
+
+	c := i18n.For(locale)
+	html, plain, err := server.templates.Render(name, c.Locale(), sample.data)
+	if err != nil {
+		return nil, sample, fmt.Errorf("email template %s: %w", name, err)
+	}
+
+	return &pb.EmailPreview{
+		Name:    name,
+		Locale:  c.Locale(),
+		Subject: c.T(sample.subject),
+		Html:    html,
+		Plain:   plain,
+	}, sample, nil
+}
+
+// emailTemplateService reads and edits the email templates of one service
+type emailTemplateService interface {
+	List(ctx context.Context) ([]mailer.TemplateInfo, error)
+	Preview(ctx context.Context, name, locale string) (*pb.EmailPreview, error)
+	SendTest(ctx context.Context, name, locale, to string) error
+	Source(ctx context.Context, name, locale string) (mailer.Source, error)
+	Versions(ctx context.Context, name, locale string) ([]models.EmailTemplateVersion, error)
+	Save(ctx context.Context, name, locale, html, plain, author string) (models.EmailTemplateVersion, error)
+	Restore(ctx context.Context, name, locale string, version int, author string) (models.EmailTemplateVersion, error)
+}
+
+// emailTemplateService returns the email templates of service, and a func to call once they are
+// no longer needed
+func (server *Server) emailTemplateService(service string) (emailTemplateService, func(), error) {
+	switch service {
+	case emailServiceMain:
+		return localEmailTemplates{server}, func() {}, nil
+
+	case emailServiceInvoice:
+		clientConn, err := server.dialInvoiceMicro()
+		if err != nil {
+			return nil, nil, err
+		}
+		return remoteEmailTemplates{pb.NewInvoiceServiceClient(clientConn)}, func() { clientConn.Close() }, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unknown service %q", service)
+	}
+}
+
+// localEmailTemplates are the main server's own email templates
+type localEmailTemplates struct {
+	server *Server
+}
+
+func (l localEmailTemplates) List(ctx context.Context) ([]mailer.TemplateInfo, error) {
+	return l.server.templates.List()
+}
+
+func (l localEmailTemplates) Preview(ctx context.Context, name, locale string) (*pb.EmailPreview, error) {
+	preview, _, err := l.server.previewEmail(name, locale)
+	return preview, err
+}
+
+func (l localEmailTemplates) SendTest(ctx context.Context, name, locale, to string) error {
+	preview, sample, err := l.server.previewEmail(name, locale)
+	if err != nil {
+		return err
+	}
+
+	return l.server.SendMail("info@yoyo.com", to, "[Test] "+preview.Subject, name, preview.Locale, sample.data)
+}
+
+func (l localEmailTemplates) Source(ctx context.Context, name, locale string) (mailer.Source, error) {
+	return l.server.templates.Source(name, locale)
+}
+
+func (l localEmailTemplates) Versions(ctx context.Context, name, locale string) ([]models.EmailTemplateVersion, error) {
+	return l.server.templates.Versions(name, locale)
+}
+
+func (l localEmailTemplates) Save(ctx context.Context, name, locale, html, plain, author string) (models.EmailTemplateVersion, error) {
+	return l.server.templates.Save(name, locale, html, plain, author, emailSamples[name].data)
+}
+
+func (l localEmailTemplates) Restore(ctx context.Context, name, locale string, version int, author string) (models.EmailTemplateVersion, error) {
+	return l.server.templates.Restore(name, locale, version, author)
+}
+
+// remoteEmailTemplates are the invoice service's email templates, reached over gRPC. Errors
+// are reduced to their status message.
+type remoteEmailTemplates struct {
+	client pb.InvoiceServiceClient
+}
+
+func (r remoteEmailTemplates) List(ctx context.Context) ([]mailer.TemplateInfo, error) {
+	resp, err := r.client.ListEmailTemplates(ctx, &pb.ListEmailTemplatesRequest{})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	var templates []mailer.TemplateInfo
+	for _, t := range resp.GetTemplates() {
+		templates = append(templates, mailer.TemplateInfo{Name: t.Name, Locales: t.Locales})
+	}
+	return templates, nil
+}
+
+func (r remoteEmailTemplates) Preview(ctx context.Context, name, locale string) (*pb.EmailPreview, error) {
+	preview, err := r.client.PreviewEmailTemplate(ctx, &pb.PreviewEmailTemplateRequest{Name: name, Locale: locale})
+	return preview, statusError(err)
+}
+
+func (r remoteEmailTemplates) SendTest(ctx context.Context, name, locale, to string) error {
+	_, err := r.client.SendTestEmail(ctx, &pb.SendTestEmailRequest{Name: name, Locale: locale, Email: to})
+	return statusError(err)
+}
+
+func (r remoteEmailTemplates) Source(ctx context.Context, name, locale string) (mailer.Source, error) {
+	src, err := r.client.GetEmailTemplateSource(ctx, &pb.EmailTemplateRequest{Name: name, Locale: locale})
+	if err != nil {
+		return mailer.Source{}, statusError(err)
+	}
+
+	return mailer.Source{
+		Name:    src.Name,
+		Locale:  src.Locale,
+		Version: int(src.Version),
+		HTML:    src.Html,
+		Plain:   src.Plain,
+	}, nil
+}
+
+func (r remoteEmailTemplates) Versions(ctx context.Context, name, locale string) ([]models.EmailTemplateVersion, error) {
+	resp, err := r.client.ListEmailTemplateVersions(ctx, &pb.EmailTemplateRequest{Name: name, Locale: locale})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	var versions []models.EmailTemplateVersion
+	for _, v := range resp.GetVersions() {
+		versions = append(versions, emailTemplateVersionFromPB(v))
+	}
+	return versions, nil
+}
+
+func (r remoteEmailTemplates) Save(ctx context.Context, name, locale, html, plain, author string) (models.EmailTemplateVersion, error) {
+	v, err := r.client.SaveEmailTemplate(ctx, &pb.SaveEmailTemplateRequest{
+		Name:   name,
+		Locale: locale,
+		Html:   html,
+		Plain:  plain,
+		Author: author,
+	})
+	if err != nil {
+		return models.EmailTemplateVersion{}, statusError(err)
+	}
+	return emailTemplateVersionFromPB(v), nil
+}
+
+func (r remoteEmailTemplates) Restore(ctx context.Context, name, locale string, version int, author string) (models.EmailTemplateVersion, error) {
+	v, err := r.client.RestoreEmailTemplate(ctx, &pb.RestoreEmailTemplateRequest{
+		Name:    name,
+		Locale:  locale,
+		Version: int32(version),
+		Author:  author,
+	})
+	if err != nil {
+		return models.EmailTemplateVersion{}, statusError(err)
+	}
+	return emailTemplateVersionFromPB(v), nil
+}
+
+// statusError reduces a gRPC error to its status message, for the admin UI
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return errors.New(status.Convert(err).Message())
+}
+
+func emailTemplateVersionFromPB(v *pb.EmailTemplateVersion) models.EmailTemplateVersion {
+	return models.EmailTemplateVersion{
+		ID:          int(v.Id),
+		Name:        v.Name,
+		Locale:      v.Locale,
+		Version:     int(v.Version),
+		HTML:        v.Html,
+		Plain:       v.Plain,
+		UsesDefault: v.UsesDefault,
+		CreatedBy:   v.CreatedBy,
+		CreatedAt:   v.CreatedAt.AsTime(),
+	}
+}
+
+// AllEmailTemplates lists the email templates of the main server and the invoice service
+func (server *Server) AllEmailTemplates(w http.ResponseWriter, r *http.Request) {
+	var resp struct {
+		Templates []emailTemplate `json:"templates"`
+		Locales   []string        `json:"locales"`
+		Message   string          `json:"message,omitempty"`
+	}
+	resp.Locales = i18n.Supported()
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	for _, service := range []string{emailServiceMain, emailServiceInvoice} {
+		templates, err := server.listEmailTemplates(ctx, service)
+		if err != nil && service == emailServiceMain {
+			_ = server.badRequest(w, r, err)
+			return
+		} else if err != nil {
+			// the main server's templates can still be used while the invoice service is down
+			log.Error().Err(err).Msg("AllEmailTemplates")
+			resp.Message = fmt.Sprintf("the %s service's templates could not be listed: %s", service, err)
+			continue
+		}
+
+		for _, t := range templates {
+			resp.Templates = append(resp.Templates, emailTemplate{Service: service, Name: t.Name, Locales: t.Locales})
+		}
+	}
+
+	_ = server.writeJSON(w, http.StatusOK, resp)
+}
+
+func (server *Server) listEmailTemplates(ctx context.Context, service string) ([]mailer.TemplateInfo, error) {
+	svc, done, err := server.emailTemplateService(service)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	return svc.List(ctx)
+}
+
+// PreviewEmailTemplate renders an email template with sample data. With ?format=html or
+// ?format=plain it returns that body as is; otherwise both, and the subject, as JSON.
+func (server *Server) PreviewEmailTemplate(w http.ResponseWriter, r *http.Request) {
+	svc, done, err := server.emailTemplateService(chi.URLParam(r, "service"))
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+	defer done()
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	preview, err := svc.Preview(ctx, chi.URLParam(r, "name"), r.URL.Query().Get("locale"))
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "html":
+		// templates are edited in the admin area, so a preview opened on the API's origin
+		// must not run scripts or reach its cookies
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Security-Policy", "sandbox")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		_, _ = w.Write([]byte(preview.Html))
+	case "plain":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		_, _ = w.Write([]byte(preview.Plain))
+	default:
+		_ = server.writeJSON(w, http.StatusOK, preview)
+	}
+}
+
+// SendTestEmail sends an email template, rendered with sample data, to the admin asking for it
+func (server *Server) SendTestEmail(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Locale string `json:"locale"`
+	}
+
+	err := server.readJSON(w, r, &payload)
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	svc, done, err := server.emailTemplateService(chi.URLParam(r, "service"))
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+	defer done()
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	name, to := chi.URLParam(r, "name"), server.authenticatedUser(r).Email
+	if err := svc.SendTest(ctx, name, payload.Locale, to); err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	resp := jsonResponse{
+		OK:      true,
+		Message: fmt.Sprintf("Test email %s sent to %s", name, to),
+	}
+
+	_ = server.writeJSON(w, http.StatusOK, resp)
+}
+
+// EmailTemplateSource returns the text of an email template in use for ?locale=, for editing.
+// An empty locale is the default template.
+func (server *Server) EmailTemplateSource(w http.ResponseWriter, r *http.Request) {
+	svc, done, err := server.emailTemplateService(chi.URLParam(r, "service"))
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+	defer done()
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	src, err := svc.Source(ctx, chi.URLParam(r, "name"), r.URL.Query().Get("locale"))
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	_ = server.writeJSON(w, http.StatusOK, src)
+}
+
+// EmailTemplateVersions lists the saved edits of an email template for ?locale=, newest first
+func (server *Server) EmailTemplateVersions(w http.ResponseWriter, r *http.Request) {
+	svc, done, err := server.emailTemplateService(chi.URLParam(r, "service"))
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+	defer done()
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	versions, err := svc.Versions(ctx, chi.URLParam(r, "name"), r.URL.Query().Get("locale"))
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	var resp struct {
+		Versions []models.EmailTemplateVersion `json:"versions"`
+	}
+	resp.Versions = versions
+
+	_ = server.writeJSON(w, http.StatusOK, resp)
+}
+
+// SaveEmailTemplate saves an edit of an email template as its new version, once it parses and
+// renders with the template's sample data
+func (server *Server) SaveEmailTemplate(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Locale string `json:"locale"`
+		HTML   string `json:"html"`
+		Plain  string `json:"plain"`
+	}
+
+	err := server.readJSON(w, r, &payload)
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	svc, done, err := server.emailTemplateService(chi.URLParam(r, "service"))
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+	defer done()
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	name := chi.URLParam(r, "name")
+	before, err := svc.Source(ctx, name, payload.Locale)
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	v, err := svc.Save(ctx, name, payload.Locale, payload.HTML, payload.Plain, server.authenticatedUser(r).Email)
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	server.audit(r, "email_template.save", "email_template", v.ID, before, v)
+
+	_ = server.writeJSON(w, http.StatusOK, v)
+}
+
+// RestoreEmailTemplate puts an earlier version of an email template back in use. Version 0 is
+// the template compiled into the service.
+func (server *Server) RestoreEmailTemplate(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Locale string `json:"locale"`
+	}
+
+	err := server.readJSON(w, r, &payload)
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || version < 0 {
+		_ = server.badRequest(w, r, errors.New("invalid version"))
+		return
+	}
+
+	svc, done, err := server.emailTemplateService(chi.URLParam(r, "service"))
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+	defer done()
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	name := chi.URLParam(r, "name")
+	before, err := svc.Source(ctx, name, payload.Locale)
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	v, err := svc.Restore(ctx, name, payload.Locale, version, server.authenticatedUser(r).Email)
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	server.audit(r, "email_template.restore", "email_template", v.ID, before, v)
+
+	_ = server.writeJSON(w, http.StatusOK, v)
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/i18n"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/mailer"
+	"github.com/go-chi/chi/v5"
+)
+
+func TestEmailSamples(t *testing.T) {
+	server := &Server{templates: mailer.NewTemplates(emailTemplateFS, "templates", nil)}
+
+	templates, err := server.templates.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != len(emailSamples) {
+		t.Errorf("expected a sample for each of the %d templates, got %d samples", len(templates), len(emailSamples))
+	}
+
+	for _, tmpl := range templates {
+		for _, locale := range i18n.Supported() {
+			preview, _, err := server.previewEmail(tmpl.Name, locale)
+			if err != nil {
+				t.Fatalf("%s in %s: %v", tmpl.Name, locale, err)
+			}
+			if preview.Locale != locale || preview.Html == "" || preview.Plain == "" || strings.HasPrefix(preview.Subject, "email.") {
+				t.Errorf("%s in %s: incomplete preview %+v", tmpl.Name, locale, preview)
+			}
+		}
+	}
+
+	if _, _, err := server.previewEmail("no-such-email", "en"); err == nil {
+		t.Error("expected an error for an unknown template")
+	}
+}
+
+func TestPreviewEmailTemplate(t *testing.T) {
+	server := &Server{templates: mailer.NewTemplates(emailTemplateFS, "templates", nil)}
+
+	tests := []struct {
+		format      string
+		contentType string
+		csp         string
+		want        string
+	}{
+		{format: "plain", contentType: "text/plain", want: "Hallo Jane,"},
+		{format: "html", contentType: "text/html", csp: "sandbox", want: "Jane"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("service", emailServiceMain)
+			rctx.URLParams.Add("name", "password-changed")
+
+			r := httptest.NewRequest(http.MethodGet, "/?locale=de-DE&format="+tt.format, nil)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			server.PreviewEmailTemplate(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, tt.contentType) {
+				t.Errorf("expected %s, got %s", tt.contentType, ct)
+			}
+			if csp := w.Header().Get("Content-Security-Policy"); csp != tt.csp {
+				t.Errorf("expected Content-Security-Policy %q, got %q", tt.csp, csp)
+			}
+			if nosniff := w.Header().Get("X-Content-Type-Options"); nosniff != "nosniff" {
+				t.Errorf("expected X-Content-Type-Options nosniff, got %q", nosniff)
+			}
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("expected the German template with sample data, got:\n%s", w.Body.String())
+			}
+		})
+	}
+}
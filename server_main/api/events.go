@@ -0,0 +1,31 @@
+package api
+
+import (
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/events"
+	"github.com/rs/zerolog/log"
+)
+
+// eventPublisher publishes domain events to the other services.
+// Having this interface allows the use of gomock in tests.
+type eventPublisher interface {
+	PublishEvent(events.Event) error
+}
+
+// publishEvent stamps e with the time, unless it has one, and publishes it through the
+// provided interface
+func publishEvent(db eventPublisher, e events.Event) error {
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+	return db.PublishEvent(e)
+}
+
+// publish tells the other services about something that has already happened, so failures
+// are logged, not returned
+func (server *Server) publish(e events.Event) {
+	if err := publishEvent(server.DB, e); err != nil {
+		log.Error().Err(err).Str("type", e.Type).Msg("publish")
+	}
+}
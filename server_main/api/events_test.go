@@ -0,0 +1,38 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/events"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPublishEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockeventPublisher(ctrl)
+
+	start := time.Now()
+	mockDB.EXPECT().PublishEvent(gomock.Any()).DoAndReturn(func(e events.Event) error {
+		if e.Type != events.UserDeleted || e.UserID != 7 {
+			t.Errorf("unexpected event %+v", e)
+		}
+		if e.At.Before(start) {
+			t.Errorf("expected the event to be stamped with the time, got %v", e.At)
+		}
+		return nil
+	})
+
+	if err := publishEvent(mockDB, events.Event{Type: events.UserDeleted, UserID: 7}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	at := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	mockDB.EXPECT().PublishEvent(events.Event{Type: events.RefundIssued, OrderID: 3, At: at}).Return(nil)
+
+	if err := publishEvent(mockDB, events.Event{Type: events.RefundIssued, OrderID: 3, At: at}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
@@ -2,59 +2,288 @@ package api
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
 	"github.com/LamThanhNguyen/yoyo-store-backend/internal/pb"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-// Invoice describes the JSON payload sent to the microservice
-type Invoice struct {
-	ID        int       `json:"id"`
-	ItemID    int       `json:"item_id"`
-	Amount    int       `json:"amount"`
-	Product   string    `json:"product"`
-	Quantity  int       `json:"quantity"`
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
-}
-
-// callInvoiceMicro calls the invoicing microservice
-func (server *Server) callInvoiceMicro(inv Invoice) error {
-	_, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// dialInvoiceMicro opens a client connection to the invoicing microservice
+func (server *Server) dialInvoiceMicro() (*grpc.ClientConn, error) {
+	return grpc.NewClient(server.config.InvoiceGrpcAddr, server.invoiceDialOpts...)
+}
+
+// sendInvoice asks the invoicing microservice to issue and email the invoice for an order,
+// returning the invoice number
+func sendInvoice(ctx context.Context, client pb.InvoiceServiceClient, inv models.InvoiceRequest) (string, error) {
+	resp, err := client.CreateAndSendInvoice(ctx, &pb.CreateInvoiceRequest{
+		Id:            int32(inv.OrderID),
+		Quantity:      int32(inv.Quantity),
+		Amount:        int32(inv.Amount),
+		Product:       inv.Product,
+		Currency:      inv.Currency,
+		CardBrand:     inv.CardBrand,
+		LastFour:      inv.LastFour,
+		FirstName:     inv.FirstName,
+		LastName:      inv.LastName,
+		Email:         inv.Email,
+		BuyerCountry:  inv.Country,
+		Locale:        inv.Locale,
+		CreatedAt:     timestamppb.New(inv.CreatedAt),
+		Discount:      int32(inv.Discount),
+		DiscountLabel: inv.DiscountLabel,
+		Shipping:      int32(inv.Shipping),
+		TaxRateBps:    int32(inv.TaxRateBps),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return resp.GetInvoiceNumber(), nil
+}
+
+// AllInvoices returns a page of invoices from the invoicing microservice, optionally filtered
+// by customer email or order id
+func (server *Server) AllInvoices(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &pb.ListInvoicesRequest{
+		Email:    query.Get("email"),
+		PageSize: 10, // default
+		Page:     1,  // default
+	}
+
+	if val := query.Get("order_id"); val != "" {
+		if id, err := strconv.Atoi(val); err == nil && id > 0 {
+			req.OrderId = int32(id)
+		} else {
+			_ = server.badRequest(w, r, errors.New("invalid order_id"))
+			return
+		}
+	}
+	if val := query.Get("page_size"); val != "" {
+		if ps, err := strconv.Atoi(val); err == nil && ps > 0 {
+			req.PageSize = int32(ps)
+		} else {
+			_ = server.badRequest(w, r, errors.New("invalid page_size"))
+			return
+		}
+	}
+	if val := query.Get("page"); val != "" {
+		if cp, err := strconv.Atoi(val); err == nil && cp > 0 {
+			req.Page = int32(cp)
+		} else {
+			_ = server.badRequest(w, r, errors.New("invalid page"))
+			return
+		}
+	}
+
+	clientConn, err := server.dialInvoiceMicro()
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+	defer clientConn.Close()
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
-	clientConn, err := grpc.NewClient(
-		server.config.InvoiceGrpcAddr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	resp, err := pb.NewInvoiceServiceClient(clientConn).ListInvoices(ctx, req)
+	if err != nil {
+		_ = server.badRequest(w, r, errors.New(status.Convert(err).Message()))
+		return
+	}
+
+	_ = server.writeJSON(w, http.StatusOK, resp)
+}
+
+// InvoicePDF streams the PDF of an invoice to the client
+func (server *Server) InvoicePDF(w http.ResponseWriter, r *http.Request) {
+	clientConn, err := server.dialInvoiceMicro()
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+	defer clientConn.Close()
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	err = server.writeInvoicePDF(ctx, w, pb.NewInvoiceServiceClient(clientConn), chi.URLParam(r, "number"))
+	if err != nil {
+		_ = server.badRequest(w, r, errors.New(status.Convert(err).Message()))
+		return
+	}
+}
+
+// InvoiceXML returns an invoice as a UBL 2.1 XML e-invoice
+func (server *Server) InvoiceXML(w http.ResponseWriter, r *http.Request) {
+	clientConn, err := server.dialInvoiceMicro()
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+	defer clientConn.Close()
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	doc, err := pb.NewInvoiceServiceClient(clientConn).GetInvoiceXML(ctx, &pb.GetInvoiceRequest{
+		Number: chi.URLParam(r, "number"),
+	})
+	if err != nil {
+		_ = server.badRequest(w, r, errors.New(status.Convert(err).Message()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", doc.GetFilename()))
+	_, _ = w.Write(doc.GetData())
+}
+
+// writeInvoicePDF copies the PDF of invoice number to w. Headers are only written once the
+// first chunk has arrived, so a failed lookup can still be reported as JSON.
+func (server *Server) writeInvoicePDF(ctx context.Context, w http.ResponseWriter, client pb.InvoiceServiceClient, number string) error {
+	stream, err := client.DownloadInvoicePDF(ctx, &pb.DownloadInvoicePDFRequest{Number: number})
 	if err != nil {
 		return err
 	}
+
+	started := false
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			if started {
+				// too late to report the error to the client, so just cut the download short
+				log.Error().Err(err).Str("number", number).Msg("writeInvoicePDF")
+				return nil
+			}
+			return err
+		}
+
+		if !started {
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", number+".pdf"))
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+
+		if _, err := w.Write(chunk.GetData()); err != nil {
+			log.Error().Err(err).Str("number", number).Msg("writeInvoicePDF")
+			return nil
+		}
+	}
+}
+
+// ResendInvoice emails an invoice again, to the customer or to the address in the request body
+func (server *Server) ResendInvoice(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Email string `json:"email"`
+	}
+
+	// the body is optional
+	if r.ContentLength != 0 {
+		if err := server.readJSON(w, r, &payload); err != nil {
+			_ = server.badRequest(w, r, err)
+			return
+		}
+	}
+
+	clientConn, err := server.dialInvoiceMicro()
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
 	defer clientConn.Close()
 
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
 	client := pb.NewInvoiceServiceClient(clientConn)
+	number := chi.URLParam(r, "number")
 
-	return server.sendInvoice(client, inv)
+	// the audit log records invoices by id
+	inv, err := client.GetInvoice(ctx, &pb.GetInvoiceRequest{Number: number})
+	if err != nil {
+		_ = server.badRequest(w, r, errors.New(status.Convert(err).Message()))
+		return
+	}
+
+	resp, err := client.ResendInvoice(ctx, &pb.ResendInvoiceRequest{
+		Number: number,
+		Email:  payload.Email,
+	})
+	if err != nil {
+		_ = server.badRequest(w, r, errors.New(status.Convert(err).Message()))
+		return
+	}
+
+	to := payload.Email
+	if to == "" {
+		to = inv.GetEmail()
+	}
+	server.audit(r, "invoice.resend", "invoice", int(inv.GetId()), nil, map[string]string{
+		"number": inv.GetNumber(),
+		"email":  to,
+	})
+
+	var out struct {
+		Error   bool   `json:"error"`
+		Message string `json:"message"`
+	}
+	out.Error = false
+	out.Message = resp.GetMessage()
+
+	_ = server.writeJSON(w, http.StatusOK, out)
 }
 
-func (server *Server) sendInvoice(client pb.InvoiceServiceClient, inv Invoice) error {
-	reqCtx, reqCancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer reqCancel()
+// VoidInvoice voids an invoice. The invoice keeps its number and stays listed.
+func (server *Server) VoidInvoice(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Reason string `json:"reason"`
+	}
+
+	if err := server.readJSON(w, r, &payload); err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	if payload.Reason == "" {
+		_ = server.badRequest(w, r, errors.New("a reason is required to void an invoice"))
+		return
+	}
 
-	_, err := client.CreateAndSendInvoice(reqCtx, &pb.CreateInvoiceRequest{
-		Id:        int32(inv.ID),
-		Quantity:  int32(inv.Quantity),
-		Amount:    int32(inv.Amount),
-		Product:   inv.Product,
-		FirstName: inv.FirstName,
-		LastName:  inv.LastName,
-		Email:     inv.Email,
-		CreatedAt: timestamppb.New(inv.CreatedAt),
+	clientConn, err := server.dialInvoiceMicro()
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+	defer clientConn.Close()
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	inv, err := pb.NewInvoiceServiceClient(clientConn).VoidInvoice(ctx, &pb.VoidInvoiceRequest{
+		Number: chi.URLParam(r, "number"),
+		Reason: payload.Reason,
 	})
-	return err
+	if err != nil {
+		_ = server.badRequest(w, r, errors.New(status.Convert(err).Message()))
+		return
+	}
+
+	server.audit(r, "invoice.void", "invoice", int(inv.GetId()), nil, inv)
+
+	_ = server.writeJSON(w, http.StatusOK, inv)
 }
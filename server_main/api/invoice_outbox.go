@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/outbox"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/pb"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	outboxSendTimeout = 30 * time.Second
+	outboxMaxAttempts = 10
+)
+
+// invoiceOutbox provides the behaviour the dispatcher needs from the outbox.
+// Having this interface allows the use of gomock in tests.
+type invoiceOutbox interface {
+	ClaimInvoiceOutbox(limit int, lease time.Duration) ([]*models.InvoiceOutboxEntry, error)
+	MarkInvoiceOutboxDelivered(id int, invoiceNumber string) error
+	RescheduleInvoiceOutbox(id int, lastError string, nextAttemptAt time.Time) error
+	DeadLetterInvoiceOutbox(id int, lastError string) error
+}
+
+// RunInvoiceDispatcher delivers invoice requests from the outbox to the invoicing microservice
+// until ctx is done. It is safe to run in every replica.
+func (server *Server) RunInvoiceDispatcher(ctx context.Context) error {
+	clientConn, err := server.dialInvoiceMicro()
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+
+	invoiceDispatcher(server.DB, pb.NewInvoiceServiceClient(clientConn)).Run(ctx)
+	return nil
+}
+
+// invoiceDispatcher returns the dispatcher delivering the outbox in db to client
+func invoiceDispatcher(db invoiceOutbox, client pb.InvoiceServiceClient) outbox.Dispatcher[*models.InvoiceOutboxEntry] {
+	return outbox.Dispatcher[*models.InvoiceOutboxEntry]{
+		Name:  "invoice dispatcher",
+		Claim: db.ClaimInvoiceOutbox,
+		Deliver: func(ctx context.Context, e *models.InvoiceOutboxEntry) {
+			deliverInvoice(ctx, db, client, e)
+		},
+		SendTimeout: outboxSendTimeout,
+	}
+}
+
+// deliverInvoice sends one outbox entry, then records the outcome
+func deliverInvoice(ctx context.Context, db invoiceOutbox, client pb.InvoiceServiceClient, e *models.InvoiceOutboxEntry) {
+	number, err := sendInvoice(ctx, client, e.Payload)
+	if err == nil {
+		log.Info().Int("order_id", e.OrderID).Str("invoice_number", number).Msg("invoice sent")
+		if err := db.MarkInvoiceOutboxDelivered(e.ID, number); err != nil {
+			log.Error().Err(err).Int("outbox_id", e.ID).Msg("deliverInvoice")
+		}
+		return
+	}
+
+	// the service rejected the request itself, so retrying it would not help
+	code := status.Code(err)
+	permanent := code == codes.InvalidArgument || code == codes.FailedPrecondition
+
+	if permanent || e.Attempts >= outboxMaxAttempts {
+		log.Error().Err(err).Int("order_id", e.OrderID).Int("attempts", e.Attempts).Msg("invoice delivery failed, giving up")
+		err = db.DeadLetterInvoiceOutbox(e.ID, err.Error())
+	} else {
+		log.Warn().Err(err).Int("order_id", e.OrderID).Int("attempts", e.Attempts).Msg("invoice delivery failed, will retry")
+		err = db.RescheduleInvoiceOutbox(e.ID, err.Error(), time.Now().Add(outbox.Backoff(e.Attempts)))
+	}
+	if err != nil {
+		log.Error().Err(err).Int("outbox_id", e.ID).Msg("deliverInvoice")
+	}
+}
+
+// InvoiceOutbox returns a page of invoice outbox entries, optionally filtered by status
+func (server *Server) InvoiceOutbox(w http.ResponseWriter, r *http.Request) {
+	pageSize := 10   // default
+	currentPage := 1 // default
+
+	// Parse query params
+	query := r.URL.Query()
+	if val := query.Get("page_size"); val != "" {
+		if ps, err := strconv.Atoi(val); err == nil && ps > 0 {
+			pageSize = ps
+		} else {
+			_ = server.badRequest(w, r, errors.New("invalid page_size"))
+			return
+		}
+	}
+	if val := query.Get("page"); val != "" {
+		if cp, err := strconv.Atoi(val); err == nil && cp > 0 {
+			currentPage = cp
+		} else {
+			_ = server.badRequest(w, r, errors.New("invalid page"))
+			return
+		}
+	}
+
+	filter := query.Get("status")
+	switch filter {
+	case "", models.OutboxStatusPending, models.OutboxStatusDelivered, models.OutboxStatusDead:
+	default:
+		_ = server.badRequest(w, r, errors.New("invalid status"))
+		return
+	}
+
+	entries, lastPage, totalRecords, err := server.DB.GetInvoiceOutboxPaginated(filter, pageSize, currentPage)
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	var resp struct {
+		CurrentPage  int                          `json:"current_page"`
+		PageSize     int                          `json:"page_size"`
+		LastPage     int                          `json:"last_page"`
+		TotalRecords int                          `json:"total_records"`
+		Entries      []*models.InvoiceOutboxEntry `json:"entries"`
+	}
+
+	resp.CurrentPage = currentPage
+	resp.PageSize = pageSize
+	resp.LastPage = lastPage
+	resp.TotalRecords = totalRecords
+	resp.Entries = entries
+
+	_ = server.writeJSON(w, http.StatusOK, resp)
+}
+
+// RetryInvoiceOutbox queues a stuck or dead-lettered invoice delivery to be tried again now
+func (server *Server) RetryInvoiceOutbox(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		_ = server.badRequest(w, r, errors.New("invalid id"))
+		return
+	}
+
+	entry, err := server.DB.RetryInvoiceOutbox(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = server.badRequest(w, r, errors.New("no undelivered invoice request with that id"))
+		return
+	} else if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	server.audit(r, "invoice_outbox.retry", "invoice_outbox", id, nil, entry)
+
+	_ = server.writeJSON(w, http.StatusOK, entry)
+}
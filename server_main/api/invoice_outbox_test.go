@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	pb "github.com/LamThanhNguyen/yoyo-store-backend/internal/pb"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDispatchInvoices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockinvoiceOutbox(ctrl)
+	mockClient := pb.NewMockInvoiceServiceClient(ctrl)
+
+	delivered := &models.InvoiceOutboxEntry{ID: 1, OrderID: 10, Attempts: 1, Payload: models.InvoiceRequest{OrderID: 10}}
+	retried := &models.InvoiceOutboxEntry{ID: 2, OrderID: 11, Attempts: 2, Payload: models.InvoiceRequest{OrderID: 11}}
+	exhausted := &models.InvoiceOutboxEntry{ID: 3, OrderID: 12, Attempts: outboxMaxAttempts, Payload: models.InvoiceRequest{OrderID: 12}}
+	rejected := &models.InvoiceOutboxEntry{ID: 4, OrderID: 13, Attempts: 1, Payload: models.InvoiceRequest{OrderID: 13}}
+
+	d := invoiceDispatcher(mockDB, mockClient)
+
+	// entries are claimed one at a time, for longer than they can take to send, so another
+	// replica can't claim one while it is being sent
+	if d.Lease() <= outboxSendTimeout {
+		t.Fatalf("the lease (%v) must outlast a send (%v)", d.Lease(), outboxSendTimeout)
+	}
+	gomock.InOrder(
+		mockDB.EXPECT().ClaimInvoiceOutbox(1, d.Lease()).Return([]*models.InvoiceOutboxEntry{delivered}, nil),
+		mockDB.EXPECT().ClaimInvoiceOutbox(1, d.Lease()).Return([]*models.InvoiceOutboxEntry{retried}, nil),
+		mockDB.EXPECT().ClaimInvoiceOutbox(1, d.Lease()).Return([]*models.InvoiceOutboxEntry{exhausted}, nil),
+		mockDB.EXPECT().ClaimInvoiceOutbox(1, d.Lease()).Return([]*models.InvoiceOutboxEntry{rejected}, nil),
+		mockDB.EXPECT().ClaimInvoiceOutbox(1, d.Lease()).Return(nil, nil),
+	)
+
+	unavailable := status.Error(codes.Unavailable, "smtp timeout")
+	gomock.InOrder(
+		mockClient.EXPECT().CreateAndSendInvoice(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ *pb.CreateInvoiceRequest, _ ...grpc.CallOption) (*pb.CreateInvoiceResponse, error) {
+				if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > outboxSendTimeout {
+					t.Errorf("expected the send to time out within %v", outboxSendTimeout)
+				}
+				return &pb.CreateInvoiceResponse{InvoiceNumber: "INV-2026-000001"}, nil
+			}),
+		mockClient.EXPECT().CreateAndSendInvoice(gomock.Any(), gomock.Any()).Return(nil, unavailable),
+		mockClient.EXPECT().CreateAndSendInvoice(gomock.Any(), gomock.Any()).Return(nil, unavailable),
+		mockClient.EXPECT().CreateAndSendInvoice(gomock.Any(), gomock.Any()).
+			Return(nil, status.Error(codes.InvalidArgument, "quantity must be positive")),
+	)
+
+	mockDB.EXPECT().MarkInvoiceOutboxDelivered(1, "INV-2026-000001").Return(nil)
+	mockDB.EXPECT().RescheduleInvoiceOutbox(2, unavailable.Error(), gomock.Any()).
+		DoAndReturn(func(_ int, _ string, at time.Time) error {
+			if wait := time.Until(at); wait < 50*time.Second || wait > time.Minute {
+				t.Errorf("expected the second attempt to be retried in about a minute, got %v", wait)
+			}
+			return nil
+		})
+	mockDB.EXPECT().DeadLetterInvoiceOutbox(3, unavailable.Error()).Return(nil)
+	mockDB.EXPECT().DeadLetterInvoiceOutbox(4, gomock.Any()).Return(nil)
+
+	n := 0
+	for d.Next(context.Background()) {
+		n++
+	}
+	if n != 4 {
+		t.Fatalf("expected 4 entries to be delivered, got %d", n)
+	}
+}
+
+func TestDispatchInvoicesClaimError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockinvoiceOutbox(ctrl)
+	mockClient := pb.NewMockInvoiceServiceClient(ctrl)
+
+	mockDB.EXPECT().ClaimInvoiceOutbox(gomock.Any(), gomock.Any()).Return(nil, errors.New("db down"))
+
+	if invoiceDispatcher(mockDB, mockClient).Next(context.Background()) {
+		t.Fatal("expected nothing to be delivered")
+	}
+}
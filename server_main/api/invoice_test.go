@@ -1,11 +1,17 @@
 package api
 
 import (
+	"context"
+	"io"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
 	pb "github.com/LamThanhNguyen/yoyo-store-backend/internal/pb"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -14,30 +20,99 @@ func TestSendInvoice(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockClient := pb.NewMockInvoiceServiceClient(ctrl)
-	inv := Invoice{
-		ID:        1,
+	inv := models.InvoiceRequest{
+		OrderID:   1,
 		Quantity:  2,
 		Amount:    100,
 		Product:   "test",
+		Currency:  "usd",
+		CardBrand: "visa",
+		LastFour:  "4242",
 		FirstName: "John",
 		LastName:  "Doe",
 		Email:     "john@example.com",
+		Country:   "DE",
+		Locale:    "de",
 		CreatedAt: time.Now(),
+
+		Discount:      20,
+		DiscountLabel: "WELCOME",
+		TaxRateBps:    1900,
 	}
 
 	mockClient.EXPECT().CreateAndSendInvoice(gomock.Any(), &pb.CreateInvoiceRequest{
-		Id:        int32(inv.ID),
-		Quantity:  int32(inv.Quantity),
-		Amount:    int32(inv.Amount),
-		Product:   inv.Product,
-		FirstName: inv.FirstName,
-		LastName:  inv.LastName,
-		Email:     inv.Email,
-		CreatedAt: timestamppb.New(inv.CreatedAt),
-	}).Return(&pb.CreateInvoiceResponse{}, nil)
+		Id:            int32(inv.OrderID),
+		Quantity:      int32(inv.Quantity),
+		Amount:        int32(inv.Amount),
+		Product:       inv.Product,
+		Currency:      inv.Currency,
+		CardBrand:     inv.CardBrand,
+		LastFour:      inv.LastFour,
+		FirstName:     inv.FirstName,
+		LastName:      inv.LastName,
+		Email:         inv.Email,
+		BuyerCountry:  inv.Country,
+		Locale:        inv.Locale,
+		CreatedAt:     timestamppb.New(inv.CreatedAt),
+		Discount:      int32(inv.Discount),
+		DiscountLabel: inv.DiscountLabel,
+		TaxRateBps:    int32(inv.TaxRateBps),
+	}).Return(&pb.CreateInvoiceResponse{InvoiceNumber: "INV-2026-000001"}, nil)
 
-	server := &Server{}
-	if err := server.sendInvoice(mockClient, inv); err != nil {
+	number, err := sendInvoice(context.Background(), mockClient, inv)
+	if err != nil {
 		t.Fatalf("sendInvoice returned error: %v", err)
 	}
+	if number != "INV-2026-000001" {
+		t.Fatalf("expected invoice number INV-2026-000001, got %q", number)
+	}
+}
+
+func TestWriteInvoicePDF(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := pb.NewMockInvoiceServiceClient(ctrl)
+	mockStream := pb.NewMockInvoiceService_DownloadInvoicePDFClient[pb.InvoicePDFChunk](ctrl)
+
+	mockClient.EXPECT().DownloadInvoicePDF(gomock.Any(), &pb.DownloadInvoicePDFRequest{Number: "INV-2026-000001"}).Return(mockStream, nil)
+	gomock.InOrder(
+		mockStream.EXPECT().Recv().Return(&pb.InvoicePDFChunk{Data: []byte("%PDF-")}, nil),
+		mockStream.EXPECT().Recv().Return(&pb.InvoicePDFChunk{Data: []byte("1.3")}, nil),
+		mockStream.EXPECT().Recv().Return(nil, io.EOF),
+	)
+
+	server := &Server{}
+	rr := httptest.NewRecorder()
+	if err := server.writeInvoicePDF(context.Background(), rr, mockClient, "INV-2026-000001"); err != nil {
+		t.Fatalf("writeInvoicePDF returned error: %v", err)
+	}
+
+	if got := rr.Header().Get("Content-Type"); got != "application/pdf" {
+		t.Errorf("Content-Type = %q, want application/pdf", got)
+	}
+	if got := rr.Body.String(); got != "%PDF-1.3" {
+		t.Errorf("body = %q, want %%PDF-1.3", got)
+	}
+}
+
+func TestWriteInvoicePDFNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := pb.NewMockInvoiceServiceClient(ctrl)
+	mockStream := pb.NewMockInvoiceService_DownloadInvoicePDFClient[pb.InvoicePDFChunk](ctrl)
+
+	mockClient.EXPECT().DownloadInvoicePDF(gomock.Any(), gomock.Any()).Return(mockStream, nil)
+	mockStream.EXPECT().Recv().Return(nil, status.Error(codes.NotFound, "invoice INV-2026-000009 not found"))
+
+	server := &Server{}
+	rr := httptest.NewRecorder()
+	err := server.writeInvoicePDF(context.Background(), rr, mockClient, "INV-2026-000009")
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("writeInvoicePDF returned %v, want NotFound", err)
+	}
+	if rr.Body.Len() != 0 {
+		t.Errorf("expected nothing to be written, got %q", rr.Body.String())
+	}
 }
@@ -1,14 +1,287 @@
 package api
 
 import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/cards"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/storage"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/validator"
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
 )
 
+const (
+	// uploadsPath is where the frontend serves the upload store from
+	uploadsPath = "/static/uploads/"
+	// maxImageSize limits item image uploads
+	maxImageSize = 5 << 20
+	// maxSlugLength limits the slugs items are linked to by
+	maxSlugLength = 100
+	// maxCatalogPageSize limits how many items the public catalog returns at once
+	maxCatalogPageSize = 100
+)
+
+// errSlugTaken is returned when an item is given the slug of another one
+var errSlugTaken = errors.New("is used by another item")
+
+// slugPattern is what slugs look like: lowercase words and numbers, separated by hyphens
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// imageTypes are the image formats items may have, with their file extensions
+var imageTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// planSyncer keeps the Stripe product and price of a recurring item up to date.
+// Having this interface allows the use of gomock in tests.
+type planSyncer interface {
+	SyncPlan(cards.Plan) (cards.Plan, cards.PlanChange, error)
+	CommitPlan(cards.PlanChange) error
+	RollbackPlan(cards.PlanChange) error
+}
+
+// slugChecker tells whether a slug is in use. Having this interface allows the use of gomock
+// in tests.
+type slugChecker interface {
+	ItemSlugTaken(slug string, exceptID int) (bool, error)
+}
+
+// itemPayload is what admins send to create or update an item
+type itemPayload struct {
+	Name           string `json:"name"`
+	Slug           string `json:"slug"`
+	Category       string `json:"category"`
+	Description    string `json:"description"`
+	Price          int    `json:"price"`
+	IsRecurring    bool   `json:"is_recurring"`
+	PlanID         string `json:"plan_id"`
+	InventoryLevel int    `json:"inventory_level"`
+	Archived       bool   `json:"archived"`
+}
+
+// apply copies p onto item. An item keeps its slug unless a new one is given, so links to it
+// don't break when it is renamed.
+func (p itemPayload) apply(item models.Item) models.Item {
+	item.Name = strings.TrimSpace(p.Name)
+	if slug := strings.ToLower(strings.TrimSpace(p.Slug)); slug != "" {
+		item.Slug = slug
+	}
+	item.Category = strings.TrimSpace(p.Category)
+	item.Description = strings.TrimSpace(p.Description)
+	item.Price = p.Price
+	item.IsRecurring = p.IsRecurring
+	item.PlanID = strings.TrimSpace(p.PlanID)
+	item.InventoryLevel = p.InventoryLevel
+	item.Archived = p.Archived
+	return item
+}
+
+// validateItem checks an item before it is saved
+func validateItem(item models.Item) *validator.Validator {
+	v := validator.New()
+	v.Check(item.Name != "", "name", "must be provided")
+	v.Check(len(item.Name) <= 255, "name", "must be at most 255 characters")
+	if item.Slug != "" {
+		v.Check(len(item.Slug) <= maxSlugLength, "slug", fmt.Sprintf("must be at most %d characters", maxSlugLength))
+		v.Check(slugPattern.MatchString(item.Slug), "slug", "must be lowercase letters and numbers, separated by hyphens")
+	}
+	v.Check(len(item.Category) <= 64, "category", "must be at most 64 characters")
+	v.Check(item.Price > 0, "price", "must be more than zero")
+	v.Check(item.InventoryLevel >= 0, "inventory_level", "must not be negative")
+	if item.IsRecurring {
+		v.Check(item.PlanID == "" || strings.HasPrefix(item.PlanID, "price_") || strings.HasPrefix(item.PlanID, "plan_"),
+			"plan_id", "must be a Stripe price id, or empty to create one")
+	} else {
+		v.Check(item.PlanID == "", "plan_id", "only recurring items have a plan")
+	}
+	return v
+}
+
+// slugify makes a slug from s, keeping its letters and numbers; other characters become hyphens
+func slugify(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, c := range strings.ToLower(s) {
+		if ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(c)
+			hyphen = false
+		} else {
+			hyphen = true
+		}
+	}
+
+	slug := b.String()
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	if slug == "" {
+		return "item"
+	}
+	return slug
+}
+
+// itemSlug returns the slug of item, through the provided interface: its own, which must not be
+// used by another item, or else one made from its name, numbered when needed to be unique.
+func itemSlug(db slugChecker, item models.Item) (string, error) {
+	if item.Slug != "" {
+		taken, err := db.ItemSlugTaken(item.Slug, item.ID)
+		if err != nil {
+			return "", err
+		}
+		if taken {
+			return "", errSlugTaken
+		}
+		return item.Slug, nil
+	}
+
+	base := slugify(item.Name)
+	for n := 1; n <= 100; n++ {
+		slug := base
+		if n > 1 {
+			suffix := fmt.Sprintf("-%d", n)
+			slug = strings.TrimRight(base[:min(len(base), maxSlugLength-len(suffix))], "-") + suffix
+		}
+
+		taken, err := db.ItemSlugTaken(slug, item.ID)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return slug, nil
+		}
+	}
+
+	return "", errors.New("no free slug for the item, please give one")
+}
+
+// syncItemPlan creates or updates the Stripe product and price of a recurring item, through
+// the provided interface, and returns the item with their ids, and what was created in Stripe.
+// Other items are left alone.
+func syncItemPlan(stripe planSyncer, item models.Item) (models.Item, cards.PlanChange, error) {
+	if !item.IsRecurring {
+		return item, cards.PlanChange{}, nil
+	}
+
+	plan, change, err := stripe.SyncPlan(cards.Plan{
+		ProductID:   item.StripeProductID,
+		PriceID:     item.PlanID,
+		Name:        item.Name,
+		Description: item.Description,
+		Amount:      item.Price,
+		Active:      !item.Archived,
+	})
+	if err != nil {
+		return item, change, fmt.Errorf("stripe: %w", err)
+	}
+
+	item.StripeProductID = plan.ProductID
+	item.PlanID = plan.PriceID
+	return item, change, nil
+}
+
+// saveItem syncs the Stripe plan of item, which was before (zero for a new item), through the
+// provided interface, then saves item with save. Stripe comes first, so an item is never sold
+// without its plan, but the price a new one replaces is only deactivated once item is saved.
+// When item can't be saved, what was created in Stripe is deactivated, and the product put back
+// as it was. An item that stops being recurring keeps its Stripe ids, but its product is
+// deactivated once it is saved, so no one can subscribe to it any more.
+func saveItem(stripe planSyncer, before, item models.Item, save func(models.Item) (models.Item, error)) (models.Item, error) {
+	item, change, err := syncItemPlan(stripe, item)
+	if before.IsRecurring && !item.IsRecurring {
+		change.OldProduct = before.StripeProductID
+	}
+	if err == nil {
+		item, err = save(item)
+		if err == nil {
+			if err := stripe.CommitPlan(change); err != nil {
+				// subscribers can still be signed up to the old price or product until it is deactivated
+				log.Error().Err(err).Interface("change", change).Msg("saveItem: deactivating the replaced plan")
+			}
+			return item, nil
+		}
+	}
+
+	if err := stripe.RollbackPlan(change); err != nil {
+		log.Error().Err(err).Interface("change", change).Msg("saveItem: rolling back the plan")
+	}
+	if before.IsRecurring && item.IsRecurring && change.NewProduct == "" {
+		if _, _, err := syncItemPlan(stripe, before); err != nil {
+			log.Error().Err(err).Str("product", before.StripeProductID).Msg("saveItem: restoring the product")
+		}
+	}
+
+	return item, err
+}
+
+// saveItemImage stores an uploaded image of item id, and returns the path the frontend serves
+// it under. Every upload gets a new key, so images can be cached for good.
+func saveItemImage(ctx context.Context, store storage.Store, id int, r io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxImageSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxImageSize {
+		return "", fmt.Errorf("the image must be at most %d MB", maxImageSize>>20)
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := imageTypes[contentType]
+	if !ok {
+		return "", errors.New("the image must be a PNG, JPEG, GIF or WebP file")
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	key := fmt.Sprintf("items/%d-%s%s", id, hex.EncodeToString(suffix), ext)
+
+	if err := store.Put(ctx, key, bytes.NewReader(data), contentType); err != nil {
+		return "", err
+	}
+
+	return uploadsPath + key, nil
+}
+
+// deleteUpload removes an uploaded file by the path it is served under. Images that weren't
+// uploaded, such as those shipped in ./static, are left alone.
+func deleteUpload(ctx context.Context, store storage.Store, path string) error {
+	key, ok := strings.CutPrefix(path, uploadsPath)
+	if !ok || key == "" {
+		return nil
+	}
+	return store.Delete(ctx, key)
+}
+
+// stripePlans returns what syncs the plans of recurring items with Stripe
+func (server *Server) stripePlans() planSyncer {
+	return &cards.Card{
+		Secret:   server.config.StripeSecret,
+		Key:      server.config.StripeKey,
+		Currency: "usd",
+	}
+}
+
 // GetItemByID gets one item by id and returns as JSON
 func (server *Server) GetItemByID(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -31,3 +304,277 @@ func (server *Server) GetItemByID(w http.ResponseWriter, r *http.Request) {
 		log.Error().Err(err).Msg("GetItemByID write")
 	}
 }
+
+// Catalog returns a paginated listing of the items on sale, filtered by category and searched
+// by text
+func (server *Server) Catalog(w http.ResponseWriter, r *http.Request) {
+	pageSize := 12   // default
+	currentPage := 1 // default
+
+	// Parse query params
+	query := r.URL.Query()
+	if val := query.Get("page_size"); val != "" {
+		if ps, err := strconv.Atoi(val); err == nil && ps > 0 && ps <= maxCatalogPageSize {
+			pageSize = ps
+		} else {
+			_ = server.badRequest(w, r, fmt.Errorf("invalid page_size, expected 1 to %d", maxCatalogPageSize))
+			return
+		}
+	}
+	if val := query.Get("page"); val != "" {
+		if cp, err := strconv.Atoi(val); err == nil && cp > 0 {
+			currentPage = cp
+		} else {
+			_ = server.badRequest(w, r, errors.New("invalid page"))
+			return
+		}
+	}
+
+	filter := models.CatalogFilter{
+		Category: strings.TrimSpace(query.Get("category")),
+		Query:    strings.TrimSpace(query.Get("q")),
+	}
+
+	items, lastPage, totalRecords, err := server.DB.GetCatalogPaginated(filter, pageSize, currentPage)
+	if err != nil {
+		_ = server.serverError(w, r, err)
+		return
+	}
+
+	var resp struct {
+		CurrentPage  int           `json:"current_page"`
+		PageSize     int           `json:"page_size"`
+		LastPage     int           `json:"last_page"`
+		TotalRecords int           `json:"total_records"`
+		Items        []models.Item `json:"items"`
+	}
+
+	resp.CurrentPage = currentPage
+	resp.PageSize = pageSize
+	resp.LastPage = lastPage
+	resp.TotalRecords = totalRecords
+	resp.Items = items
+
+	_ = server.writeJSON(w, http.StatusOK, resp)
+}
+
+// ItemCategories returns the categories of the items on sale
+func (server *Server) ItemCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := server.DB.GetItemCategories()
+	if err != nil {
+		_ = server.serverError(w, r, err)
+		return
+	}
+
+	var resp struct {
+		Categories []string `json:"categories"`
+	}
+	resp.Categories = categories
+
+	_ = server.writeJSON(w, http.StatusOK, resp)
+}
+
+// AllItems returns every item, archived ones included
+func (server *Server) AllItems(w http.ResponseWriter, r *http.Request) {
+	items, err := server.DB.GetAllItems()
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	var resp struct {
+		Items []models.Item `json:"items"`
+	}
+	resp.Items = items
+
+	_ = server.writeJSON(w, http.StatusOK, resp)
+}
+
+// OneItem returns one item by id (from the url)
+func (server *Server) OneItem(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	item, err := server.DB.GetItem(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = server.badRequest(w, r, errors.New("no item with that id"))
+		return
+	} else if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	_ = server.writeJSON(w, http.StatusOK, item)
+}
+
+// CreateItem creates an item, with its Stripe product and price if it is recurring
+func (server *Server) CreateItem(w http.ResponseWriter, r *http.Request) {
+	var payload itemPayload
+	err := server.readJSON(w, r, &payload)
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	item := payload.apply(models.Item{})
+	if v := validateItem(item); !v.Valid() {
+		server.failedValidation(w, r, v.Errors)
+		return
+	}
+
+	item.Slug, err = itemSlug(server.DB, item)
+	if errors.Is(err, errSlugTaken) {
+		server.failedValidation(w, r, map[string]string{"slug": err.Error()})
+		return
+	} else if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	item, err = saveItem(server.stripePlans(), models.Item{}, item, func(item models.Item) (models.Item, error) {
+		var err error
+		item.ID, err = server.DB.InsertItem(item)
+		return item, err
+	})
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	server.audit(r, "item.create", "item", item.ID, nil, item)
+
+	_ = server.writeJSON(w, http.StatusCreated, item)
+}
+
+// UpdateItem saves the changes to an item (by id, from the url), and to its Stripe product and
+// price if it is recurring
+func (server *Server) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	var payload itemPayload
+	err := server.readJSON(w, r, &payload)
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	before, err := server.DB.GetItem(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = server.badRequest(w, r, errors.New("no item with that id"))
+		return
+	} else if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	item := payload.apply(before)
+	if v := validateItem(item); !v.Valid() {
+		server.failedValidation(w, r, v.Errors)
+		return
+	}
+
+	item.Slug, err = itemSlug(server.DB, item)
+	if errors.Is(err, errSlugTaken) {
+		server.failedValidation(w, r, map[string]string{"slug": err.Error()})
+		return
+	} else if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	item, err = saveItem(server.stripePlans(), before, item, func(item models.Item) (models.Item, error) {
+		return item, server.DB.UpdateItem(item)
+	})
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	server.audit(r, "item.update", "item", id, before, item)
+
+	_ = server.writeJSON(w, http.StatusOK, item)
+}
+
+// DeleteItem deletes an item that was never ordered (by id, from the url)
+func (server *Server) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	before, err := server.DB.GetItem(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = server.badRequest(w, r, errors.New("no item with that id"))
+		return
+	} else if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	err = server.DB.DeleteItem(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = server.badRequest(w, r, errors.New("the item has been ordered, so it can only be archived"))
+		return
+	} else if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	server.audit(r, "item.delete", "item", id, before, nil)
+
+	if err := deleteUpload(r.Context(), server.uploads, before.Image); err != nil {
+		log.Error().Err(err).Str("image", before.Image).Msg("DeleteItem")
+	}
+
+	var resp struct {
+		Error   bool   `json:"error"`
+		Message string `json:"message"`
+	}
+
+	resp.Error = false
+	resp.Message = "item deleted"
+	_ = server.writeJSON(w, http.StatusOK, resp)
+}
+
+// UploadItemImage replaces the image of an item (by id, from the url) with the "image" file of
+// a multipart form
+func (server *Server) UploadItemImage(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	before, err := server.DB.GetItem(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = server.badRequest(w, r, errors.New("no item with that id"))
+		return
+	} else if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImageSize+1<<20)
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		_ = server.badRequest(w, r, fmt.Errorf("an image file is required: %w", err))
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	item := before
+	item.Image, err = saveItemImage(ctx, server.uploads, id, file)
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	err = server.DB.UpdateItem(item)
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	server.audit(r, "item.image", "item", id, before, item)
+
+	if err := deleteUpload(ctx, server.uploads, before.Image); err != nil {
+		log.Error().Err(err).Str("image", before.Image).Msg("UploadItemImage")
+	}
+
+	_ = server.writeJSON(w, http.StatusOK, item)
+}
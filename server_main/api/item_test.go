@@ -0,0 +1,355 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/cards"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/storage"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"go.uber.org/mock/gomock"
+)
+
+func TestValidateItem(t *testing.T) {
+	tests := []struct {
+		name  string
+		item  models.Item
+		field string
+	}{
+		{"valid", models.Item{Name: "Yoyo", Price: 1000, InventoryLevel: 10}, ""},
+		{"valid plan", models.Item{Name: "Bronze Plan", Price: 2000, IsRecurring: true, PlanID: "price_123"}, ""},
+		{"new plan", models.Item{Name: "Silver Plan", Price: 3000, IsRecurring: true}, ""},
+		{"no name", models.Item{Price: 1000}, "name"},
+		{"free", models.Item{Name: "Yoyo"}, "price"},
+		{"negative inventory", models.Item{Name: "Yoyo", Price: 1000, InventoryLevel: -1}, "inventory_level"},
+		{"plan on a one-time item", models.Item{Name: "Yoyo", Price: 1000, PlanID: "price_123"}, "plan_id"},
+		{"not a price", models.Item{Name: "Bronze Plan", Price: 2000, IsRecurring: true, PlanID: "prod_123"}, "plan_id"},
+		{"slug", models.Item{Name: "Yoyo", Slug: "red-yoyo-2", Price: 1000}, ""},
+		{"bad slug", models.Item{Name: "Yoyo", Slug: "red yoyo", Price: 1000}, "slug"},
+		{"trailing hyphen", models.Item{Name: "Yoyo", Slug: "yoyo-", Price: 1000}, "slug"},
+		{"long category", models.Item{Name: "Yoyo", Category: strings.Repeat("c", 65), Price: 1000}, "category"},
+	}
+
+	for _, tt := range tests {
+		v := validateItem(tt.item)
+		if tt.field == "" && !v.Valid() {
+			t.Errorf("%s: expected no errors, got %v", tt.name, v.Errors)
+		}
+		if _, ok := v.Errors[tt.field]; tt.field != "" && !ok {
+			t.Errorf("%s: expected an error on %s, got %v", tt.name, tt.field, v.Errors)
+		}
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Yoyo":                    "yoyo",
+		"Bronze Plan":             "bronze-plan",
+		"  The *Best* Yoyo, 2!  ": "the-best-yoyo-2",
+		"Café Yoyo":               "caf-yoyo",
+		"!!!":                     "item",
+		strings.Repeat("ab ", 60): strings.Repeat("ab-", 33) + "a",
+	}
+
+	for in, want := range tests {
+		if got := slugify(in); got != want {
+			t.Errorf("slugify(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestItemSlug(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockslugChecker(ctrl)
+
+	// an item keeps its own slug
+	mockDB.EXPECT().ItemSlugTaken("yoyo", 1).Return(false, nil)
+	slug, err := itemSlug(mockDB, models.Item{ID: 1, Name: "Red Yoyo", Slug: "yoyo"})
+	if err != nil || slug != "yoyo" {
+		t.Errorf("got %q, %v", slug, err)
+	}
+
+	// but can't take the slug of another one
+	mockDB.EXPECT().ItemSlugTaken("yoyo", 3).Return(true, nil)
+	if _, err := itemSlug(mockDB, models.Item{ID: 3, Name: "Yoyo", Slug: "yoyo"}); !errors.Is(err, errSlugTaken) {
+		t.Errorf("expected errSlugTaken, got %v", err)
+	}
+
+	// new slugs are numbered until they are free
+	gomock.InOrder(
+		mockDB.EXPECT().ItemSlugTaken("red-yoyo", 0).Return(true, nil),
+		mockDB.EXPECT().ItemSlugTaken("red-yoyo-2", 0).Return(true, nil),
+		mockDB.EXPECT().ItemSlugTaken("red-yoyo-3", 0).Return(false, nil),
+	)
+	slug, err = itemSlug(mockDB, models.Item{Name: "Red Yoyo"})
+	if err != nil || slug != "red-yoyo-3" {
+		t.Errorf("got %q, %v", slug, err)
+	}
+
+	mockDB.EXPECT().ItemSlugTaken("red-yoyo", 0).Return(false, errors.New("connection refused"))
+	if _, err := itemSlug(mockDB, models.Item{Name: "Red Yoyo"}); err == nil {
+		t.Error("expected the database error")
+	}
+}
+
+func TestSyncItemPlan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStripe := NewMockplanSyncer(ctrl)
+
+	// one-time items have nothing in Stripe
+	item, _, err := syncItemPlan(mockStripe, models.Item{Name: "Yoyo", Price: 1000})
+	if err != nil || item.PlanID != "" {
+		t.Fatalf("got %+v, %v", item, err)
+	}
+
+	mockStripe.EXPECT().SyncPlan(cards.Plan{
+		PriceID:     "price_old",
+		Name:        "Bronze Plan",
+		Description: "Three yoyos a month",
+		Amount:      2500,
+		Active:      false,
+	}).Return(cards.Plan{ProductID: "prod_1", PriceID: "price_new"}, cards.PlanChange{NewPrice: "price_new", OldPrice: "price_old"}, nil)
+
+	item, change, err := syncItemPlan(mockStripe, models.Item{
+		Name:        "Bronze Plan",
+		Description: "Three yoyos a month",
+		Price:       2500,
+		IsRecurring: true,
+		PlanID:      "price_old",
+		Archived:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.StripeProductID != "prod_1" || item.PlanID != "price_new" {
+		t.Errorf("expected the Stripe ids to be kept on the item, got %+v", item)
+	}
+	if change.OldPrice != "price_old" {
+		t.Errorf("expected the change to be returned, got %+v", change)
+	}
+
+	mockErr := errors.New("invalid api key")
+	mockStripe.EXPECT().SyncPlan(gomock.Any()).Return(cards.Plan{}, cards.PlanChange{}, mockErr)
+
+	if _, _, err := syncItemPlan(mockStripe, models.Item{Name: "Bronze Plan", Price: 2000, IsRecurring: true}); !errors.Is(err, mockErr) {
+		t.Errorf("expected %v, got %v", mockErr, err)
+	}
+}
+
+func TestSaveItem(t *testing.T) {
+	before := models.Item{
+		ID:              7,
+		Name:            "Bronze Plan",
+		Price:           2000,
+		IsRecurring:     true,
+		StripeProductID: "prod_1",
+		PlanID:          "price_old",
+	}
+	item := before
+	item.Name = "Bronze Plan Plus"
+	item.Price = 2500
+
+	change := cards.PlanChange{NewPrice: "price_new", OldPrice: "price_old"}
+	synced := cards.Plan{ProductID: "prod_1", PriceID: "price_new"}
+
+	t.Run("saved", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockStripe := NewMockplanSyncer(ctrl)
+
+		// the old price is only deactivated once the item is saved with the new one
+		var saved models.Item
+		gomock.InOrder(
+			mockStripe.EXPECT().SyncPlan(gomock.Any()).Return(synced, change, nil),
+			mockStripe.EXPECT().CommitPlan(change).Do(func(cards.PlanChange) {
+				if saved.PlanID != "price_new" {
+					t.Error("expected the item to be saved before the old price is deactivated")
+				}
+			}).Return(nil),
+		)
+
+		got, err := saveItem(mockStripe, before, item, func(item models.Item) (models.Item, error) {
+			saved = item
+			return item, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.PlanID != "price_new" {
+			t.Errorf("expected the new price, got %q", got.PlanID)
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockStripe := NewMockplanSyncer(ctrl)
+
+		// the new price is deactivated, and the product put back as it was, with its old price
+		mockErr := errors.New("connection refused")
+		gomock.InOrder(
+			mockStripe.EXPECT().SyncPlan(cards.Plan{ProductID: "prod_1", PriceID: "price_old", Name: "Bronze Plan Plus", Amount: 2500, Active: true}).
+				Return(synced, change, nil),
+			mockStripe.EXPECT().RollbackPlan(change).Return(nil),
+			mockStripe.EXPECT().SyncPlan(cards.Plan{ProductID: "prod_1", PriceID: "price_old", Name: "Bronze Plan", Amount: 2000, Active: true}).
+				Return(cards.Plan{ProductID: "prod_1", PriceID: "price_old"}, cards.PlanChange{}, nil),
+		)
+
+		_, err := saveItem(mockStripe, before, item, func(item models.Item) (models.Item, error) {
+			return item, mockErr
+		})
+		if !errors.Is(err, mockErr) {
+			t.Fatalf("expected %v, got %v", mockErr, err)
+		}
+	})
+
+	t.Run("no longer recurring", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockStripe := NewMockplanSyncer(ctrl)
+
+		// the product is deactivated, but the item keeps it, in case it becomes a plan again
+		mockStripe.EXPECT().CommitPlan(cards.PlanChange{OldProduct: "prod_1"}).Return(nil)
+
+		oneOff := before
+		oneOff.IsRecurring = false
+
+		got, err := saveItem(mockStripe, before, oneOff, func(item models.Item) (models.Item, error) {
+			return item, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.StripeProductID != "prod_1" || got.PlanID != "price_old" {
+			t.Errorf("expected the item to keep its Stripe ids, got %q and %q", got.StripeProductID, got.PlanID)
+		}
+	})
+
+	t.Run("no longer recurring, not saved", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockStripe := NewMockplanSyncer(ctrl)
+
+		// the product stays active, as the item is still a plan
+		mockStripe.EXPECT().RollbackPlan(cards.PlanChange{OldProduct: "prod_1"}).Return(nil)
+
+		oneOff := before
+		oneOff.IsRecurring = false
+
+		_, err := saveItem(mockStripe, before, oneOff, func(item models.Item) (models.Item, error) {
+			return item, errSlugTaken
+		})
+		if !errors.Is(err, errSlugTaken) {
+			t.Fatalf("expected %v, got %v", errSlugTaken, err)
+		}
+	})
+
+	t.Run("new item not saved", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockStripe := NewMockplanSyncer(ctrl)
+
+		created := cards.PlanChange{NewProduct: "prod_2", NewPrice: "price_2"}
+		mockStripe.EXPECT().SyncPlan(gomock.Any()).Return(cards.Plan{ProductID: "prod_2", PriceID: "price_2"}, created, nil)
+		mockStripe.EXPECT().RollbackPlan(created).Return(nil)
+
+		_, err := saveItem(mockStripe, models.Item{}, models.Item{Name: "Gold Plan", Price: 3000, IsRecurring: true},
+			func(item models.Item) (models.Item, error) {
+				return item, errSlugTaken
+			})
+		if !errors.Is(err, errSlugTaken) {
+			t.Fatalf("expected %v, got %v", errSlugTaken, err)
+		}
+	})
+}
+
+func TestSaveItemImage(t *testing.T) {
+	store, err := storage.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := saveItemImage(ctx, store, 3, bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(path, "/static/uploads/items/3-") || !strings.HasSuffix(path, ".png") {
+		t.Errorf("unexpected path %q", path)
+	}
+
+	key := strings.TrimPrefix(path, uploadsPath)
+	rc, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if !bytes.Equal(stored, buf.Bytes()) {
+		t.Error("expected the image to be stored as uploaded")
+	}
+
+	if _, err := saveItemImage(ctx, store, 3, strings.NewReader("<script>alert(1)</script>")); err == nil {
+		t.Error("expected a file that isn't an image to be refused")
+	}
+	if _, err := saveItemImage(ctx, store, 3, io.MultiReader(bytes.NewReader(buf.Bytes()), bytes.NewReader(make([]byte, maxImageSize)))); err == nil {
+		t.Error("expected an image that is too large to be refused")
+	}
+
+	// shipped images aren't in the store
+	if err := deleteUpload(ctx, store, "/static/yoyo4.png"); err != nil {
+		t.Errorf("expected a shipped image to be left alone, got %v", err)
+	}
+	if err := deleteUpload(ctx, store, path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("expected the upload to be deleted, got %v", err)
+	}
+}
+
+func TestCatalogDatabaseError(t *testing.T) {
+	db, err := sql.Open("pgx", "postgres://localhost/yoyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = db.Close()
+
+	server := &Server{DB: &models.DBModel{DB: db}}
+
+	for _, h := range []http.HandlerFunc{server.Catalog, server.ItemCategories} {
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest("GET", "/api/v1/items", nil))
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+		}
+		if strings.Contains(w.Body.String(), "closed") {
+			t.Errorf("expected the database error not to be shown, got %s", w.Body.String())
+		}
+	}
+}
@@ -1,16 +1,12 @@
 package api
 
 import (
-	"bytes"
+	"context"
 	"embed"
-	"fmt"
-	"html/template"
-	"strconv"
-	"time"
+	"encoding/json"
 
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/mailer"
 	"github.com/rs/zerolog/log"
-
-	mail "github.com/xhit/go-simple-mail/v2"
 )
 
 //go:embed templates
@@ -20,73 +16,73 @@ func (server *Server) SendMail(
 	from,
 	to,
 	subject,
-	tmpl string,
+	tmpl,
+	locale string,
 	data interface{},
 ) error {
-	templateToRender := fmt.Sprintf("templates/%s.html.tmpl", tmpl)
-
-	t, err := template.New("email-html").ParseFS(emailTemplateFS, templateToRender)
+	formattedMessage, plainMessage, err := server.templates.Render(tmpl, locale, data)
 	if err != nil {
 		log.Error().Err(err).Msg("SendMail")
 		return err
 	}
 
-	var tpl bytes.Buffer
-	if err = t.ExecuteTemplate(&tpl, "body", data); err != nil {
-		log.Error().Err(err).Msg("SendMail")
-		return err
-	}
-
-	formattedMessage := tpl.String()
-
-	templateToRender = fmt.Sprintf("templates/%s.plain.tmpl", tmpl)
-	t, err = template.New("email-plain").ParseFS(emailTemplateFS, templateToRender)
-	if err != nil {
-		log.Error().Err(err).Msg("SendMail")
-		return err
+	// queue the mail; the dispatcher sends it, retrying while the transport is down
+	msg := mailer.Message{
+		From:    from,
+		To:      to,
+		Subject: subject,
+		HTML:    formattedMessage,
+		Plain:   plainMessage,
 	}
 
-	if err = t.ExecuteTemplate(&tpl, "body", data); err != nil {
+	if err := server.mail.Send(context.Background(), msg); err != nil {
 		log.Error().Err(err).Msg("SendMail")
 		return err
 	}
 
-	plainMessage := tpl.String()
+	return nil
+}
 
-	// send the mail
-	mail_server := mail.NewSMTPClient()
-	mail_server.Host = server.config.SmtpHost
-	mail_server.Port, err = strconv.Atoi(server.config.SmtpPort)
+// queueMail queues an email by template name, to be rendered with data when the mail dispatcher
+// sends it, rather than when it is queued
+func (server *Server) queueMail(from, to, subject, tmpl, locale string, data interface{}) error {
+	body, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
-	mail_server.Username = server.config.SmtpUsername
-	mail_server.Password = server.config.SmtpPassword
-	mail_server.Encryption = mail.EncryptionTLS
-	mail_server.KeepAlive = false
-	mail_server.ConnectTimeout = 30 * time.Second
-	mail_server.SendTimeout = 30 * time.Second
 
-	smtpClient, err := mail_server.Connect()
-	if err != nil {
-		return err
+	msg := mailer.Message{
+		From:     from,
+		To:       to,
+		Subject:  subject,
+		Template: tmpl,
+		Locale:   locale,
+		Data:     body,
 	}
 
-	email := mail.NewMSG()
-	email.SetFrom(from).
-		AddTo(to).
-		SetSubject(subject)
+	if err := server.mail.Send(context.Background(), msg); err != nil {
+		log.Error().Err(err).Msg("queueMail")
+		return err
+	}
 
-	email.SetBody(mail.TextHTML, formattedMessage)
-	email.AddAlternative(mail.TextPlain, plainMessage)
+	return nil
+}
 
-	err = email.Send(smtpClient)
-	if err != nil {
-		log.Error().Err(err).Msg("SendMail")
-		return err
+// renderQueued renders an email queued by template name when the mail dispatcher sends it. The
+// password reset and verification emails are queued without their links, which grant access to
+// the account, so the links are issued here and never stored in the outbox.
+func (server *Server) renderQueued(tmpl, locale string, data json.RawMessage) (string, string, error) {
+	switch tmpl {
+	case "password-reset":
+		return server.renderPasswordReset(locale, data)
+	case "email-verification":
+		return server.renderVerificationEmail(locale, data)
 	}
 
-	log.Info().Msg("send email")
+	return server.renderNotification(tmpl, locale, data)
+}
 
-	return nil
+// RunMailDispatcher sends queued mail until ctx is done
+func (server *Server) RunMailDispatcher(ctx context.Context) error {
+	return server.mail.Run(ctx)
 }
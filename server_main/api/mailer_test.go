@@ -0,0 +1,189 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/i18n"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/mailer"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/server_main/util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestEmailTemplatesAreLocalized(t *testing.T) {
+	data := struct {
+		FirstName string
+		Link      string
+	}{"Jane", "https://example.com/link"}
+
+	for _, name := range []string{"email-verification", "password-changed", "password-reset"} {
+		for _, kind := range []string{"html", "plain"} {
+			for _, locale := range i18n.Supported() {
+				c := i18n.For(locale)
+				file := i18n.TemplateFile(emailTemplateFS, "templates", name, kind, locale)
+
+				tmpl, err := template.New(name).Funcs(c.Funcs()).ParseFS(emailTemplateFS, file)
+				if err != nil {
+					t.Fatalf("%s: %v", file, err)
+				}
+
+				var buf bytes.Buffer
+				if err := tmpl.ExecuteTemplate(&buf, "body", data); err != nil {
+					t.Fatalf("%s in %s: %v", file, locale, err)
+				}
+				if !strings.Contains(buf.String(), data.Link) || strings.Contains(buf.String(), "email.") {
+					t.Errorf("%s in %s is missing its link or a translation:\n%s", file, locale, buf.String())
+				}
+			}
+		}
+	}
+}
+
+func TestNotificationTemplates(t *testing.T) {
+	data := notification{
+		FirstName: "Jane",
+		OrderID:   42,
+		Product:   "Bronze Plan",
+		Amount:    2000,
+		Currency:  "eur",
+		LastFour:  "4242",
+		EndsAt:    time.Date(2026, 11, 19, 0, 0, 0, 0, time.UTC),
+		Reason:    "Your card was declined",
+		Link:      "https://example.com/plans/bronze",
+	}
+
+	tests := map[string]string{
+		"order-confirmation":     "Thank you for your order. We have received your payment of €20.00 for Bronze Plan.",
+		"refund-issued":          "We have refunded €20.00 for order 42.",
+		"subscription-cancelled": "until the end of the current billing period, on November 19, 2026.",
+		"payment-failed":         "Reason: Your card was declined",
+	}
+
+	for name, want := range tests {
+		prefix := "email." + strings.ReplaceAll(name, "-", "_") + "."
+
+		for _, kind := range []string{"html", "plain"} {
+			for _, locale := range i18n.Supported() {
+				c := i18n.For(locale)
+				file := i18n.TemplateFile(emailTemplateFS, "templates", name, kind, locale)
+
+				tmpl, err := template.New(name).Funcs(c.Funcs()).ParseFS(emailTemplateFS, file)
+				if err != nil {
+					t.Fatalf("%s: %v", file, err)
+				}
+
+				var buf bytes.Buffer
+				if err := tmpl.ExecuteTemplate(&buf, "body", data); err != nil {
+					t.Fatalf("%s in %s: %v", file, locale, err)
+				}
+				if strings.Contains(buf.String(), prefix) || strings.Contains(buf.String(), "%!") {
+					t.Errorf("%s in %s is missing a translation:\n%s", file, locale, buf.String())
+				}
+				if locale == "en" && !strings.Contains(buf.String(), want) {
+					t.Errorf("expected %s to contain %q:\n%s", file, want, buf.String())
+				}
+			}
+		}
+
+		if subject := prefix + "subject"; i18n.For("de").T(subject) == subject {
+			t.Errorf("%s has no subject", name)
+		}
+	}
+}
+
+func TestRenderNotification(t *testing.T) {
+	server := &Server{templates: mailer.NewTemplates(emailTemplateFS, "templates", nil)}
+
+	// as the frontend queues its order confirmations
+	data, err := json.Marshal(struct {
+		FirstName string
+		OrderID   int
+		Product   string
+		Amount    int
+		Currency  string
+	}{"Jane", 42, "Yoyo", 1000, "usd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	html, plain, err := server.renderNotification("order-confirmation", "en", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "We have received your payment of $10.00 for Yoyo."
+	if !strings.Contains(html, want) || !strings.Contains(plain, want) {
+		t.Errorf("expected the email to contain %q:\n%s\n%s", want, html, plain)
+	}
+}
+
+func TestRenderQueuedVerificationEmail(t *testing.T) {
+	server := &Server{
+		config:    util.Config{FrontendAddr: "https://shop.example.com", TokenSymmetricKey: "secret"},
+		templates: mailer.NewTemplates(emailTemplateFS, "templates", nil),
+	}
+
+	// the queued email holds only who it is for
+	data, err := json.Marshal(verificationEmail{UserID: 7, FirstName: "Jane", Email: "jane@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "verify-email") {
+		t.Fatalf("expected no link in the queued data, got %s", data)
+	}
+
+	html, _, err := server.renderQueued("email-verification", "en", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "https://shop.example.com/verify-email?id=7&amp;email=jane%40example.com&amp;hash="
+	if !strings.Contains(html, want) {
+		t.Errorf("expected the email to contain %q:\n%s", want, html)
+	}
+}
+
+func TestPasswordResetLink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockpasswordResetInserter(ctrl)
+
+	var stored *models.Token
+	mockDB.EXPECT().InsertPasswordReset(gomock.Any()).DoAndReturn(func(token *models.Token) error {
+		stored = token
+		return nil
+	})
+
+	link, err := passwordResetLink(mockDB, "https://shop.example.com", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stored.UserID != 7 || stored.Scope != models.ScopePasswordReset {
+		t.Errorf("expected a password reset token for user 7, got %+v", stored)
+	}
+	if want := "https://shop.example.com/reset-password?token=" + stored.PlainText; link != want {
+		t.Errorf("expected %q, got %q", want, link)
+	}
+}
+
+func TestPasswordResetLinkError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockpasswordResetInserter(ctrl)
+	mockErr := errors.New("insert failed")
+	mockDB.EXPECT().InsertPasswordReset(gomock.Any()).Return(mockErr)
+
+	// the email is retried rather than sent with a link that doesn't work
+	if _, err := passwordResetLink(mockDB, "https://shop.example.com", 7); !errors.Is(err, mockErr) {
+		t.Fatalf("expected insert error, got %v", err)
+	}
+}
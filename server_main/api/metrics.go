@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+)
+
+// metricsReader provides the aggregates the sales dashboard is built from.
+// Having this interface allows the use of gomock in tests.
+type metricsReader interface {
+	GetSalesTotals(since time.Time) ([]models.SalesTotal, error)
+	GetSubscriptionTotals() ([]models.SubscriptionTotal, error)
+}
+
+// salesPeriod sums up the orders placed in a period. Amounts are in cents, per currency.
+type salesPeriod struct {
+	Since      time.Time      `json:"since"`
+	Orders     int            `json:"orders"`
+	Refunds    int            `json:"refunds"`
+	RefundRate float64        `json:"refund_rate"`
+	Revenue    map[string]int `json:"revenue"`
+}
+
+type salesMetrics struct {
+	Today               salesPeriod    `json:"today"`
+	Last7Days           salesPeriod    `json:"last_7_days"`
+	Last30Days          salesPeriod    `json:"last_30_days"`
+	ActiveSubscriptions int            `json:"active_subscriptions"`
+	MRR                 map[string]int `json:"mrr"`
+	GeneratedAt         time.Time      `json:"generated_at"`
+}
+
+// getSalesMetrics builds the sales dashboard as of now, through the provided interface. Today
+// starts at midnight in now's location.
+func getSalesMetrics(db metricsReader, now time.Time) (salesMetrics, error) {
+	m := salesMetrics{MRR: map[string]int{}, GeneratedAt: now}
+
+	periods := []struct {
+		period *salesPeriod
+		since  time.Time
+	}{
+		{&m.Today, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())},
+		{&m.Last7Days, now.AddDate(0, 0, -7)},
+		{&m.Last30Days, now.AddDate(0, 0, -30)},
+	}
+	for _, p := range periods {
+		totals, err := db.GetSalesTotals(p.since)
+		if err != nil {
+			return m, err
+		}
+
+		*p.period = salesPeriod{Since: p.since, Revenue: map[string]int{}}
+		for _, t := range totals {
+			p.period.Orders += t.Orders
+			p.period.Refunds += t.Refunds
+			p.period.Revenue[t.Currency] += t.Revenue
+		}
+		if p.period.Orders > 0 {
+			p.period.RefundRate = float64(p.period.Refunds) / float64(p.period.Orders)
+		}
+	}
+
+	subscriptions, err := db.GetSubscriptionTotals()
+	if err != nil {
+		return m, err
+	}
+	for _, t := range subscriptions {
+		m.ActiveSubscriptions += t.Active
+		m.MRR[t.Currency] += t.MRR
+	}
+
+	return m, nil
+}
+
+// SalesMetrics returns revenue, order counts and refund rates for today and the last 7 and 30
+// days, with the active subscriptions and the monthly recurring revenue
+func (server *Server) SalesMetrics(w http.ResponseWriter, r *http.Request) {
+	metrics, err := getSalesMetrics(server.DB, time.Now())
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	_ = server.writeJSON(w, http.StatusOK, metrics)
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetSalesMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockmetricsReader(ctrl)
+	now := time.Date(2026, 3, 10, 15, 30, 0, 0, time.UTC)
+
+	mockDB.EXPECT().GetSalesTotals(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)).Return(nil, nil)
+	mockDB.EXPECT().GetSalesTotals(time.Date(2026, 3, 3, 15, 30, 0, 0, time.UTC)).Return([]models.SalesTotal{
+		{Currency: "usd", Orders: 4, Refunds: 1, Revenue: 5000},
+	}, nil)
+	mockDB.EXPECT().GetSalesTotals(time.Date(2026, 2, 8, 15, 30, 0, 0, time.UTC)).Return([]models.SalesTotal{
+		{Currency: "eur", Orders: 2, Refunds: 0, Revenue: 3000},
+		{Currency: "usd", Orders: 8, Refunds: 2, Revenue: 11000},
+	}, nil)
+	mockDB.EXPECT().GetSubscriptionTotals().Return([]models.SubscriptionTotal{
+		{Currency: "usd", Active: 3, MRR: 6000},
+	}, nil)
+
+	m, err := getSalesMetrics(mockDB, now)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if m.Today.Orders != 0 || m.Today.RefundRate != 0 || len(m.Today.Revenue) != 0 {
+		t.Errorf("expected a day without orders to be empty, got %+v", m.Today)
+	}
+	if m.Last7Days.Orders != 4 || m.Last7Days.RefundRate != 0.25 || m.Last7Days.Revenue["usd"] != 5000 {
+		t.Errorf("unexpected last 7 days %+v", m.Last7Days)
+	}
+	if m.Last30Days.Orders != 10 || m.Last30Days.Refunds != 2 || m.Last30Days.RefundRate != 0.2 {
+		t.Errorf("unexpected last 30 days %+v", m.Last30Days)
+	}
+	if m.Last30Days.Revenue["eur"] != 3000 || m.Last30Days.Revenue["usd"] != 11000 {
+		t.Errorf("expected revenue to be kept per currency, got %v", m.Last30Days.Revenue)
+	}
+	if m.ActiveSubscriptions != 3 || m.MRR["usd"] != 6000 {
+		t.Errorf("unexpected subscriptions %d, %v", m.ActiveSubscriptions, m.MRR)
+	}
+}
+
+func TestGetSalesMetricsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockmetricsReader(ctrl)
+	mockErr := errors.New("query failed")
+	mockDB.EXPECT().GetSalesTotals(gomock.Any()).Return(nil, mockErr)
+
+	if _, err := getSalesMetrics(mockDB, time.Now()); !errors.Is(err, mockErr) {
+		t.Fatalf("expected %v, got %v", mockErr, err)
+	}
+}
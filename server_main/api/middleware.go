@@ -1,14 +1,104 @@
 package api
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+	"strings"
 
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+)
+
+type contextKey string
+
+const (
+	userContextKey   = contextKey("user")
+	apiKeyContextKey = contextKey("api_key")
+)
+
+// Auth accepts either the auth token of an admin user or an API key as the bearer token
 func (server *Server) Auth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		_, err := server.authenticateToken(r)
+		token, err := server.bearerToken(r)
 		if err != nil {
 			_ = server.invalidCredentials(w)
 			return
 		}
+
+		var ctx context.Context
+		if strings.HasPrefix(token, models.APIKeyPrefix) {
+			key, err := server.authenticateAPIKey(r)
+			if err != nil {
+				_ = server.invalidCredentials(w)
+				return
+			}
+			ctx = context.WithValue(r.Context(), apiKeyContextKey, key)
+		} else {
+			user, err := server.authenticateToken(r)
+			if err != nil {
+				_ = server.invalidCredentials(w)
+				return
+			}
+			if !user.EmailVerified {
+				_ = server.emailNotVerified(w)
+				return
+			}
+			if user.Role != models.RoleAdmin {
+				_ = server.forbidden(w)
+				return
+			}
+			ctx = context.WithValue(r.Context(), userContextKey, user)
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequireScope lets requests authenticated by a user through, and requests authenticated
+// by an API key only if the key carries scope. It must run after Auth.
+func (server *Server) RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if server.authenticatedUser(r) != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := server.authenticatedAPIKey(r)
+			if key == nil || !key.HasScope(scope) {
+				_ = server.forbidden(w)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireUser only lets requests authenticated by a user through. It must run after Auth.
+func (server *Server) RequireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if server.authenticatedUser(r) == nil {
+			_ = server.forbidden(w)
+			return
+		}
 		next.ServeHTTP(w, r)
 	})
 }
+
+// authenticatedUser returns the user set on the request by Auth, or nil
+func (server *Server) authenticatedUser(r *http.Request) *models.User {
+	user, ok := r.Context().Value(userContextKey).(*models.User)
+	if !ok {
+		return nil
+	}
+	return user
+}
+
+// authenticatedAPIKey returns the API key set on the request by Auth, or nil
+func (server *Server) authenticatedAPIKey(r *http.Request) *models.APIKey {
+	key, ok := r.Context().Value(apiKeyContextKey).(*models.APIKey)
+	if !ok {
+		return nil
+	}
+	return key
+}
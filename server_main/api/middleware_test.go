@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+)
+
+func TestRequireScope(t *testing.T) {
+	server := &Server{}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := server.RequireScope(models.ScopeSalesRead)(next)
+
+	tests := []struct {
+		name string
+		key  contextKey
+		val  interface{}
+		want int
+	}{
+		{"user", userContextKey, &models.User{ID: 1}, http.StatusOK},
+		{"key with scope", apiKeyContextKey, &models.APIKey{Scopes: []string{models.ScopeSalesRead}}, http.StatusOK},
+		{"key without scope", apiKeyContextKey, &models.APIKey{Scopes: []string{models.ScopeRefundsWrite}}, http.StatusForbidden},
+		{"nobody", contextKey("other"), nil, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/api/v1/admin/all-sales", nil)
+		r = r.WithContext(context.WithValue(r.Context(), tt.key, tt.val))
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, r)
+
+		if w.Code != tt.want {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.want, w.Code)
+		}
+	}
+}
+
+func TestRequireUser(t *testing.T) {
+	server := &Server{}
+	handler := server.RequireUser(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	r := httptest.NewRequest("GET", "/api/v1/admin/api-keys", nil)
+	key := &models.APIKey{Scopes: models.APIKeyScopes}
+	r = r.WithContext(context.WithValue(r.Context(), apiKeyContextKey, key))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected api key to be forbidden, got %d", w.Code)
+	}
+}
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/LamThanhNguyen/yoyo-store-backend/server_main/api (interfaces: customerInserter,orderInserter,transactionInserter)
+// Source: github.com/LamThanhNguyen/yoyo-store-backend/server_main/api (interfaces: auditEventInserter,customerInserter,eventPublisher,invoiceOutbox,metricsReader,orderInserter,passwordResetInserter,paymentFailureGate,planSyncer,slugChecker,transactionInserter)
 //
 // Generated by this command:
 //
-//	mockgen -package api -destination server_main/api/mock_interfaces_test.go github.com/LamThanhNguyen/yoyo-store-backend/server_main/api customerInserter,orderInserter,transactionInserter
+//	mockgen -package api -destination server_main/api/mock_interfaces_test.go github.com/LamThanhNguyen/yoyo-store-backend/server_main/api auditEventInserter,customerInserter,eventPublisher,invoiceOutbox,metricsReader,orderInserter,passwordResetInserter,paymentFailureGate,planSyncer,slugChecker,transactionInserter
 //
 
 // Package api is a generated GoMock package.
@@ -11,11 +11,53 @@ package api
 
 import (
 	reflect "reflect"
+	time "time"
 
+	cards "github.com/LamThanhNguyen/yoyo-store-backend/internal/cards"
+	events "github.com/LamThanhNguyen/yoyo-store-backend/internal/events"
 	models "github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	throttle "github.com/LamThanhNguyen/yoyo-store-backend/internal/throttle"
 	gomock "go.uber.org/mock/gomock"
 )
 
+// MockauditEventInserter is a mock of auditEventInserter interface.
+type MockauditEventInserter struct {
+	ctrl     *gomock.Controller
+	recorder *MockauditEventInserterMockRecorder
+	isgomock struct{}
+}
+
+// MockauditEventInserterMockRecorder is the mock recorder for MockauditEventInserter.
+type MockauditEventInserterMockRecorder struct {
+	mock *MockauditEventInserter
+}
+
+// NewMockauditEventInserter creates a new mock instance.
+func NewMockauditEventInserter(ctrl *gomock.Controller) *MockauditEventInserter {
+	mock := &MockauditEventInserter{ctrl: ctrl}
+	mock.recorder = &MockauditEventInserterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockauditEventInserter) EXPECT() *MockauditEventInserterMockRecorder {
+	return m.recorder
+}
+
+// InsertAuditEvent mocks base method.
+func (m *MockauditEventInserter) InsertAuditEvent(arg0 models.AuditEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAuditEvent", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertAuditEvent indicates an expected call of InsertAuditEvent.
+func (mr *MockauditEventInserterMockRecorder) InsertAuditEvent(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuditEvent", reflect.TypeOf((*MockauditEventInserter)(nil).InsertAuditEvent), arg0)
+}
+
 // MockcustomerInserter is a mock of customerInserter interface.
 type MockcustomerInserter struct {
 	ctrl     *gomock.Controller
@@ -55,6 +97,179 @@ func (mr *MockcustomerInserterMockRecorder) InsertCustomer(arg0 any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertCustomer", reflect.TypeOf((*MockcustomerInserter)(nil).InsertCustomer), arg0)
 }
 
+// MockeventPublisher is a mock of eventPublisher interface.
+type MockeventPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockeventPublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockeventPublisherMockRecorder is the mock recorder for MockeventPublisher.
+type MockeventPublisherMockRecorder struct {
+	mock *MockeventPublisher
+}
+
+// NewMockeventPublisher creates a new mock instance.
+func NewMockeventPublisher(ctrl *gomock.Controller) *MockeventPublisher {
+	mock := &MockeventPublisher{ctrl: ctrl}
+	mock.recorder = &MockeventPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockeventPublisher) EXPECT() *MockeventPublisherMockRecorder {
+	return m.recorder
+}
+
+// PublishEvent mocks base method.
+func (m *MockeventPublisher) PublishEvent(arg0 events.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishEvent", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishEvent indicates an expected call of PublishEvent.
+func (mr *MockeventPublisherMockRecorder) PublishEvent(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishEvent", reflect.TypeOf((*MockeventPublisher)(nil).PublishEvent), arg0)
+}
+
+// MockinvoiceOutbox is a mock of invoiceOutbox interface.
+type MockinvoiceOutbox struct {
+	ctrl     *gomock.Controller
+	recorder *MockinvoiceOutboxMockRecorder
+	isgomock struct{}
+}
+
+// MockinvoiceOutboxMockRecorder is the mock recorder for MockinvoiceOutbox.
+type MockinvoiceOutboxMockRecorder struct {
+	mock *MockinvoiceOutbox
+}
+
+// NewMockinvoiceOutbox creates a new mock instance.
+func NewMockinvoiceOutbox(ctrl *gomock.Controller) *MockinvoiceOutbox {
+	mock := &MockinvoiceOutbox{ctrl: ctrl}
+	mock.recorder = &MockinvoiceOutboxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockinvoiceOutbox) EXPECT() *MockinvoiceOutboxMockRecorder {
+	return m.recorder
+}
+
+// ClaimInvoiceOutbox mocks base method.
+func (m *MockinvoiceOutbox) ClaimInvoiceOutbox(limit int, lease time.Duration) ([]*models.InvoiceOutboxEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimInvoiceOutbox", limit, lease)
+	ret0, _ := ret[0].([]*models.InvoiceOutboxEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimInvoiceOutbox indicates an expected call of ClaimInvoiceOutbox.
+func (mr *MockinvoiceOutboxMockRecorder) ClaimInvoiceOutbox(limit, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimInvoiceOutbox", reflect.TypeOf((*MockinvoiceOutbox)(nil).ClaimInvoiceOutbox), limit, lease)
+}
+
+// DeadLetterInvoiceOutbox mocks base method.
+func (m *MockinvoiceOutbox) DeadLetterInvoiceOutbox(id int, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeadLetterInvoiceOutbox", id, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeadLetterInvoiceOutbox indicates an expected call of DeadLetterInvoiceOutbox.
+func (mr *MockinvoiceOutboxMockRecorder) DeadLetterInvoiceOutbox(id, lastError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeadLetterInvoiceOutbox", reflect.TypeOf((*MockinvoiceOutbox)(nil).DeadLetterInvoiceOutbox), id, lastError)
+}
+
+// MarkInvoiceOutboxDelivered mocks base method.
+func (m *MockinvoiceOutbox) MarkInvoiceOutboxDelivered(id int, invoiceNumber string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkInvoiceOutboxDelivered", id, invoiceNumber)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkInvoiceOutboxDelivered indicates an expected call of MarkInvoiceOutboxDelivered.
+func (mr *MockinvoiceOutboxMockRecorder) MarkInvoiceOutboxDelivered(id, invoiceNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkInvoiceOutboxDelivered", reflect.TypeOf((*MockinvoiceOutbox)(nil).MarkInvoiceOutboxDelivered), id, invoiceNumber)
+}
+
+// RescheduleInvoiceOutbox mocks base method.
+func (m *MockinvoiceOutbox) RescheduleInvoiceOutbox(id int, lastError string, nextAttemptAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RescheduleInvoiceOutbox", id, lastError, nextAttemptAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RescheduleInvoiceOutbox indicates an expected call of RescheduleInvoiceOutbox.
+func (mr *MockinvoiceOutboxMockRecorder) RescheduleInvoiceOutbox(id, lastError, nextAttemptAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RescheduleInvoiceOutbox", reflect.TypeOf((*MockinvoiceOutbox)(nil).RescheduleInvoiceOutbox), id, lastError, nextAttemptAt)
+}
+
+// MockmetricsReader is a mock of metricsReader interface.
+type MockmetricsReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockmetricsReaderMockRecorder
+	isgomock struct{}
+}
+
+// MockmetricsReaderMockRecorder is the mock recorder for MockmetricsReader.
+type MockmetricsReaderMockRecorder struct {
+	mock *MockmetricsReader
+}
+
+// NewMockmetricsReader creates a new mock instance.
+func NewMockmetricsReader(ctrl *gomock.Controller) *MockmetricsReader {
+	mock := &MockmetricsReader{ctrl: ctrl}
+	mock.recorder = &MockmetricsReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockmetricsReader) EXPECT() *MockmetricsReaderMockRecorder {
+	return m.recorder
+}
+
+// GetSalesTotals mocks base method.
+func (m *MockmetricsReader) GetSalesTotals(since time.Time) ([]models.SalesTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSalesTotals", since)
+	ret0, _ := ret[0].([]models.SalesTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSalesTotals indicates an expected call of GetSalesTotals.
+func (mr *MockmetricsReaderMockRecorder) GetSalesTotals(since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSalesTotals", reflect.TypeOf((*MockmetricsReader)(nil).GetSalesTotals), since)
+}
+
+// GetSubscriptionTotals mocks base method.
+func (m *MockmetricsReader) GetSubscriptionTotals() ([]models.SubscriptionTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptionTotals")
+	ret0, _ := ret[0].([]models.SubscriptionTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscriptionTotals indicates an expected call of GetSubscriptionTotals.
+func (mr *MockmetricsReaderMockRecorder) GetSubscriptionTotals() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptionTotals", reflect.TypeOf((*MockmetricsReader)(nil).GetSubscriptionTotals))
+}
+
 // MockorderInserter is a mock of orderInserter interface.
 type MockorderInserter struct {
 	ctrl     *gomock.Controller
@@ -94,6 +309,219 @@ func (mr *MockorderInserterMockRecorder) InsertOrder(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOrder", reflect.TypeOf((*MockorderInserter)(nil).InsertOrder), arg0)
 }
 
+// MockpasswordResetInserter is a mock of passwordResetInserter interface.
+type MockpasswordResetInserter struct {
+	ctrl     *gomock.Controller
+	recorder *MockpasswordResetInserterMockRecorder
+	isgomock struct{}
+}
+
+// MockpasswordResetInserterMockRecorder is the mock recorder for MockpasswordResetInserter.
+type MockpasswordResetInserterMockRecorder struct {
+	mock *MockpasswordResetInserter
+}
+
+// NewMockpasswordResetInserter creates a new mock instance.
+func NewMockpasswordResetInserter(ctrl *gomock.Controller) *MockpasswordResetInserter {
+	mock := &MockpasswordResetInserter{ctrl: ctrl}
+	mock.recorder = &MockpasswordResetInserterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpasswordResetInserter) EXPECT() *MockpasswordResetInserterMockRecorder {
+	return m.recorder
+}
+
+// InsertPasswordReset mocks base method.
+func (m *MockpasswordResetInserter) InsertPasswordReset(t *models.Token) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertPasswordReset", t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertPasswordReset indicates an expected call of InsertPasswordReset.
+func (mr *MockpasswordResetInserterMockRecorder) InsertPasswordReset(t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertPasswordReset", reflect.TypeOf((*MockpasswordResetInserter)(nil).InsertPasswordReset), t)
+}
+
+// MockpaymentFailureGate is a mock of paymentFailureGate interface.
+type MockpaymentFailureGate struct {
+	ctrl     *gomock.Controller
+	recorder *MockpaymentFailureGateMockRecorder
+	isgomock struct{}
+}
+
+// MockpaymentFailureGateMockRecorder is the mock recorder for MockpaymentFailureGate.
+type MockpaymentFailureGateMockRecorder struct {
+	mock *MockpaymentFailureGate
+}
+
+// NewMockpaymentFailureGate creates a new mock instance.
+func NewMockpaymentFailureGate(ctrl *gomock.Controller) *MockpaymentFailureGate {
+	mock := &MockpaymentFailureGate{ctrl: ctrl}
+	mock.recorder = &MockpaymentFailureGateMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpaymentFailureGate) EXPECT() *MockpaymentFailureGateMockRecorder {
+	return m.recorder
+}
+
+// EmailIsVerified mocks base method.
+func (m *MockpaymentFailureGate) EmailIsVerified(email string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmailIsVerified", email)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EmailIsVerified indicates an expected call of EmailIsVerified.
+func (mr *MockpaymentFailureGateMockRecorder) EmailIsVerified(email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmailIsVerified", reflect.TypeOf((*MockpaymentFailureGate)(nil).EmailIsVerified), email)
+}
+
+// GetAuthLockout mocks base method.
+func (m *MockpaymentFailureGate) GetAuthLockout(action string, keys []throttle.Key) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthLockout", action, keys)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthLockout indicates an expected call of GetAuthLockout.
+func (mr *MockpaymentFailureGateMockRecorder) GetAuthLockout(action, keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthLockout", reflect.TypeOf((*MockpaymentFailureGate)(nil).GetAuthLockout), action, keys)
+}
+
+// RecordAuthFailure mocks base method.
+func (m *MockpaymentFailureGate) RecordAuthFailure(action, ip string, keys []throttle.Key) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAuthFailure", action, ip, keys)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordAuthFailure indicates an expected call of RecordAuthFailure.
+func (mr *MockpaymentFailureGateMockRecorder) RecordAuthFailure(action, ip, keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAuthFailure", reflect.TypeOf((*MockpaymentFailureGate)(nil).RecordAuthFailure), action, ip, keys)
+}
+
+// MockplanSyncer is a mock of planSyncer interface.
+type MockplanSyncer struct {
+	ctrl     *gomock.Controller
+	recorder *MockplanSyncerMockRecorder
+	isgomock struct{}
+}
+
+// MockplanSyncerMockRecorder is the mock recorder for MockplanSyncer.
+type MockplanSyncerMockRecorder struct {
+	mock *MockplanSyncer
+}
+
+// NewMockplanSyncer creates a new mock instance.
+func NewMockplanSyncer(ctrl *gomock.Controller) *MockplanSyncer {
+	mock := &MockplanSyncer{ctrl: ctrl}
+	mock.recorder = &MockplanSyncerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockplanSyncer) EXPECT() *MockplanSyncerMockRecorder {
+	return m.recorder
+}
+
+// CommitPlan mocks base method.
+func (m *MockplanSyncer) CommitPlan(arg0 cards.PlanChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommitPlan", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CommitPlan indicates an expected call of CommitPlan.
+func (mr *MockplanSyncerMockRecorder) CommitPlan(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitPlan", reflect.TypeOf((*MockplanSyncer)(nil).CommitPlan), arg0)
+}
+
+// RollbackPlan mocks base method.
+func (m *MockplanSyncer) RollbackPlan(arg0 cards.PlanChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackPlan", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollbackPlan indicates an expected call of RollbackPlan.
+func (mr *MockplanSyncerMockRecorder) RollbackPlan(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackPlan", reflect.TypeOf((*MockplanSyncer)(nil).RollbackPlan), arg0)
+}
+
+// SyncPlan mocks base method.
+func (m *MockplanSyncer) SyncPlan(arg0 cards.Plan) (cards.Plan, cards.PlanChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncPlan", arg0)
+	ret0, _ := ret[0].(cards.Plan)
+	ret1, _ := ret[1].(cards.PlanChange)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SyncPlan indicates an expected call of SyncPlan.
+func (mr *MockplanSyncerMockRecorder) SyncPlan(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncPlan", reflect.TypeOf((*MockplanSyncer)(nil).SyncPlan), arg0)
+}
+
+// MockslugChecker is a mock of slugChecker interface.
+type MockslugChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockslugCheckerMockRecorder
+	isgomock struct{}
+}
+
+// MockslugCheckerMockRecorder is the mock recorder for MockslugChecker.
+type MockslugCheckerMockRecorder struct {
+	mock *MockslugChecker
+}
+
+// NewMockslugChecker creates a new mock instance.
+func NewMockslugChecker(ctrl *gomock.Controller) *MockslugChecker {
+	mock := &MockslugChecker{ctrl: ctrl}
+	mock.recorder = &MockslugCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockslugChecker) EXPECT() *MockslugCheckerMockRecorder {
+	return m.recorder
+}
+
+// ItemSlugTaken mocks base method.
+func (m *MockslugChecker) ItemSlugTaken(slug string, exceptID int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ItemSlugTaken", slug, exceptID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ItemSlugTaken indicates an expected call of ItemSlugTaken.
+func (mr *MockslugCheckerMockRecorder) ItemSlugTaken(slug, exceptID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ItemSlugTaken", reflect.TypeOf((*MockslugChecker)(nil).ItemSlugTaken), slug, exceptID)
+}
+
 // MocktransactionInserter is a mock of transactionInserter interface.
 type MocktransactionInserter struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/i18n"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/throttle"
+	"github.com/rs/zerolog/log"
+)
+
+// notification is the data of the order, refund, cancellation and payment failure emails
+type notification struct {
+	FirstName string
+	OrderID   int
+	Product   string
+	Amount    int
+	Currency  string
+	LastFour  string
+	EndsAt    time.Time
+	Reason    string
+	Link      string
+}
+
+// notify emails a customer with one of the notification templates, in their language. The
+// subject is taken from the catalog, e.g. email.refund_issued.subject for refund-issued.
+// Failures are logged rather than returned, since the change the email reports on has
+// already been made.
+func (server *Server) notify(to, tmpl, locale string, data notification) {
+	key := "email." + strings.ReplaceAll(tmpl, "-", "_") + ".subject"
+	subject := i18n.For(locale).T(key)
+
+	err := server.SendMail("info@yoyo.com", to, subject, tmpl, locale, data)
+	if err != nil {
+		log.Error().Err(err).Str("template", tmpl).Msg("notify")
+	}
+}
+
+// renderNotification renders a notification queued by a service without the templates, such
+// as the order confirmations of the frontend, when the mail dispatcher sends it
+func (server *Server) renderNotification(tmpl, locale string, data json.RawMessage) (string, string, error) {
+	var n notification
+	if err := json.Unmarshal(data, &n); err != nil {
+		return "", "", err
+	}
+
+	return server.templates.Render(tmpl, locale, n)
+}
+
+// paymentFailureGate is what decides whether a payment failure email may be sent. Having this
+// interface allows the use of gomock in tests.
+type paymentFailureGate interface {
+	EmailIsVerified(email string) (bool, error)
+	GetAuthLockout(action string, keys []throttle.Key) (time.Time, error)
+	RecordAuthFailure(action, ip string, keys []throttle.Key) error
+}
+
+// mayNotifyPaymentFailure reports whether the payment failure email may go to email, for a
+// request from ip, and if so counts it. Anyone can make a payment fail with any address, so the
+// email only goes to verified addresses, and no more often than throttle.Notification allows
+// per address and throttle.IP per client.
+func mayNotifyPaymentFailure(db paymentFailureGate, email, ip string) bool {
+	keys := []throttle.Key{throttle.NotificationKey(email), throttle.IPKey(ip)}
+
+	// fail closed: a missed email is better than one sent to a stranger
+	lockedUntil, err := db.GetAuthLockout(throttle.ActionPaymentFailedEmail, keys)
+	if err != nil {
+		log.Error().Err(err).Msg("mayNotifyPaymentFailure")
+		return false
+	}
+	if !lockedUntil.IsZero() {
+		return false
+	}
+
+	verified, err := db.EmailIsVerified(email)
+	if err != nil {
+		log.Error().Err(err).Msg("mayNotifyPaymentFailure")
+		return false
+	}
+	if !verified {
+		return false
+	}
+
+	if err := db.RecordAuthFailure(throttle.ActionPaymentFailedEmail, ip, keys); err != nil {
+		log.Error().Err(err).Msg("mayNotifyPaymentFailure")
+		return false
+	}
+
+	return true
+}
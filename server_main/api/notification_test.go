@@ -0,0 +1,72 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/throttle"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMayNotifyPaymentFailure(t *testing.T) {
+	email, ip := "Jane@Example.com", "10.0.0.1"
+	keys := []throttle.Key{throttle.NotificationKey(email), throttle.IPKey(ip)}
+
+	tests := []struct {
+		name   string
+		expect func(db *MockpaymentFailureGate)
+		want   bool
+	}{
+		{
+			name: "verified address",
+			expect: func(db *MockpaymentFailureGate) {
+				db.EXPECT().GetAuthLockout(throttle.ActionPaymentFailedEmail, keys).Return(time.Time{}, nil)
+				db.EXPECT().EmailIsVerified(email).Return(true, nil)
+				db.EXPECT().RecordAuthFailure(throttle.ActionPaymentFailedEmail, ip, keys).Return(nil)
+			},
+			want: true,
+		},
+		{
+			name: "unverified address",
+			expect: func(db *MockpaymentFailureGate) {
+				db.EXPECT().GetAuthLockout(throttle.ActionPaymentFailedEmail, keys).Return(time.Time{}, nil)
+				db.EXPECT().EmailIsVerified(email).Return(false, nil)
+			},
+		},
+		{
+			name: "recently notified",
+			expect: func(db *MockpaymentFailureGate) {
+				db.EXPECT().GetAuthLockout(throttle.ActionPaymentFailedEmail, keys).Return(time.Now().Add(time.Hour), nil)
+			},
+		},
+		{
+			name: "lockout lookup fails",
+			expect: func(db *MockpaymentFailureGate) {
+				db.EXPECT().GetAuthLockout(throttle.ActionPaymentFailedEmail, keys).Return(time.Time{}, errors.New("db down"))
+			},
+		},
+		{
+			name: "count fails",
+			expect: func(db *MockpaymentFailureGate) {
+				db.EXPECT().GetAuthLockout(throttle.ActionPaymentFailedEmail, keys).Return(time.Time{}, nil)
+				db.EXPECT().EmailIsVerified(email).Return(true, nil)
+				db.EXPECT().RecordAuthFailure(throttle.ActionPaymentFailedEmail, ip, keys).Return(errors.New("db down"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			db := NewMockpaymentFailureGate(ctrl)
+			tt.expect(db)
+
+			if got := mayNotifyPaymentFailure(db, email, ip); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -3,15 +3,20 @@ package api
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/LamThanhNguyen/yoyo-store-backend/internal/cards"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/events"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/i18n"
 	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/throttle"
 	"github.com/LamThanhNguyen/yoyo-store-backend/internal/validator"
 	"github.com/rs/zerolog/log"
 	"github.com/stripe/stripe-go/v82"
+	"google.golang.org/grpc/status"
 )
 
 type stripePayload struct {
@@ -27,6 +32,7 @@ type stripePayload struct {
 	ProductID     string `json:"product_id"`
 	FirstName     string `json:"first_name"`
 	LastName      string `json:"last_name"`
+	Locale        string `json:"locale"`
 }
 
 func (server *Server) GetPaymentIntent(w http.ResponseWriter, r *http.Request) {
@@ -114,6 +120,15 @@ func (server *Server) CreateCustomerAndSubscribeToPlan(w http.ResponseWriter, r
 	okay := true
 	var subscription *stripe.Subscription
 	txnMsg := "Transaction successful"
+	product := "Bronze Plan monthly subscription"
+	amount, _ := strconv.Atoi(data.Amount)
+
+	// invoices and emails are written in the language the customer picked, or the one
+	// their browser asks for
+	locale := i18n.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+	if data.Locale != "" {
+		locale = i18n.Match(data.Locale)
+	}
 
 	stripeCustomer, msg, err := card.CreateCustomer(data.PaymentMethod, data.Email)
 	if err != nil {
@@ -122,6 +137,18 @@ func (server *Server) CreateCustomerAndSubscribeToPlan(w http.ResponseWriter, r
 		txnMsg = msg
 	}
 
+	// the buyer's country is required on e-invoices, but not to subscribe; without it the
+	// invoice service leaves it out of the e-invoice
+	var country string
+	if okay {
+		pm, err := card.GetPaymentMethod(data.PaymentMethod)
+		if err != nil {
+			log.Error().Err(err).Msg("CreateCustomerAndSubscribeToPlan: reading the billing country")
+		} else {
+			country = cards.BillingCountry(pm)
+		}
+	}
+
 	if okay {
 		subscription, err = card.SubscribeToPlan(stripeCustomer, data.Plan, data.Email, data.LastFour, "")
 		if err != nil {
@@ -133,15 +160,13 @@ func (server *Server) CreateCustomerAndSubscribeToPlan(w http.ResponseWriter, r
 
 	if okay {
 		productID, _ := strconv.Atoi(data.ProductID)
-		customerID, err := server.SaveCustomer(data.FirstName, data.LastName, data.Email)
+		customerID, err := server.SaveCustomer(data.FirstName, data.LastName, data.Email, locale)
 		if err != nil {
 			log.Error().Err(err).Msg("CreateCustomerAndSubscribeToPlan")
 			return
 		}
 
 		// create a new txn
-		amount, _ := strconv.Atoi(data.Amount)
-
 		txn := models.Transaction{
 			Amount:              amount,
 			Currency:            "usd",
@@ -167,32 +192,66 @@ func (server *Server) CreateCustomerAndSubscribeToPlan(w http.ResponseWriter, r
 			StatusID:      1,
 			Quantity:      1,
 			Amount:        amount,
+			Locale:        locale,
 			CreatedAt:     time.Now(),
 			UpdatedAt:     time.Now(),
 		}
 
-		orderID, err := server.SaveOrder(order)
-		if err != nil {
-			log.Error().Err(err).Msg("CreateCustomerAndSubscribeToPlan")
-			return
-		}
-
-		inv := Invoice{
-			ID:        orderID,
+		invoice := models.InvoiceRequest{
 			Amount:    2000,
-			Product:   "Bronze Plan monthly subscription",
+			Product:   product,
 			Quantity:  order.Quantity,
+			Currency:  "usd",
+			CardBrand: data.CardBrand,
+			LastFour:  data.LastFour,
+			Country:   country,
+			Locale:    locale,
 			FirstName: data.FirstName,
 			LastName:  data.LastName,
 			Email:     data.Email,
 			CreatedAt: time.Now(),
 		}
 
-		err = server.callInvoiceMicro(inv)
+		// the invoice shows what Stripe charged: the plan, less any coupon, plus any tax
+		if subscription.LatestInvoice != nil {
+			adj := cards.Adjustments(subscription.LatestInvoice)
+			invoice.Amount = adj.Subtotal
+			invoice.Discount = adj.Discount
+			invoice.DiscountLabel = adj.DiscountLabel
+			invoice.TaxRateBps = adj.TaxRateBps
+		}
+
+		// the invoice is requested in the same transaction as the order, and delivered by the dispatcher
+		orderID, err := server.DB.InsertOrderWithInvoiceRequest(order, invoice)
 		if err != nil {
 			log.Error().Err(err).Msg("CreateCustomerAndSubscribeToPlan")
 			return
 		}
+
+		server.publish(events.Event{
+			Type:     events.SaleCreated,
+			OrderID:  orderID,
+			Product:  product,
+			Amount:   amount,
+			Currency: "usd",
+		})
+
+		server.notify(data.Email, "order-confirmation", locale, notification{
+			FirstName: data.FirstName,
+			OrderID:   orderID,
+			Product:   product,
+			Amount:    amount,
+			Currency:  "usd",
+		})
+	} else if data.Email != "" && mayNotifyPaymentFailure(server.DB, data.Email, throttle.ClientIP(r)) {
+		server.notify(data.Email, "payment-failed", locale, notification{
+			FirstName: data.FirstName,
+			Product:   product,
+			Amount:    amount,
+			Currency:  "usd",
+			Reason:    txnMsg,
+			Link:      fmt.Sprintf("%s/plans/bronze", server.config.FrontendAddr),
+		})
 	}
 
 	resp := jsonResponse{
@@ -267,12 +326,15 @@ func (server *Server) VirtualTerminalPaymentSucceeded(w http.ResponseWriter, r *
 		TransactionStatusID: 2,
 	}
 
-	_, err = server.SaveTransaction(txn)
+	txnID, err := server.SaveTransaction(txn)
 	if err != nil {
 		_ = server.badRequest(w, r, err)
 		return
 	}
 
+	txn.ID = txnID
+	server.audit(r, "transaction.virtual_terminal", "transaction", txnID, nil, txn)
+
 	_ = server.writeJSON(w, http.StatusOK, txn)
 }
 
@@ -283,6 +345,7 @@ func (server *Server) RefundCharge(w http.ResponseWriter, r *http.Request) {
 		PaymentIntent string `json:"pi"`
 		Amount        int    `json:"amount"`
 		Currency      string `json:"currency"`
+		Reason        string `json:"reason"`
 	}
 
 	err := server.readJSON(w, r, &chargeToRefund)
@@ -297,7 +360,13 @@ func (server *Server) RefundCharge(w http.ResponseWriter, r *http.Request) {
 		Currency: chargeToRefund.Currency,
 	}
 
-	err = card.Refund(chargeToRefund.PaymentIntent, chargeToRefund.Amount)
+	before, err := server.DB.GetOrderByID(chargeToRefund.ID)
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	refundID, err := card.Refund(chargeToRefund.PaymentIntent, chargeToRefund.Amount)
 	if err != nil {
 		_ = server.badRequest(w, r, err)
 		return
@@ -310,13 +379,46 @@ func (server *Server) RefundCharge(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	after := before
+	after.StatusID = 2
+	server.audit(r, "order.refund", "order", chargeToRefund.ID, before, after)
+
 	var resp struct {
-		Error   bool   `json:"error"`
-		Message string `json:"message"`
+		Error            bool   `json:"error"`
+		Message          string `json:"message"`
+		CreditNoteNumber string `json:"credit_note_number,omitempty"`
 	}
 	resp.Error = false
 	resp.Message = "Charge refunded"
 
+	// the refund has gone through either way, so a missing credit note is reported but not fatal
+	number, err := server.creditRefund(r.Context(), chargeToRefund.ID, chargeToRefund.Amount, chargeToRefund.Reason, refundID)
+	if err != nil {
+		log.Error().Err(err).Int("order_id", chargeToRefund.ID).Str("refund_id", refundID).Msg("RefundCharge")
+		resp.Message = fmt.Sprintf("Charge refunded, but the credit note could not be issued: %s", status.Convert(err).Message())
+	} else {
+		resp.CreditNoteNumber = number
+	}
+
+	currency := chargeToRefund.Currency
+	if currency == "" {
+		currency = before.Transaction.Currency
+	}
+	server.publish(events.Event{
+		Type:     events.RefundIssued,
+		OrderID:  before.ID,
+		Product:  before.Item.Name,
+		Amount:   chargeToRefund.Amount,
+		Currency: currency,
+	})
+	server.notify(before.Customer.Email, "refund-issued", before.Locale, notification{
+		FirstName: before.Customer.FirstName,
+		OrderID:   before.ID,
+		Amount:    chargeToRefund.Amount,
+		Currency:  currency,
+		LastFour:  before.Transaction.LastFour,
+	})
+
 	_ = server.writeJSON(w, http.StatusOK, resp)
 }
 
@@ -340,7 +442,13 @@ func (server *Server) CancelSubscription(w http.ResponseWriter, r *http.Request)
 		Currency: subToCancel.Currency,
 	}
 
-	err = card.CancelSubscription(subToCancel.PaymentIntent)
+	before, err := server.DB.GetOrderByID(subToCancel.ID)
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	endsAt, err := card.CancelSubscription(subToCancel.PaymentIntent)
 	if err != nil {
 		_ = server.badRequest(w, r, err)
 		return
@@ -353,6 +461,22 @@ func (server *Server) CancelSubscription(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	after := before
+	after.StatusID = 3
+	server.audit(r, "subscription.cancel", "order", subToCancel.ID, before, after)
+	server.publish(events.Event{
+		Type:    events.SubscriptionCancelled,
+		OrderID: before.ID,
+		Product: before.Item.Name,
+	})
+
+	server.notify(before.Customer.Email, "subscription-cancelled", before.Locale, notification{
+		FirstName: before.Customer.FirstName,
+		OrderID:   before.ID,
+		Product:   before.Item.Name,
+		EndsAt:    endsAt,
+	})
+
 	var resp struct {
 		Error   bool   `json:"error"`
 		Message string `json:"message"`
@@ -1,40 +1,122 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"maps"
+	"math"
 	"net/http"
+	"net/netip"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/grpcauth"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/mailer"
 	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/passwords"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/storage"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/throttle"
 	"github.com/LamThanhNguyen/yoyo-store-backend/server_main/util"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
 )
 
 type Server struct {
-	config util.Config
-	DB     *models.DBModel
-	router http.Handler
+	config          util.Config
+	DB              *models.DBModel
+	passwordPolicy  passwords.Policy
+	invoiceDialOpts []grpc.DialOption
+	proxies         []netip.Prefix
+	mail            *mailer.Outbox
+	templates       *mailer.Templates
+	uploads         storage.Store
+	router          http.Handler
+
+	// auditFailures counts the audit events that could not be recorded since start up
+	auditFailures atomic.Int64
 }
 
 func NewServer(
 	config util.Config,
 	db *models.DBModel,
 ) (*Server, error) {
+	var policy passwords.Policy
+	if config.PasswordMinLength != "" {
+		minLength, err := strconv.Atoi(config.PasswordMinLength)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PASSWORD_MIN_LENGTH: %w", err)
+		}
+		policy.MinLength = minLength
+	}
+
+	proxies, err := throttle.ParseProxies(config.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+
+	invoiceDialOpts, err := grpcauth.DialOptions(
+		config.Environment,
+		grpcauth.TLSFiles{
+			CAFile:   config.InvoiceTlsCaFile,
+			CertFile: config.InvoiceTlsCertFile,
+			KeyFile:  config.InvoiceTlsKeyFile,
+		},
+		"server_main",
+		config.InvoiceServiceSecret,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invoice service credentials: %w", err)
+	}
+
+	transport, err := mailer.New(mailer.Config{
+		Driver:       config.MailDriver,
+		Dir:          config.MailDir,
+		SMTPHost:     config.SmtpHost,
+		SMTPPort:     config.SmtpPort,
+		SMTPUsername: config.SmtpUsername,
+		SMTPPassword: config.SmtpPassword,
+		SMTPPoolSize: config.SmtpPoolSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	uploads, err := storage.New(ctx, config.UploadStorageConfig())
+	if err != nil {
+		return nil, fmt.Errorf("upload storage: %w", err)
+	}
 
-	return &Server{
-		config: config,
-		DB:     db,
-	}, nil
+	server := &Server{
+		config:          config,
+		DB:              db,
+		passwordPolicy:  policy,
+		invoiceDialOpts: invoiceDialOpts,
+		proxies:         proxies,
+		mail:            mailer.NewOutbox(db, transport),
+		templates:       mailer.NewTemplates(emailTemplateFS, "templates", db),
+		uploads:         uploads,
+	}
+	server.mail.RenderWith(server.renderQueued)
+
+	return server, nil
 }
 
 func (server *Server) SetupRouter() {
 	mux := chi.NewRouter()
 
+	mux.Use(throttle.RealIP(server.proxies))
+	mux.Use(middleware.RequestID)
 	mux.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   server.config.AllowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
@@ -46,6 +128,8 @@ func (server *Server) SetupRouter() {
 
 	mux.Get("/api/v1/health", server.handleHealthCheck)
 	mux.Post("/api/v1/payment-intent", server.GetPaymentIntent)
+	mux.Get("/api/v1/items", server.Catalog)
+	mux.Get("/api/v1/items/categories", server.ItemCategories)
 	mux.Get("/api/v1/items/{id}", server.GetItemByID)
 	mux.Post("/api/v1/create-customer-and-subscribe-to-plan", server.CreateCustomerAndSubscribeToPlan)
 
@@ -58,19 +142,60 @@ func (server *Server) SetupRouter() {
 	mux.Route("/api/v1/admin", func(mux chi.Router) {
 		mux.Use(server.Auth)
 
-		mux.Post("/virtual-terminal-succeeded", server.VirtualTerminalPaymentSucceeded)
-		mux.Get("/all-sales", server.AllSales)
-		mux.Get("/all-subscriptions", server.AllSubscriptions)
-
-		mux.Get("/get-sale/{id}", server.GetSale)
-
-		mux.Post("/refund", server.RefundCharge)
-		mux.Post("/cancel-subscription", server.CancelSubscription)
-
-		mux.Get("/all-users", server.AllUsers)
-		mux.Get("/all-users/{id}", server.OneUser)
-		mux.Patch("/all-users/edit/{id}", server.EditUser)
-		mux.Delete("/all-users/delete/{id}", server.DeleteUser)
+		mux.With(server.RequireScope(models.ScopeTransactionsWrite)).Post("/virtual-terminal-succeeded", server.VirtualTerminalPaymentSucceeded)
+		mux.With(server.RequireScope(models.ScopeSalesRead)).Get("/all-sales", server.AllSales)
+		mux.With(server.RequireScope(models.ScopeSalesRead)).Get("/all-subscriptions", server.AllSubscriptions)
+
+		mux.With(server.RequireScope(models.ScopeSalesRead)).Get("/get-sale/{id}", server.GetSale)
+		mux.With(server.RequireScope(models.ScopeSalesRead)).Get("/metrics", server.SalesMetrics)
+
+		mux.With(server.RequireScope(models.ScopeRefundsWrite)).Post("/refund", server.RefundCharge)
+		mux.With(server.RequireScope(models.ScopeSubscriptionsWrite)).Post("/cancel-subscription", server.CancelSubscription)
+
+		mux.With(server.RequireScope(models.ScopeUsersRead)).Get("/all-users", server.AllUsers)
+		mux.With(server.RequireScope(models.ScopeUsersRead)).Get("/all-users/{id}", server.OneUser)
+		mux.With(server.RequireScope(models.ScopeUsersWrite)).Patch("/all-users/edit/{id}", server.EditUser)
+		mux.With(server.RequireScope(models.ScopeUsersWrite)).Delete("/all-users/delete/{id}", server.DeleteUser)
+		mux.With(server.RequireScope(models.ScopeUsersWrite)).Post("/all-users/{id}/resend-verification", server.ResendVerificationEmail)
+
+		mux.With(server.RequireScope(models.ScopeAuditRead)).Get("/audit", server.AuditEvents)
+
+		mux.With(server.RequireScope(models.ScopeItemsRead)).Get("/items", server.AllItems)
+		mux.With(server.RequireScope(models.ScopeItemsRead)).Get("/items/{id}", server.OneItem)
+		mux.With(server.RequireScope(models.ScopeItemsWrite)).Post("/items", server.CreateItem)
+		mux.With(server.RequireScope(models.ScopeItemsWrite)).Put("/items/{id}", server.UpdateItem)
+		mux.With(server.RequireScope(models.ScopeItemsWrite)).Delete("/items/{id}", server.DeleteItem)
+		mux.With(server.RequireScope(models.ScopeItemsWrite)).Post("/items/{id}/image", server.UploadItemImage)
+
+		mux.With(server.RequireScope(models.ScopeInvoicesRead)).Get("/invoices", server.AllInvoices)
+		mux.With(server.RequireScope(models.ScopeInvoicesRead)).Get("/invoices/{number}/pdf", server.InvoicePDF)
+		mux.With(server.RequireScope(models.ScopeInvoicesRead)).Get("/invoices/{number}/xml", server.InvoiceXML)
+		mux.With(server.RequireScope(models.ScopeInvoicesWrite)).Post("/invoices/{number}/resend", server.ResendInvoice)
+		mux.With(server.RequireScope(models.ScopeInvoicesWrite)).Post("/invoices/{number}/void", server.VoidInvoice)
+		mux.With(server.RequireScope(models.ScopeInvoicesWrite)).Post("/invoices/{number}/credit-notes", server.CreateCreditNote)
+		mux.With(server.RequireScope(models.ScopeInvoicesRead)).Get("/invoice-outbox", server.InvoiceOutbox)
+		mux.With(server.RequireScope(models.ScopeInvoicesWrite)).Post("/invoice-outbox/{id}/retry", server.RetryInvoiceOutbox)
+
+		// test emails go to the user asking for them, and edits are recorded against them, so these
+		// are for users only
+		mux.Route("/email-templates", func(mux chi.Router) {
+			mux.Use(server.RequireUser)
+			mux.Get("/", server.AllEmailTemplates)
+			mux.Get("/{service}/{name}", server.PreviewEmailTemplate)
+			mux.Post("/{service}/{name}/test", server.SendTestEmail)
+			mux.Get("/{service}/{name}/source", server.EmailTemplateSource)
+			mux.Get("/{service}/{name}/versions", server.EmailTemplateVersions)
+			mux.Post("/{service}/{name}/versions", server.SaveEmailTemplate)
+			mux.Post("/{service}/{name}/versions/{version}/restore", server.RestoreEmailTemplate)
+		})
+
+		// api keys can only be managed by users, never by other api keys
+		mux.Route("/api-keys", func(mux chi.Router) {
+			mux.Use(server.RequireUser)
+			mux.Get("/", server.AllAPIKeys)
+			mux.Post("/", server.CreateAPIKey)
+			mux.Delete("/{id}", server.RevokeAPIKey)
+		})
 	})
 
 	server.router = mux
@@ -196,33 +321,102 @@ func (server *Server) invalidCredentials(w http.ResponseWriter) error {
 	return nil
 }
 
-func (server *Server) passwordMatches(hash, password string) (bool, error) {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+// forbidden sends a JSON response with status http.StatusForbidden
+func (server *Server) forbidden(w http.ResponseWriter) error {
+	var payload struct {
+		Error   bool   `json:"error"`
+		Message string `json:"message"`
+	}
+
+	payload.Error = true
+	payload.Message = "you do not have permission to perform this action"
+
+	err := server.writeJSON(w, http.StatusForbidden, payload)
 	if err != nil {
-		switch {
-		case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
-			return false, nil
-		default:
-			return false, err
-		}
+		return err
 	}
+	return nil
+}
+
+// serverError logs err and sends a JSON response with status http.StatusInternalServerError,
+// without the details of err
+func (server *Server) serverError(w http.ResponseWriter, r *http.Request, err error) error {
+	log.Error().Err(err).Str("path", r.URL.Path).Msg("serverError")
 
-	return true, nil
+	var payload struct {
+		Error   bool   `json:"error"`
+		Message string `json:"message"`
+	}
+
+	payload.Error = true
+	payload.Message = "the server could not process your request"
+
+	err = server.writeJSON(w, http.StatusInternalServerError, payload)
+	if err != nil {
+		return err
+	}
+	return nil
 }
 
-// authenticateToken checks an auth token for validity
-func (server *Server) authenticateToken(r *http.Request) (*models.User, error) {
+// tooManyRequests sends a JSON response with status http.StatusTooManyRequests,
+// telling the client when it may try again
+func (server *Server) tooManyRequests(w http.ResponseWriter, retryAfter time.Duration) error {
+	var payload struct {
+		Error   bool   `json:"error"`
+		Message string `json:"message"`
+	}
+
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+
+	payload.Error = true
+	payload.Message = fmt.Sprintf("too many attempts, please try again in %d seconds", seconds)
+
+	headers := make(http.Header)
+	headers.Set("Retry-After", strconv.Itoa(seconds))
+
+	err := server.writeJSON(w, http.StatusTooManyRequests, payload, headers)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// passwordMatches checks password against hash, and reports whether the hash should be upgraded
+func (server *Server) passwordMatches(hash, password string) (bool, bool, error) {
+	return passwords.Verify(password, hash)
+}
+
+// hashPassword checks password against the password policy, and hashes it
+func (server *Server) hashPassword(password string) (string, error) {
+	if err := server.passwordPolicy.Check(password); err != nil {
+		return "", err
+	}
+
+	return passwords.Hash(password)
+}
+
+// bearerToken returns the token from the request's Authorization header
+func (server *Server) bearerToken(r *http.Request) (string, error) {
 	authorizationHeader := r.Header.Get("Authorization")
 	if authorizationHeader == "" {
-		return nil, errors.New("no authorization header received")
+		return "", errors.New("no authorization header received")
 	}
 
 	headerParts := strings.Split(authorizationHeader, " ")
 	if len(headerParts) != 2 || headerParts[0] != "Bearer" {
-		return nil, errors.New("no authorization header received")
+		return "", errors.New("no authorization header received")
+	}
+
+	return headerParts[1], nil
+}
+
+// authenticateToken checks an auth token for validity
+func (server *Server) authenticateToken(r *http.Request) (*models.User, error) {
+	token, err := server.bearerToken(r)
+	if err != nil {
+		return nil, err
 	}
 
-	token := headerParts[1]
 	if len(token) != 26 {
 		return nil, errors.New("authentication token wrong size")
 	}
@@ -236,7 +430,29 @@ func (server *Server) authenticateToken(r *http.Request) (*models.User, error) {
 	return user, nil
 }
 
+// authenticateAPIKey checks an API key for validity
+func (server *Server) authenticateAPIKey(r *http.Request) (*models.APIKey, error) {
+	token, err := server.bearerToken(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(token, models.APIKeyPrefix) {
+		return nil, errors.New("not an api key")
+	}
+
+	key, err := server.DB.GetAPIKeyForToken(token)
+	if err != nil {
+		return nil, errors.New("no matching api key found")
+	}
+
+	return key, nil
+}
+
 func (server *Server) handleHealthCheck(w http.ResponseWriter, r *http.Request) {
-	var data = map[string]string{"status": "ok"}
+	var data = map[string]interface{}{
+		"status":         "ok",
+		"audit_failures": server.auditFailures.Load(),
+	}
 	_ = server.writeJSON(w, http.StatusOK, data)
 }
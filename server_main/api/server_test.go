@@ -8,14 +8,17 @@ import (
 
 func TestPasswordMatches(t *testing.T) {
 	hash, _ := bcrypt.GenerateFromPassword([]byte("secret"), 12)
-	match, err := (&Server{}).passwordMatches(string(hash), "secret")
+	match, needsRehash, err := (&Server{}).passwordMatches(string(hash), "secret")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if !match {
 		t.Fatal("expected passwords to match")
 	}
-	match, _ = (&Server{}).passwordMatches(string(hash), "wrong")
+	if !needsRehash {
+		t.Fatal("expected bcrypt hash to need a rehash")
+	}
+	match, _, _ = (&Server{}).passwordMatches(string(hash), "wrong")
 	if match {
 		t.Fatal("expected passwords not to match")
 	}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/throttle"
+	"github.com/rs/zerolog/log"
+)
+
+// lockedOut reports whether any of keys is locked out for action, and if so
+// writes a http.StatusTooManyRequests response
+func (server *Server) lockedOut(w http.ResponseWriter, action string, keys []throttle.Key) bool {
+	lockedUntil, err := server.DB.GetAuthLockout(action, keys)
+	if err != nil {
+		// fail open: a database hiccup should not lock everybody out
+		log.Error().Err(err).Msg("lockedOut")
+		return false
+	}
+
+	if lockedUntil.IsZero() {
+		return false
+	}
+
+	_ = server.tooManyRequests(w, time.Until(lockedUntil))
+	return true
+}
+
+// recordAuthFailure counts a failed attempt against keys
+func (server *Server) recordAuthFailure(action, ip string, keys []throttle.Key) {
+	if err := server.DB.RecordAuthFailure(action, ip, keys); err != nil {
+		log.Error().Err(err).Msg("recordAuthFailure")
+	}
+}
@@ -1,18 +1,21 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
-	"github.com/LamThanhNguyen/yoyo-store-backend/internal/encryption"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/events"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/i18n"
 	"github.com/LamThanhNguyen/yoyo-store-backend/internal/models"
-	"github.com/LamThanhNguyen/yoyo-store-backend/internal/urlsigner"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/passwords"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/throttle"
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // CreateAuthToken creates and sends an auth token, if user supplies valid information
@@ -28,25 +31,53 @@ func (server *Server) CreateAuthToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ip := throttle.ClientIP(r)
+	keys := throttle.Keys(userInput.Email, ip)
+
+	if server.lockedOut(w, throttle.ActionLogin, keys) {
+		return
+	}
+
 	// get the user from the database by email; send error if invalid email
 	user, err := server.DB.GetUserByEmail(userInput.Email)
 	if err != nil {
+		server.recordAuthFailure(throttle.ActionLogin, ip, keys)
 		_ = server.invalidCredentials(w)
 		return
 	}
 
 	// validate the password; send error if invalid password
-	validPassword, err := server.passwordMatches(user.Password, userInput.Password)
+	validPassword, needsRehash, err := server.passwordMatches(user.Password, userInput.Password)
 	if err != nil {
+		server.recordAuthFailure(throttle.ActionLogin, ip, keys)
 		_ = server.invalidCredentials(w)
 		return
 	}
 
 	if !validPassword {
+		server.recordAuthFailure(throttle.ActionLogin, ip, keys)
 		_ = server.invalidCredentials(w)
 		return
 	}
 
+	if err := server.DB.ClearAuthFailures(throttle.ActionLogin, throttle.AccountKey(userInput.Email)); err != nil {
+		log.Error().Err(err).Msg("CreateAuthToken")
+	}
+
+	if !user.EmailVerified {
+		_ = server.emailNotVerified(w)
+		return
+	}
+
+	// upgrade bcrypt or outdated argon2id hashes while we have the plain text password
+	if needsRehash {
+		if newHash, err := passwords.Hash(userInput.Password); err != nil {
+			log.Error().Err(err).Msg("CreateAuthToken")
+		} else if err := server.DB.UpdatePasswordForUser(user, newHash); err != nil {
+			log.Error().Err(err).Msg("CreateAuthToken")
+		}
+	}
+
 	// generate the token
 	token, err := models.GenerateToken(user.ID, 24*time.Hour, models.ScopeAuthentication)
 	if err != nil {
@@ -95,7 +126,8 @@ func (server *Server) CheckAuthentication(w http.ResponseWriter, r *http.Request
 	_ = server.writeJSON(w, http.StatusOK, payload)
 }
 
-// SendPasswordResetEmail sends an email with a signed url to allow user to reset password
+// SendPasswordResetEmail sends an email with a single-use link to allow user to reset password.
+// The response is the same whether or not the email belongs to an account.
 func (server *Server) SendPasswordResetEmail(w http.ResponseWriter, r *http.Request) {
 	var payload struct {
 		Email string `json:"email"`
@@ -107,55 +139,94 @@ func (server *Server) SendPasswordResetEmail(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	_, err = server.DB.GetUserByEmail(payload.Email)
+	ip := throttle.ClientIP(r)
+	keys := throttle.Keys(payload.Email, ip)
 
-	if err != nil {
-		var resp struct {
-			Error   bool   `json:"error"`
-			Message string `json:"message"`
-		}
-		resp.Error = true
-		resp.Message = "No matching email found on our system"
-		_ = server.writeJSON(w, http.StatusAccepted, resp)
+	if server.lockedOut(w, throttle.ActionPasswordReset, keys) {
 		return
 	}
 
-	link := fmt.Sprintf("%s/reset-password?email=%s", server.config.FrontendAddr, payload.Email)
+	// every request counts towards the limit, whether or not the account exists
+	server.recordAuthFailure(throttle.ActionPasswordReset, ip, keys)
 
-	sign := urlsigner.Signer{
-		Secret: []byte(server.config.TokenSymmetricKey),
+	// send in the background, so the response time does not reveal whether the account exists
+	go server.sendPasswordResetLink(payload.Email, i18n.FromAcceptLanguage(r.Header.Get("Accept-Language")))
+
+	var resp struct {
+		Error   bool   `json:"error"`
+		Message string `json:"message"`
 	}
 
-	signedLink := sign.GenerateTokenFromString(link)
+	resp.Error = false
+	resp.Message = "If the email address is registered, a password reset link has been sent to it"
 
-	var data struct {
-		Link string
-	}
+	_ = server.writeJSON(w, http.StatusAccepted, resp)
+}
 
-	data.Link = signedLink
+// passwordResetEmail is what a password reset email is queued with; the token is issued when it is sent
+type passwordResetEmail struct {
+	UserID int
+}
 
-	// send mail
-	err = server.SendMail("info@yoyo.com", payload.Email, "Password Reset Request", "password-reset", data)
+// sendPasswordResetLink queues a password reset email for the user with email, if there is one,
+// in the language given by locale
+func (server *Server) sendPasswordResetLink(email, locale string) {
+	user, err := server.DB.GetUserByEmail(email)
 	if err != nil {
-		log.Error().Err(err).Msg("SendPasswordResetEmail")
-		_ = server.badRequest(w, r, err)
+		log.Info().Msg("password reset requested for unknown email")
 		return
 	}
 
-	var resp struct {
-		Error   bool   `json:"error"`
-		Message string `json:"message"`
+	subject := i18n.For(locale).T("email.password_reset.subject")
+	err = server.queueMail("info@yoyo.com", user.Email, subject, "password-reset", locale, passwordResetEmail{UserID: user.ID})
+	if err != nil {
+		log.Error().Err(err).Msg("sendPasswordResetLink")
 	}
+}
 
-	resp.Error = false
+// renderPasswordReset renders a queued password reset email with a new reset token. A retried
+// email gets another token; the unused ones expire.
+func (server *Server) renderPasswordReset(locale string, data json.RawMessage) (string, string, error) {
+	var p passwordResetEmail
+	if err := json.Unmarshal(data, &p); err != nil {
+		return "", "", err
+	}
 
-	_ = server.writeJSON(w, http.StatusCreated, resp)
+	link, err := passwordResetLink(server.DB, server.config.FrontendAddr, p.UserID)
+	if err != nil {
+		return "", "", err
+	}
+
+	return server.templates.Render("password-reset", locale, struct {
+		Link string
+	}{link})
+}
+
+// passwordResetInserter is what stores password reset tokens. Having this interface allows
+// the use of gomock in tests.
+type passwordResetInserter interface {
+	InsertPasswordReset(t *models.Token) error
+}
+
+// passwordResetLink issues a password reset token for userID, valid for an hour, and returns
+// the frontend link that uses it. Only the hash of the token is stored.
+func passwordResetLink(db passwordResetInserter, frontendAddr string, userID int) (string, error) {
+	token, err := models.GenerateToken(userID, 60*time.Minute, models.ScopePasswordReset)
+	if err != nil {
+		return "", err
+	}
+
+	if err := db.InsertPasswordReset(token); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s/reset-password?token=%s", frontendAddr, token.PlainText), nil
 }
 
-// ResetPassword resets a user's password in the database
+// ResetPassword consumes a password reset token and sets the user's new password
 func (server *Server) ResetPassword(w http.ResponseWriter, r *http.Request) {
 	var payload struct {
-		Email    string `json:"email"`
+		Token    string `json:"token"`
 		Password string `json:"password"`
 	}
 
@@ -165,32 +236,32 @@ func (server *Server) ResetPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	encyrptor := encryption.Encryption{
-		Key: []byte(server.config.TokenSymmetricKey),
-	}
-
-	realEmail, err := encyrptor.Decrypt(payload.Email)
+	newHash, err := server.hashPassword(payload.Password)
 	if err != nil {
 		_ = server.badRequest(w, r, err)
 		return
 	}
 
-	user, err := server.DB.GetUserByEmail(realEmail)
+	user, err := server.DB.ResetPasswordWithToken(payload.Token, newHash)
 	if err != nil {
 		_ = server.badRequest(w, r, err)
 		return
 	}
 
-	newHash, err := bcrypt.GenerateFromPassword([]byte(payload.Password), 12)
-	if err != nil {
-		_ = server.badRequest(w, r, err)
-		return
+	// let the account owner know, in case they did not make this change
+	var data struct {
+		FirstName string
+		Link      string
 	}
 
-	err = server.DB.UpdatePasswordForUser(user, string(newHash))
+	data.FirstName = user.FirstName
+	data.Link = fmt.Sprintf("%s/forgot-password", server.config.FrontendAddr)
+
+	locale := i18n.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+	subject := i18n.For(locale).T("email.password_changed.subject")
+	err = server.SendMail("info@yoyo.com", user.Email, subject, "password-changed", locale, data)
 	if err != nil {
-		_ = server.badRequest(w, r, err)
-		return
+		log.Error().Err(err).Msg("ResetPassword")
 	}
 
 	var resp struct {
@@ -270,17 +341,26 @@ func (server *Server) CreateUser(w http.ResponseWriter, r *http.Request) {
 		_ = server.badRequest(w, r, err)
 		return
 	}
-	newHash, err := bcrypt.GenerateFromPassword([]byte(user.Password), 12)
+	newHash, err := server.hashPassword(user.Password)
 	if err != nil {
 		_ = server.badRequest(w, r, err)
 		return
 	}
-	err = server.DB.AddUser(user, string(newHash))
+	newID, err := server.DB.AddUser(user, newHash)
 	if err != nil {
 		_ = server.badRequest(w, r, err)
 		return
 	}
 
+	user.ID = newID
+	user.Password = ""
+	server.audit(r, "user.create", "user", newID, nil, user)
+
+	// the account stays unverified until the link is clicked
+	if err := server.sendVerificationEmail(newID, user.FirstName, user.Email); err != nil {
+		log.Error().Err(err).Msg("CreateUser")
+	}
+
 	var resp struct {
 		Error   bool   `json:"error"`
 		Message string `json:"message"`
@@ -303,36 +383,77 @@ func (server *Server) EditUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// never write the plain text password to the audit log
+	password := user.Password
+	user.Password = ""
+
+	// check a new password against the policy before changing anything
+	var newHash string
+	if password != "" || userID == 0 {
+		newHash, err = server.hashPassword(password)
+		if err != nil {
+			_ = server.badRequest(w, r, err)
+			return
+		}
+	}
+
 	if userID > 0 {
+		before, err := server.DB.GetOneUser(userID)
+		if err != nil {
+			_ = server.badRequest(w, r, err)
+			return
+		}
+
 		err = server.DB.EditUser(user)
 		if err != nil {
 			_ = server.badRequest(w, r, err)
 			return
 		}
 
-		if user.Password != "" {
-			newHash, err := bcrypt.GenerateFromPassword([]byte(user.Password), 12)
+		after := before
+		after.FirstName = user.FirstName
+		after.LastName = user.LastName
+		server.audit(r, "user.update", "user", userID, before, after)
+
+		// a new email address only replaces the current one once it is verified
+		if !strings.EqualFold(user.Email, before.Email) {
+			err = server.DB.SetPendingEmail(userID, user.Email)
 			if err != nil {
 				_ = server.badRequest(w, r, err)
 				return
 			}
 
-			err = server.DB.UpdatePasswordForUser(user, string(newHash))
+			server.audit(r, "user.email_change_requested", "user", userID,
+				map[string]string{"email": before.Email},
+				map[string]string{"pending_email": user.Email})
+
+			if err := server.sendVerificationEmail(userID, before.FirstName, user.Email); err != nil {
+				log.Error().Err(err).Msg("EditUser")
+			}
+		}
+
+		if password != "" {
+			err = server.DB.UpdatePasswordForUser(user, newHash)
 			if err != nil {
 				_ = server.badRequest(w, r, err)
 				return
 			}
+
+			server.audit(r, "user.password_change", "user", userID, nil, nil)
 		}
 	} else {
-		newHash, err := bcrypt.GenerateFromPassword([]byte(user.Password), 12)
+		newID, err := server.DB.AddUser(user, newHash)
 		if err != nil {
 			_ = server.badRequest(w, r, err)
 			return
 		}
-		err = server.DB.AddUser(user, string(newHash))
-		if err != nil {
-			_ = server.badRequest(w, r, err)
-			return
+
+		user.ID = newID
+		server.audit(r, "user.create", "user", newID, nil, user)
+
+		// the account stays unverified until the link is clicked
+		if err := server.sendVerificationEmail(newID, user.FirstName, user.Email); err != nil {
+			log.Error().Err(err).Msg("EditUser")
 		}
 	}
 
@@ -350,12 +471,22 @@ func (server *Server) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	userID, _ := strconv.Atoi(id)
 
-	err := server.DB.DeleteUser(userID)
+	before, err := server.DB.GetOneUser(userID)
 	if err != nil {
 		_ = server.badRequest(w, r, err)
 		return
 	}
 
+	err = server.DB.DeleteUser(userID)
+	if err != nil {
+		_ = server.badRequest(w, r, err)
+		return
+	}
+
+	server.audit(r, "user.delete", "user", userID, before, nil)
+	// the user's open pages, on whichever frontend they are, are logged out
+	server.publish(events.Event{Type: events.UserDeleted, UserID: userID})
+
 	var resp struct {
 		Error   bool   `json:"error"`
 		Message string `json:"message"`
@@ -36,7 +36,7 @@ func main() {
 		log.Fatal().Err(err).Msg("cannot load config")
 	}
 
-	log.Info().Interface("config", config).Msg("loaded config")
+	log.Info().Interface("config", config.Redacted()).Msg("loaded config")
 
 	// connPool, err := pgxpool.New(ctx, runtimeCfg.DBSource)
 	connPool, err := sql.Open("pgx", config.DBSource)
@@ -97,6 +97,18 @@ func runServer(
 		WriteTimeout:      30 * time.Second,
 	}
 
+	// Deliver queued invoice requests in the background
+	waitGroup.Go(func() error {
+		log.Info().Msg("start invoice dispatcher")
+		return server.RunInvoiceDispatcher(ctx)
+	})
+
+	// Send queued mail in the background
+	waitGroup.Go(func() error {
+		log.Info().Msg("start mail dispatcher")
+		return server.RunMailDispatcher(ctx)
+	})
+
 	// Start HTTP server in goroutine
 	waitGroup.Go(func() error {
 		log.Info().Msgf("start HTTP server at %s", httpServer.Addr)
@@ -8,6 +8,8 @@ import (
 	"os"
 	"strings"
 
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/redact"
+	"github.com/LamThanhNguyen/yoyo-store-backend/internal/storage"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
@@ -15,20 +17,68 @@ import (
 )
 
 type Config struct {
-	Environment       string   `mapstructure:"ENVIRONMENT" json:"ENVIRONMENT"`
-	AllowedOrigins    []string `mapstructure:"ALLOWED_ORIGINS" json:"ALLOWED_ORIGINS"`
-	DBSource          string   `mapstructure:"DB_SOURCE" json:"DB_SOURCE"`
-	MigrationURL      string   `mapstructure:"MIGRATION_URL" json:"MIGRATION_URL"`
-	MainServerPort    string   `mapstructure:"MAIN_SERVER_PORT" json:"MAIN_SERVER_PORT"`
-	InvoiceGrpcAddr   string   `mapstructure:"INVOICE_GRPC_ADDR" json:"INVOICE_GRPC_ADDR"`
-	TokenSymmetricKey string   `mapstructure:"TOKEN_SYMMETRIC_KEY" json:"TOKEN_SYMMETRIC_KEY"`
-	SmtpHost          string   `mapstructure:"SMTP_HOST" json:"SMTP_HOST"`
-	SmtpPort          string   `mapstructure:"SMTP_PORT" json:"SMTP_PORT"`
-	SmtpUsername      string   `mapstructure:"SMTP_USERNAME" json:"SMTP_USERNAME"`
-	SmtpPassword      string   `mapstructure:"SMTP_PASSWORD" json:"SMTP_PASSWORD"`
-	FrontendAddr      string   `mapstructure:"FRONTEND_ADDR" json:"FRONTEND_ADDR"`
-	StripeKey         string   `mapstructure:"STRIPE_KEY" json:"STRIPE_KEY"`
-	StripeSecret      string   `mapstructure:"STRIPE_SECRET" json:"STRIPE_SECRET"`
+	Environment          string   `mapstructure:"ENVIRONMENT" json:"ENVIRONMENT"`
+	AllowedOrigins       []string `mapstructure:"ALLOWED_ORIGINS" json:"ALLOWED_ORIGINS"`
+	TrustedProxies       []string `mapstructure:"TRUSTED_PROXIES" json:"TRUSTED_PROXIES"`
+	DBSource             string   `mapstructure:"DB_SOURCE" json:"DB_SOURCE"`
+	MigrationURL         string   `mapstructure:"MIGRATION_URL" json:"MIGRATION_URL"`
+	MainServerPort       string   `mapstructure:"MAIN_SERVER_PORT" json:"MAIN_SERVER_PORT"`
+	InvoiceGrpcAddr      string   `mapstructure:"INVOICE_GRPC_ADDR" json:"INVOICE_GRPC_ADDR"`
+	InvoiceTlsCaFile     string   `mapstructure:"INVOICE_TLS_CA_FILE" json:"INVOICE_TLS_CA_FILE"`
+	InvoiceTlsCertFile   string   `mapstructure:"INVOICE_TLS_CERT_FILE" json:"INVOICE_TLS_CERT_FILE"`
+	InvoiceTlsKeyFile    string   `mapstructure:"INVOICE_TLS_KEY_FILE" json:"INVOICE_TLS_KEY_FILE"`
+	InvoiceServiceSecret string   `mapstructure:"INVOICE_SERVICE_SECRET" json:"INVOICE_SERVICE_SECRET"`
+	TokenSymmetricKey    string   `mapstructure:"TOKEN_SYMMETRIC_KEY" json:"TOKEN_SYMMETRIC_KEY"`
+	SmtpHost             string   `mapstructure:"SMTP_HOST" json:"SMTP_HOST"`
+	SmtpPort             string   `mapstructure:"SMTP_PORT" json:"SMTP_PORT"`
+	SmtpUsername         string   `mapstructure:"SMTP_USERNAME" json:"SMTP_USERNAME"`
+	SmtpPassword         string   `mapstructure:"SMTP_PASSWORD" json:"SMTP_PASSWORD"`
+	SmtpPoolSize         string   `mapstructure:"SMTP_POOL_SIZE" json:"SMTP_POOL_SIZE"`
+	MailDriver           string   `mapstructure:"MAIL_DRIVER" json:"MAIL_DRIVER"`
+	MailDir              string   `mapstructure:"MAIL_DIR" json:"MAIL_DIR"`
+	FrontendAddr         string   `mapstructure:"FRONTEND_ADDR" json:"FRONTEND_ADDR"`
+	StripeKey            string   `mapstructure:"STRIPE_KEY" json:"STRIPE_KEY"`
+	StripeSecret         string   `mapstructure:"STRIPE_SECRET" json:"STRIPE_SECRET"`
+	PasswordMinLength    string   `mapstructure:"PASSWORD_MIN_LENGTH" json:"PASSWORD_MIN_LENGTH"`
+	UploadStorage        string   `mapstructure:"UPLOAD_STORAGE" json:"UPLOAD_STORAGE"`
+	UploadStorageDir     string   `mapstructure:"UPLOAD_STORAGE_DIR" json:"UPLOAD_STORAGE_DIR"`
+	UploadS3Bucket       string   `mapstructure:"UPLOAD_S3_BUCKET" json:"UPLOAD_S3_BUCKET"`
+	UploadS3Region       string   `mapstructure:"UPLOAD_S3_REGION" json:"UPLOAD_S3_REGION"`
+	UploadS3Endpoint     string   `mapstructure:"UPLOAD_S3_ENDPOINT" json:"UPLOAD_S3_ENDPOINT"`
+	UploadS3AccessKey    string   `mapstructure:"UPLOAD_S3_ACCESS_KEY" json:"UPLOAD_S3_ACCESS_KEY"`
+	UploadS3SecretKey    string   `mapstructure:"UPLOAD_S3_SECRET_KEY" json:"UPLOAD_S3_SECRET_KEY"`
+}
+
+// UploadStorageConfig returns the configuration of the store uploaded files, such as item
+// images, are kept in
+func (config Config) UploadStorageConfig() storage.Config {
+	dir := config.UploadStorageDir
+	if dir == "" {
+		dir = "./uploads"
+	}
+
+	return storage.Config{
+		Driver: config.UploadStorage,
+		Dir:    dir,
+		S3: storage.S3Config{
+			Bucket:    config.UploadS3Bucket,
+			Region:    config.UploadS3Region,
+			Endpoint:  config.UploadS3Endpoint,
+			AccessKey: config.UploadS3AccessKey,
+			SecretKey: config.UploadS3SecretKey,
+		},
+	}
+}
+
+// Redacted returns config with its secrets masked, so it can be logged
+func (config Config) Redacted() Config {
+	config.DBSource = redact.URL(config.DBSource)
+	config.InvoiceServiceSecret = redact.Secret(config.InvoiceServiceSecret)
+	config.TokenSymmetricKey = redact.Secret(config.TokenSymmetricKey)
+	config.SmtpPassword = redact.Secret(config.SmtpPassword)
+	config.StripeSecret = redact.Secret(config.StripeSecret)
+	config.UploadS3SecretKey = redact.Secret(config.UploadS3SecretKey)
+	return config
 }
 
 // LoadConfig reads configuration from file or environment variables.
@@ -52,6 +102,7 @@ func LoadConfig(ctx context.Context, path string) (Config, error) {
 		if err != nil {
 			return config, fmt.Errorf("viper unmarshal error: %w", err)
 		}
+		config.Environment = environment
 		return config, nil
 
 	case "staging", "production":
@@ -72,6 +123,7 @@ func LoadConfig(ctx context.Context, path string) (Config, error) {
 		if err != nil {
 			return config, fmt.Errorf("unmarshal secret: %w", err)
 		}
+		config.Environment = environment
 		return config, nil
 
 	default: